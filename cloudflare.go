@@ -1,4 +1,11 @@
 // Package cloudflare implements the Cloudflare v4 API.
+//
+// Every method taking a context aborts its request in flight when the
+// context is cancelled or its deadline passes, returning an error wrapping
+// the context's error, and doesn't retry it. Cancelling a request that
+// changes something doesn't undo it: the API may have applied the change
+// before the request was aborted, so read the resource back before retrying
+// a create that isn't idempotent.
 package cloudflare
 
 import (
@@ -10,6 +17,7 @@ import (
 	"io/ioutil"
 	"log"
 	"math"
+	"math/rand"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -52,7 +60,11 @@ type API struct {
 	authType          int
 	rateLimiter       *rate.Limiter
 	retryPolicy       RetryPolicy
+	requestTimeout    time.Duration
 	logger            Logger
+	requestLogger     RequestLogger
+	teamsCatalogCache *teamsCatalogCache
+	asnPrefixResolver ASNPrefixResolver
 	Debug             bool
 }
 
@@ -187,10 +199,19 @@ func (api *API) makeRequestWithAuthType(ctx context.Context, method, uri string,
 }
 
 func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, uri string, params interface{}, authType int, headers http.Header) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok && api.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, api.requestTimeout)
+		defer cancel()
+	}
+
 	var err error
 	var resp *http.Response
 	var respErr error
 	var respBody []byte
+	var retryAfter time.Duration
+	// the request body as sent, kept for the request logger
+	var reqBodyBytes []byte
 	for i := 0; i <= api.retryPolicy.MaxRetries; i++ {
 		var reqBody io.Reader
 		if params != nil {
@@ -198,6 +219,7 @@ func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, u
 				reqBody = r
 			} else if paramBytes, ok := params.([]byte); ok {
 				reqBody = bytes.NewReader(paramBytes)
+				reqBodyBytes = paramBytes
 			} else {
 				var jsonBody []byte
 				jsonBody, err = json.Marshal(params)
@@ -205,6 +227,7 @@ func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, u
 					return nil, fmt.Errorf("error marshalling params to JSON: %w", err)
 				}
 				reqBody = bytes.NewReader(jsonBody)
+				reqBodyBytes = jsonBody
 			}
 		}
 
@@ -217,6 +240,16 @@ func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, u
 			if sleepDuration > api.retryPolicy.MaxRetryDelay {
 				sleepDuration = api.retryPolicy.MaxRetryDelay
 			}
+			// the server knows best how long we need to wait before trying again
+			if retryAfter > sleepDuration {
+				sleepDuration = retryAfter
+			}
+			if api.retryPolicy.Jitter > 0 {
+				sleepDuration += time.Duration(rand.Int63n(int64(api.retryPolicy.Jitter)))
+			}
+			if deadline, ok := ctx.Deadline(); ok && time.Now().Add(sleepDuration).After(deadline) {
+				return nil, fmt.Errorf("operation aborted before backoff of %s: %w", sleepDuration.String(), context.DeadlineExceeded)
+			}
 			// useful to do some simple logging here, maybe introduce levels later
 			api.logger.Printf("Sleeping %s before retry attempt number %d for request %s %s", sleepDuration.String(), i, method, uri)
 
@@ -248,12 +281,29 @@ func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, u
 
 		resp, respErr = api.request(ctx, method, uri, reqBody, authType, headers)
 
+		// a cancelled or expired context aborts the request in flight and
+		// must not be retried
+		if respErr != nil && ctx.Err() != nil {
+			return nil, respErr
+		}
+
 		// retry if the server is rate limiting us or if it failed
 		// assumes server operations are rolled back on failure
-		if respErr != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		retryable := respErr != nil || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+		if retryable && !api.retryPolicy.allowsRetry(ctx, method) {
+			if respErr != nil {
+				return nil, respErr
+			}
+			retryable = false
+		}
+
+		if retryable {
+			retryAfter = 0
+
 			// if we got a valid http response, try to read body so we can reuse the connection
 			// see https://golang.org/pkg/net/http/#Client.Do
 			if respErr == nil {
+				retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
 				respBody, err = ioutil.ReadAll(resp.Body)
 				resp.Body.Close()
 
@@ -278,6 +328,24 @@ func (api *API) makeRequestWithAuthTypeAndHeaders(ctx context.Context, method, u
 		return nil, respErr
 	}
 
+	if meta, ok := ctx.Value(responseMetaKey{}).(*ResponseMeta); ok && meta != nil {
+		// bodies that aren't a response envelope, such as file downloads,
+		// have no messages
+		var envelope Response
+		_ = json.Unmarshal(respBody, &envelope)
+
+		*meta = ResponseMeta{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header.Clone(),
+			RayID:      resp.Header.Get("cf-ray"),
+			Messages:   envelope.Messages,
+		}
+	}
+
+	if api.requestLogger != nil {
+		api.logRequest(resp, reqBodyBytes, respBody)
+	}
+
 	if api.Debug {
 		fmt.Printf("cloudflare-go [DEBUG] RESPONSE StatusCode:%d Body:%#v RayID:%s\n", resp.StatusCode, string(respBody), resp.Header.Get("cf-ray"))
 	}
@@ -352,6 +420,16 @@ func (api *API) request(ctx context.Context, method, uri string, reqBody io.Read
 	combinedHeaders := make(http.Header)
 	copyHeader(combinedHeaders, api.headers)
 	copyHeader(combinedHeaders, headers)
+	if extra, ok := ctx.Value(extraHeadersKey{}).(http.Header); ok {
+		for name, values := range extra {
+			if contains(protectedHeaders, http.CanonicalHeaderKey(name)) {
+				continue
+			}
+			for _, value := range values {
+				combinedHeaders.Add(name, value)
+			}
+		}
+	}
 	req.Header = combinedHeaders
 
 	if authType&AuthKeyEmail != 0 {
@@ -464,10 +542,114 @@ type PaginationOptions struct {
 
 // RetryPolicy specifies number of retries and min/max retry delays
 // This config is used when the client exponentially backs off after errored requests.
+// A Retry-After header sent by the API takes precedence over the computed delay.
 type RetryPolicy struct {
 	MaxRetries    int
 	MinRetryDelay time.Duration
 	MaxRetryDelay time.Duration
+
+	// Jitter is the upper bound of a random delay added to each backoff.
+	Jitter time.Duration
+
+	// IdempotentOnly restricts retries to idempotent methods and requests
+	// marked with WithRetryableRequest.
+	IdempotentOnly bool
+}
+
+type retryableRequestKey struct{}
+
+// WithRetryableRequest marks the requests made with the returned context as
+// safe to retry even when their method isn't idempotent.
+func WithRetryableRequest(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableRequestKey{}, true)
+}
+
+// withoutRetry marks the requests made with the returned context as never
+// retried, such as those streaming a body that can't be read twice.
+func withoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, retryableRequestKey{}, false)
+}
+
+// ResponseMeta holds the metadata of an API response, such as the rate
+// limit headers and the ray ID Cloudflare support asks for. Messages holds
+// the non-fatal messages of the response envelope, such as deprecation
+// warnings, with their codes.
+type ResponseMeta struct {
+	StatusCode int
+	Header     http.Header
+	RayID      string
+	Messages   []ResponseInfo
+}
+
+type responseMetaKey struct{}
+
+// WithResponseMeta returns a context that records the metadata of the
+// responses to requests made with it in meta. When a call makes several
+// requests meta holds the last response. It's also set for error responses.
+//
+//	var meta cloudflare.ResponseMeta
+//	config, err := api.TeamsAccountConfiguration(cloudflare.WithResponseMeta(ctx, &meta), accountID)
+//	log.Printf("ray ID %s", meta.RayID)
+func WithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaKey{}, meta)
+}
+
+type extraHeadersKey struct{}
+
+// protectedHeaders are the request headers set by the client that
+// WithExtraHeaders can't override.
+var protectedHeaders = []string{
+	"Authorization",
+	"Content-Type",
+	"X-Auth-Key",
+	"X-Auth-Email",
+	"X-Auth-User-Service-Key",
+}
+
+// WithExtraHeaders returns a context that adds headers to the requests made
+// with it, such as a change request ID for audit correlation. Headers used
+// for authentication and the Content-Type header are ignored.
+func WithExtraHeaders(ctx context.Context, headers http.Header) context.Context {
+	return context.WithValue(ctx, extraHeadersKey{}, headers)
+}
+
+// allowsRetry returns whether a failed request may be sent again.
+func (p RetryPolicy) allowsRetry(ctx context.Context, method string) bool {
+	retryable, marked := ctx.Value(retryableRequestKey{}).(bool)
+	if marked && !retryable {
+		return false
+	}
+
+	if !p.IdempotentOnly {
+		return true
+	}
+
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	return retryable
+}
+
+// parseRetryAfter returns the delay requested by a Retry-After header, given
+// either in seconds or as an HTTP date, or zero if it is missing or invalid.
+func parseRetryAfter(header string) time.Duration {
+	if header == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(header); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if date, err := http.ParseTime(header); err == nil {
+		if d := time.Until(date); d > 0 {
+			return d
+		}
+	}
+
+	return 0
 }
 
 // Logger defines the interface this library needs to use logging
@@ -213,6 +213,101 @@ func TestClient_RetryReturnsPersistentErrorResponse(t *testing.T) {
 	assert.Error(t, err)
 }
 
+func TestClient_RetryAbortsWhenRetryAfterExceedsDeadline(t *testing.T) {
+	setup(UsingRetryPolicy(2, 0, 1))
+	defer teardown()
+
+	requestsReceived := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requestsReceived++
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{
+			"success": false,
+			"errors": [ "this is a rate limiting error"],
+			"messages": [],
+			"result": []
+		}`)
+	}
+
+	mux.HandleFunc("/user/load_balancers/pools", handler)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.ListLoadBalancerPools(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, requestsReceived)
+	assert.Less(t, time.Since(start), 5*time.Second)
+}
+
+func TestClient_IdempotentRetriesSkipPost(t *testing.T) {
+	setup(UsingRetryPolicy(2, 0, 0), UsingIdempotentRetries())
+	defer teardown()
+
+	requestsReceived := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		requestsReceived++
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{
+			"success": false,
+			"errors": [ "server created some error"],
+			"messages": [],
+			"result": []
+		}`)
+	}
+
+	mux.HandleFunc("/user/load_balancers/pools", handler)
+
+	_, err := client.CreateLoadBalancerPool(context.Background(), LoadBalancerPool{ID: "123"})
+	var serviceErr *ServiceError
+	assert.ErrorAs(t, err, &serviceErr)
+	assert.Equal(t, 1, requestsReceived)
+
+	requestsReceived = 0
+	_, err = client.CreateLoadBalancerPool(WithRetryableRequest(context.Background()), LoadBalancerPool{ID: "123"})
+	assert.Error(t, err)
+	assert.Equal(t, 3, requestsReceived)
+}
+
+func TestClient_RequestTimeout(t *testing.T) {
+	setup(UsingRequestTimeout(50 * time.Millisecond))
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+	}
+
+	mux.HandleFunc("/user/load_balancers/pools", handler)
+
+	_, err := client.ListLoadBalancerPools(context.Background())
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err = client.ListLoadBalancerPools(ctx)
+	assert.NoError(t, err)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("soon"))
+	assert.Equal(t, 120*time.Second, parseRetryAfter("120"))
+
+	d := parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.Greater(t, d, 59*time.Minute)
+}
+
 func TestZoneIDByNameWithNonUniqueZonesWithoutOrgID(t *testing.T) {
 	setup()
 	defer teardown()
@@ -534,3 +629,59 @@ func TestCheckResultInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestClient_WithResponseMeta(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("cf-ray", "7059e0d2bd2b0000-LHR")
+		w.Header().Set("CF-RateLimit-Remaining", "1199")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [{"code": 10050, "message": "setting deprecated"}], "result": {"settings": {}}}`)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/missing", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.Header().Set("cf-ray", "7059e0d2bd2b0001-LHR")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 2001, "message": "not found"}], "messages": [], "result": null}`)
+	})
+
+	var meta ResponseMeta
+	_, err := client.TeamsAccountConfiguration(WithResponseMeta(context.Background(), &meta), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, http.StatusOK, meta.StatusCode)
+		assert.Equal(t, "7059e0d2bd2b0000-LHR", meta.RayID)
+		assert.Equal(t, "1199", meta.Header.Get("CF-RateLimit-Remaining"))
+		assert.Equal(t, []ResponseInfo{{Code: 10050, Message: "setting deprecated"}}, meta.Messages)
+	}
+
+	_, err = client.TeamsRule(WithResponseMeta(context.Background(), &meta), testAccountID, "missing")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusNotFound, meta.StatusCode)
+	assert.Equal(t, "7059e0d2bd2b0001-LHR", meta.RayID)
+	assert.Empty(t, meta.Messages)
+}
+
+func TestClient_WithExtraHeaders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/7559a944-3dd7-41bf-b183-360a814a8c36", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "CHG-1234", r.Header.Get("X-Change-Request"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "deadbeef", r.Header.Get("X-Auth-Key"))
+		assert.Empty(t, r.Header.Get("Authorization"))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+	})
+
+	headers := make(http.Header)
+	headers.Set("X-Change-Request", "CHG-1234")
+	headers.Set("Content-Type", "text/plain")
+	headers.Set("X-Auth-Key", "override")
+	headers.Set("Authorization", "Bearer override")
+
+	err := client.TeamsDeleteRule(WithExtraHeaders(context.Background(), headers), testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36")
+	assert.NoError(t, err)
+}
@@ -0,0 +1,65 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// DeviceClientCertificates controls whether devices enrolled in WARP are
+// issued a client certificate for mTLS with the zone.
+type DeviceClientCertificates struct {
+	Enabled bool `json:"enabled"`
+}
+
+// DeviceClientCertificatesResponse is the API response, containing the
+// device client certificate settings of a zone.
+type DeviceClientCertificatesResponse struct {
+	Response
+	Result DeviceClientCertificates `json:"result"`
+}
+
+// GetDeviceClientCertificates returns the device client certificate
+// settings. Unlike the other device settings these belong to a zone rather
+// than an account.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-get-device-client-certificates
+func (api *API) GetDeviceClientCertificates(ctx context.Context, zoneID string) (DeviceClientCertificates, error) {
+	if zoneID == "" {
+		return DeviceClientCertificates{}, ErrMissingZoneID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy/certificates", ZoneRouteRoot, zoneID)
+
+	return api.deviceClientCertificatesRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// UpdateDeviceClientCertificates enables or disables the issuing of device
+// client certificates for a zone.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-update-device-client-certificates
+func (api *API) UpdateDeviceClientCertificates(ctx context.Context, zoneID string, certificates DeviceClientCertificates) (DeviceClientCertificates, error) {
+	if zoneID == "" {
+		return DeviceClientCertificates{}, ErrMissingZoneID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy/certificates", ZoneRouteRoot, zoneID)
+
+	return api.deviceClientCertificatesRequest(ctx, http.MethodPatch, uri, certificates)
+}
+
+func (api *API) deviceClientCertificatesRequest(ctx context.Context, method, uri string, params interface{}) (DeviceClientCertificates, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return DeviceClientCertificates{}, err
+	}
+
+	var deviceClientCertificatesResponse DeviceClientCertificatesResponse
+	err = json.Unmarshal(res, &deviceClientCertificatesResponse)
+	if err != nil {
+		return DeviceClientCertificates{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return deviceClientCertificatesResponse.Result, nil
+}
@@ -0,0 +1,66 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDeviceClientCertificates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"enabled": true}
+		}`)
+	}
+
+	mux.HandleFunc("/zones/"+testZoneID+"/devices/policy/certificates", handler)
+
+	actual, err := client.GetDeviceClientCertificates(context.Background(), testZoneID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DeviceClientCertificates{Enabled: true}, actual)
+	}
+
+	_, err = client.GetDeviceClientCertificates(context.Background(), "")
+	assert.Equal(t, ErrMissingZoneID, err)
+}
+
+func TestUpdateDeviceClientCertificates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"enabled":false}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"enabled": false}
+		}`)
+	}
+
+	mux.HandleFunc("/zones/"+testZoneID+"/devices/policy/certificates", handler)
+
+	actual, err := client.UpdateDeviceClientCertificates(context.Background(), testZoneID, DeviceClientCertificates{Enabled: false})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DeviceClientCertificates{Enabled: false}, actual)
+	}
+}
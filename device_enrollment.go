@@ -0,0 +1,81 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+)
+
+var ErrMissingDeviceEnrollmentApplication = errors.New("account has no device enrollment application")
+
+// DeviceEnrollmentPolicy holds the Access policies deciding who can enroll a
+// device with WARP. Enrollment permissions are the policies of the account's
+// Access application of type warp, so each policy is a regular AccessPolicy
+// with include, exclude and require rules.
+type DeviceEnrollmentPolicy struct {
+	ApplicationID string         `json:"application_id"`
+	Policies      []AccessPolicy `json:"policies"`
+}
+
+// GetDeviceEnrollmentPolicy returns the device enrollment policies of an
+// account. If a page of policies after the first one fails, the policies
+// fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#access-policy-list-access-policies
+func (api *API) GetDeviceEnrollmentPolicy(ctx context.Context, accountID string) (DeviceEnrollmentPolicy, error) {
+	applicationID, err := api.deviceEnrollmentApplicationID(ctx, accountID)
+	if err != nil {
+		return DeviceEnrollmentPolicy{}, err
+	}
+
+	var policies []AccessPolicy
+	err = fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		page, resultInfo, err := api.AccessPolicies(ctx, accountID, applicationID, PaginationOptions{Page: params.Page, PerPage: params.PerPage})
+		policies = append(policies, page...)
+		return len(page), resultInfo, err
+	})
+	if err != nil && !isPaginationError(err) {
+		return DeviceEnrollmentPolicy{}, err
+	}
+
+	return DeviceEnrollmentPolicy{ApplicationID: applicationID, Policies: policies}, err
+}
+
+// UpdateDeviceEnrollmentPolicy updates a device enrollment policy, or adds it
+// when it has no ID.
+//
+// API reference: https://api.cloudflare.com/#access-policy-update-access-policy
+func (api *API) UpdateDeviceEnrollmentPolicy(ctx context.Context, accountID string, policy AccessPolicy) (AccessPolicy, error) {
+	applicationID, err := api.deviceEnrollmentApplicationID(ctx, accountID)
+	if err != nil {
+		return AccessPolicy{}, err
+	}
+
+	if policy.ID == "" {
+		return api.CreateAccessPolicy(ctx, accountID, applicationID, policy)
+	}
+
+	return api.UpdateAccessPolicy(ctx, accountID, applicationID, policy)
+}
+
+// deviceEnrollmentApplicationID returns the ID of the account's Access
+// application of type warp.
+func (api *API) deviceEnrollmentApplicationID(ctx context.Context, accountID string) (string, error) {
+	var applications []AccessApplication
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		page, resultInfo, err := api.AccessApplications(ctx, accountID, PaginationOptions{Page: params.Page, PerPage: params.PerPage})
+		applications = append(applications, page...)
+		return len(page), resultInfo, err
+	})
+
+	// the application may be on one of the pages fetched before a failure
+	for _, application := range applications {
+		if application.Type == Warp {
+			return application.ID, nil
+		}
+	}
+
+	if err != nil {
+		return "", err
+	}
+	return "", ErrMissingDeviceEnrollmentApplication
+}
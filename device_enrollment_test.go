@@ -0,0 +1,112 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func handleDeviceEnrollmentApplications(t *testing.T) {
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/apps", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", "name": "Admin", "type": "self_hosted"},
+				{"id": "%s", "name": "Warp Login App", "type": "warp"}
+			],
+			"result_info": {"page": 1, "per_page": 50, "count": 2, "total_count": 2, "total_pages": 1}
+		}`, accessApplicationID)
+	})
+}
+
+func TestGetDeviceEnrollmentPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleDeviceEnrollmentApplications(t)
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/apps/"+accessApplicationID+"/policies", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": "%s",
+					"precedence": 1,
+					"decision": "allow",
+					"name": "Employees",
+					"include": [{"email_domain": {"domain": "example.com"}}],
+					"exclude": [],
+					"require": []
+				}
+			]
+		}`, accessPolicyID)
+	})
+
+	actual, err := client.GetDeviceEnrollmentPolicy(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, accessApplicationID, actual.ApplicationID)
+		if assert.Len(t, actual.Policies, 1) {
+			assert.Equal(t, accessPolicyID, actual.Policies[0].ID)
+			assert.Equal(t, []interface{}{map[string]interface{}{"email_domain": map[string]interface{}{"domain": "example.com"}}}, actual.Policies[0].Include)
+		}
+	}
+}
+
+func TestUpdateDeviceEnrollmentPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handleDeviceEnrollmentApplications(t)
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/apps/"+accessApplicationID+"/policies/"+accessPolicyID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		var policy AccessPolicy
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&policy)) {
+			assert.Equal(t, "Employees", policy.Name)
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "precedence": 1, "decision": "allow", "name": "Employees"}
+		}`, accessPolicyID)
+	})
+
+	actual, err := client.UpdateDeviceEnrollmentPolicy(context.Background(), testAccountID, AccessPolicy{
+		ID:       accessPolicyID,
+		Name:     "Employees",
+		Decision: "allow",
+		Include:  []interface{}{map[string]interface{}{"email_domain": map[string]interface{}{"domain": "example.com"}}},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, accessPolicyID, actual.ID)
+	}
+}
+
+func TestGetDeviceEnrollmentPolicyMissingApplication(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/apps", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+	})
+
+	_, err := client.GetDeviceEnrollmentPolicy(context.Background(), testAccountID)
+	assert.Equal(t, ErrMissingDeviceEnrollmentApplication, err)
+}
@@ -0,0 +1,150 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+)
+
+var ErrMissingNetworkID = errors.New("required missing network ID")
+
+var sha256HexRegexp = regexp.MustCompile(`^[0-9a-fA-F]{64}$`)
+
+// DeviceManagedNetworkConfig is the TLS endpoint that WARP clients probe to
+// detect that they are on a managed network.
+type DeviceManagedNetworkConfig struct {
+	TLSSockAddr string `json:"tls_sockaddr"`
+	Sha256      string `json:"sha256"`
+}
+
+// DeviceManagedNetwork represents a network on which WARP clients switch to
+// the settings of the device settings policies matching it.
+type DeviceManagedNetwork struct {
+	NetworkID string                      `json:"network_id,omitempty"`
+	Type      string                      `json:"type"`
+	Name      string                      `json:"name"`
+	Config    *DeviceManagedNetworkConfig `json:"config"`
+}
+
+// DeviceManagedNetworkResponse is the API response, containing a single
+// managed network.
+type DeviceManagedNetworkResponse struct {
+	Response
+	Result DeviceManagedNetwork `json:"result"`
+}
+
+// DeviceManagedNetworkListResponse is the API response, containing an array
+// of managed networks.
+type DeviceManagedNetworkListResponse struct {
+	Response
+	Result []DeviceManagedNetwork `json:"result"`
+}
+
+// validateDeviceManagedNetwork checks that the certificate hash is a hex
+// encoded SHA-256 digest.
+func validateDeviceManagedNetwork(network DeviceManagedNetwork) error {
+	if network.Config != nil && !sha256HexRegexp.MatchString(network.Config.Sha256) {
+		return fmt.Errorf("invalid managed network certificate hash %q: must be a 64 character hex encoded SHA-256 digest", network.Config.Sha256)
+	}
+
+	return nil
+}
+
+// DeviceManagedNetworks returns all device managed networks within an account.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-list-device-managed-networks
+func (api *API) DeviceManagedNetworks(ctx context.Context, accountID string) ([]DeviceManagedNetwork, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/networks", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []DeviceManagedNetwork{}, err
+	}
+
+	var deviceManagedNetworkListResponse DeviceManagedNetworkListResponse
+	err = json.Unmarshal(res, &deviceManagedNetworkListResponse)
+	if err != nil {
+		return []DeviceManagedNetwork{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return deviceManagedNetworkListResponse.Result, nil
+}
+
+// DeviceManagedNetwork returns a single device managed network.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-device-managed-network-details
+func (api *API) DeviceManagedNetwork(ctx context.Context, accountID, networkID string) (DeviceManagedNetwork, error) {
+	if networkID == "" {
+		return DeviceManagedNetwork{}, ErrMissingNetworkID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/networks/%s", AccountRouteRoot, accountID, networkID)
+
+	return api.deviceManagedNetworkRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// CreateDeviceManagedNetwork creates a new device managed network.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-create-device-managed-network
+func (api *API) CreateDeviceManagedNetwork(ctx context.Context, accountID string, network DeviceManagedNetwork) (DeviceManagedNetwork, error) {
+	if err := validateDeviceManagedNetwork(network); err != nil {
+		return DeviceManagedNetwork{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/networks", AccountRouteRoot, accountID)
+
+	return api.deviceManagedNetworkRequest(ctx, http.MethodPost, uri, network)
+}
+
+// UpdateDeviceManagedNetwork updates an existing device managed network.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-update-device-managed-network
+func (api *API) UpdateDeviceManagedNetwork(ctx context.Context, accountID string, network DeviceManagedNetwork) (DeviceManagedNetwork, error) {
+	if network.NetworkID == "" {
+		return DeviceManagedNetwork{}, ErrMissingNetworkID
+	}
+
+	if err := validateDeviceManagedNetwork(network); err != nil {
+		return DeviceManagedNetwork{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/networks/%s", AccountRouteRoot, accountID, network.NetworkID)
+
+	return api.deviceManagedNetworkRequest(ctx, http.MethodPut, uri, network)
+}
+
+// DeleteDeviceManagedNetwork deletes a device managed network.
+//
+// API reference: https://api.cloudflare.com/#device-managed-networks-delete-device-managed-network
+func (api *API) DeleteDeviceManagedNetwork(ctx context.Context, accountID, networkID string) error {
+	if networkID == "" {
+		return ErrMissingNetworkID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/networks/%s", AccountRouteRoot, accountID, networkID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (api *API) deviceManagedNetworkRequest(ctx context.Context, method, uri string, params interface{}) (DeviceManagedNetwork, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return DeviceManagedNetwork{}, err
+	}
+
+	var deviceManagedNetworkResponse DeviceManagedNetworkResponse
+	err = json.Unmarshal(res, &deviceManagedNetworkResponse)
+	if err != nil {
+		return DeviceManagedNetwork{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return deviceManagedNetworkResponse.Result, nil
+}
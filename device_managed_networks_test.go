@@ -0,0 +1,224 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDeviceManagedNetworkID = "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"
+
+func TestDeviceManagedNetworks(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"network_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"type": "tls",
+				"name": "office",
+				"config": {
+					"tls_sockaddr": "10.0.0.1:443",
+					"sha256": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+				}
+			}]
+		}`)
+	}
+
+	want := DeviceManagedNetwork{
+		NetworkID: testDeviceManagedNetworkID,
+		Type:      "tls",
+		Name:      "office",
+		Config: &DeviceManagedNetworkConfig{
+			TLSSockAddr: "10.0.0.1:443",
+			Sha256:      "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/networks", handler)
+
+	actual, err := client.DeviceManagedNetworks(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []DeviceManagedNetwork{want}, actual)
+	}
+}
+
+func TestDeviceManagedNetwork(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"network_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"type": "tls",
+				"name": "office",
+				"config": {
+					"tls_sockaddr": "10.0.0.1:443",
+					"sha256": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+				}
+			}
+		}`)
+	}
+
+	want := DeviceManagedNetwork{
+		NetworkID: testDeviceManagedNetworkID,
+		Type:      "tls",
+		Name:      "office",
+		Config: &DeviceManagedNetworkConfig{
+			TLSSockAddr: "10.0.0.1:443",
+			Sha256:      "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/networks/"+testDeviceManagedNetworkID, handler)
+
+	actual, err := client.DeviceManagedNetwork(context.Background(), testAccountID, testDeviceManagedNetworkID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.DeviceManagedNetwork(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingNetworkID, err)
+}
+
+func TestCreateDeviceManagedNetwork(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"network_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"type": "tls",
+				"name": "office",
+				"config": {
+					"tls_sockaddr": "10.0.0.1:443",
+					"sha256": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+				}
+			}
+		}`)
+	}
+
+	want := DeviceManagedNetwork{
+		NetworkID: testDeviceManagedNetworkID,
+		Type:      "tls",
+		Name:      "office",
+		Config: &DeviceManagedNetworkConfig{
+			TLSSockAddr: "10.0.0.1:443",
+			Sha256:      "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/networks", handler)
+
+	network := want
+	network.NetworkID = ""
+	actual, err := client.CreateDeviceManagedNetwork(context.Background(), testAccountID, network)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	network.Config = &DeviceManagedNetworkConfig{TLSSockAddr: "10.0.0.1:443", Sha256: "not-a-hash"}
+	_, err = client.CreateDeviceManagedNetwork(context.Background(), testAccountID, network)
+	assert.EqualError(t, err, `invalid managed network certificate hash "not-a-hash": must be a 64 character hex encoded SHA-256 digest`)
+}
+
+func TestUpdateDeviceManagedNetwork(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"network_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"type": "tls",
+				"name": "office",
+				"config": {
+					"tls_sockaddr": "10.0.0.1:443",
+					"sha256": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+				}
+			}
+		}`)
+	}
+
+	want := DeviceManagedNetwork{
+		NetworkID: testDeviceManagedNetworkID,
+		Type:      "tls",
+		Name:      "office",
+		Config: &DeviceManagedNetworkConfig{
+			TLSSockAddr: "10.0.0.1:443",
+			Sha256:      "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c",
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/networks/"+testDeviceManagedNetworkID, handler)
+
+	actual, err := client.UpdateDeviceManagedNetwork(context.Background(), testAccountID, want)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.UpdateDeviceManagedNetwork(context.Background(), testAccountID, DeviceManagedNetwork{})
+	assert.Equal(t, ErrMissingNetworkID, err)
+}
+
+func TestDeleteDeviceManagedNetwork(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"network_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"type": "tls",
+				"name": "office",
+				"config": {
+					"tls_sockaddr": "10.0.0.1:443",
+					"sha256": "b5bb9d8014a0f9b1d61e21e796d78dccdf1352f23cd32812f4850b878ae4944c"
+				}
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/networks/"+testDeviceManagedNetworkID, handler)
+
+	err := client.DeleteDeviceManagedNetwork(context.Background(), testAccountID, testDeviceManagedNetworkID)
+	assert.NoError(t, err)
+
+	err = client.DeleteDeviceManagedNetwork(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingNetworkID, err)
+}
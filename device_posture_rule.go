@@ -5,10 +5,34 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Device posture integration types.
+const (
+	DevicePostureIntegrationCrowdStrike  = "crowdstrike_s2s"
+	DevicePostureIntegrationIntune       = "intune"
+	DevicePostureIntegrationWorkspaceOne = "workspace_one"
+	DevicePostureIntegrationUptycs       = "uptycs"
+	DevicePostureIntegrationSentinelOne  = "sentinelone_s2s"
 )
 
 // DevicePostureIntegrationConfig contains authentication information
-// for a device posture integration.
+// for a device posture integration. Which fields apply depends on the
+// integration type:
+//
+//	crowdstrike_s2s: ApiUrl, ClientID, ClientSecret, CustomerID
+//	intune:          ClientID, ClientSecret, CustomerID (the Azure tenant ID)
+//	workspace_one:   ApiUrl, AuthUrl, ClientID, ClientSecret
+//	uptycs:          ApiUrl, ClientKey, ClientSecret, CustomerID
+//	sentinelone_s2s: ApiUrl, ClientSecret
+//
+// ClientSecret and ClientKey are write-only: they are sent when creating or
+// updating an integration but cleared from the integrations returned by
+// DevicePostureIntegration, DevicePostureIntegrations,
+// CreateDevicePostureIntegration and UpdateDevicePostureIntegration.
 type DevicePostureIntegrationConfig struct {
 	ClientID     string `json:"client_id,omitempty"`
 	ClientSecret string `json:"client_secret,omitempty"`
@@ -18,7 +42,19 @@ type DevicePostureIntegrationConfig struct {
 	CustomerID   string `json:"customer_id,omitempty"`
 }
 
+// clearSecrets removes the write-only fields from a config returned by the
+// API.
+func (c *DevicePostureIntegrationConfig) clearSecrets() {
+	c.ClientSecret = ""
+	c.ClientKey = ""
+}
+
 // DevicePostureIntegration represents a device posture integration.
+//
+// Integrations are polled on Interval. The API neither offers a way to
+// trigger a poll on demand nor reports when an integration was last polled
+// or whether polling succeeded, so staleness can't be detected from the
+// integration itself.
 type DevicePostureIntegration struct {
 	IntegrationID string                         `json:"id,omitempty"`
 	Name          string                         `json:"name,omitempty"`
@@ -51,7 +87,6 @@ func (api *API) CreateDevicePostureIntegration(ctx context.Context, accountID st
 
 	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, integration)
 	if err != nil {
-		fmt.Printf("err:%+v res:%+v\n", err, res)
 		return DevicePostureIntegration{}, err
 	}
 
@@ -61,6 +96,8 @@ func (api *API) CreateDevicePostureIntegration(ctx context.Context, accountID st
 		return DevicePostureIntegration{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
+	devicePostureIntegrationResponse.Result.Config.clearSecrets()
+
 	return devicePostureIntegrationResponse.Result, nil
 }
 
@@ -81,6 +118,8 @@ func (api *API) UpdateDevicePostureIntegration(ctx context.Context, accountID st
 		return DevicePostureIntegration{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
+	devicePostureIntegrationResponse.Result.Config.clearSecrets()
+
 	return devicePostureIntegrationResponse.Result, nil
 }
 
@@ -101,6 +140,8 @@ func (api *API) DevicePostureIntegration(ctx context.Context, accountID, integra
 		return DevicePostureIntegration{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
+	devicePostureIntegrationResponse.Result.Config.clearSecrets()
+
 	return devicePostureIntegrationResponse.Result, nil
 }
 
@@ -121,6 +162,10 @@ func (api *API) DevicePostureIntegrations(ctx context.Context, accountID string)
 		return []DevicePostureIntegration{}, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
+	for i := range devicePostureIntegrationListResponse.Result {
+		devicePostureIntegrationListResponse.Result[i].Config.clearSecrets()
+	}
+
 	return devicePostureIntegrationListResponse.Result, devicePostureIntegrationListResponse.ResultInfo, nil
 }
 
@@ -161,6 +206,21 @@ type DevicePostureRuleMatch struct {
 }
 
 // DevicePostureRuleInput represents the value to be checked against.
+//
+// The fields that apply depend on the rule type and unused fields are
+// omitted from the request:
+//
+//   - file: Path, Exists, Thumbprint, Sha256
+//   - application: Path, Running, Thumbprint, Sha256
+//   - serial_number: ID
+//   - os_version: Version, Operator
+//   - domain_joined: Domain
+//   - firewall, disk_encryption: Enabled, RequireAll
+//   - workspace_one, crowdstrike_s2s, intune: ConnectionID, ComplianceStatus,
+//     Operator, Version
+//   - tanium, tanium_s2s: ConnectionID, EidLastSeen, RiskLevel,
+//     ScoreOperator, TotalScore
+//   - kolide: ConnectionID, CountOperator, IssueCount
 type DevicePostureRuleInput struct {
 	ID               string `json:"id,omitempty"`
 	Path             string `json:"path,omitempty"`
@@ -175,6 +235,148 @@ type DevicePostureRuleInput struct {
 	Domain           string `json:"domain,omitempty"`
 	ComplianceStatus string `json:"compliance_status,omitempty"`
 	ConnectionID     string `json:"connection_id,omitempty"`
+	CountOperator    string `json:"countOperator,omitempty"`
+	IssueCount       string `json:"issue_count,omitempty"`
+	EidLastSeen      string `json:"eid_last_seen,omitempty"`
+	RiskLevel        string `json:"risk_level,omitempty"`
+	ScoreOperator    string `json:"scoreOperator,omitempty"`
+	TotalScore       int    `json:"total_score,omitempty"`
+}
+
+// devicePostureVersionOperators lists the operators an os_version rule can
+// compare versions with.
+var devicePostureVersionOperators = []string{"<", "<=", ">", ">=", "=="}
+
+// devicePostureVersionRegexp matches the versions os_version rules compare
+// against. The API takes semantic versions on every platform, so macOS 13.4
+// is written 13.4.0 and Windows builds as 10.0.19045.
+var devicePostureVersionRegexp = regexp.MustCompile(`^(0|[1-9]\d*)\.(0|[1-9]\d*)\.(0|[1-9]\d*)$`)
+
+// validateDevicePostureRuleInput checks the fields required by the third
+// party integration input types before a rule is sent to the API.
+func validateDevicePostureRuleInput(rule DevicePostureRule) error {
+	switch rule.Type {
+	case "tanium", "tanium_s2s":
+		if rule.Input.ConnectionID == "" {
+			return fmt.Errorf("device posture rule of type %q requires an integration connection ID", rule.Type)
+		}
+
+		if rule.Input.TotalScore != 0 && rule.Input.ScoreOperator == "" {
+			return fmt.Errorf("device posture rule of type %q requires a score operator when total score is set", rule.Type)
+		}
+
+		switch rule.Input.RiskLevel {
+		case "", "low", "medium", "high", "critical":
+		default:
+			return fmt.Errorf("invalid device posture rule risk level %q", rule.Input.RiskLevel)
+		}
+	case "os_version":
+		if rule.Input.Operator != "" && !contains(devicePostureVersionOperators, rule.Input.Operator) {
+			return fmt.Errorf("invalid os_version operator %q, must be one of %s", rule.Input.Operator, strings.Join(devicePostureVersionOperators, ", "))
+		}
+
+		if rule.Input.Version != "" && !devicePostureVersionRegexp.MatchString(rule.Input.Version) {
+			return fmt.Errorf("invalid os_version version %q: must be a semantic version such as 13.4.1", rule.Input.Version)
+		}
+	case "kolide":
+		if rule.Input.ConnectionID == "" {
+			return fmt.Errorf("device posture rule of type %q requires an integration connection ID", rule.Type)
+		}
+
+		if rule.Input.IssueCount != "" && rule.Input.CountOperator == "" {
+			return fmt.Errorf("device posture rule of type %q requires a count operator when issue count is set", rule.Type)
+		}
+	}
+
+	return nil
+}
+
+// devicePostureRulePlatforms lists the platforms each rule type can match.
+// Types that aren't listed can match any platform.
+var devicePostureRulePlatforms = map[string][]string{
+	"file":            {"windows", "mac", "linux"},
+	"application":     {"windows", "mac", "linux"},
+	"serial_number":   {"windows", "mac", "linux", "chromeos"},
+	"os_version":      {"windows", "mac", "linux", "android", "ios", "chromeos"},
+	"domain_joined":   {"windows"},
+	"firewall":        {"windows", "mac"},
+	"disk_encryption": {"windows", "mac", "linux"},
+}
+
+var devicePostureAllPlatforms = []string{"windows", "mac", "linux", "android", "ios", "chromeos"}
+
+// DevicePostureRuleValidationError holds every problem found by
+// DevicePostureRule.Validate.
+type DevicePostureRuleValidationError struct {
+	Errors []error
+}
+
+func (e *DevicePostureRuleValidationError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Validate checks that the Match platforms can be used with the rule Type,
+// that Schedule is a duration such as "5m" or "1h" and that the Input fields
+// required by the rule Type are set. All problems are reported in a
+// *DevicePostureRuleValidationError.
+func (rule DevicePostureRule) Validate() error {
+	var errs []error
+
+	platforms, ok := devicePostureRulePlatforms[rule.Type]
+	if !ok {
+		platforms = devicePostureAllPlatforms
+	}
+	for _, match := range rule.Match {
+		if !contains(platforms, match.Platform) {
+			errs = append(errs, fmt.Errorf("invalid platform %q for device posture rule of type %q, must be one of %s", match.Platform, rule.Type, strings.Join(platforms, ", ")))
+		}
+	}
+
+	if rule.Schedule != "" {
+		if d, err := time.ParseDuration(rule.Schedule); err != nil || d <= 0 {
+			errs = append(errs, fmt.Errorf("invalid device posture rule schedule %q: must be a duration such as 5m or 1h", rule.Schedule))
+		}
+	}
+
+	var missing []string
+	switch rule.Type {
+	case "file", "application":
+		if rule.Input.Path == "" {
+			missing = append(missing, "path")
+		}
+	case "serial_number":
+		if rule.Input.ID == "" {
+			missing = append(missing, "id")
+		}
+	case "os_version":
+		if rule.Input.Version == "" {
+			missing = append(missing, "version")
+		}
+		if rule.Input.Operator == "" {
+			missing = append(missing, "operator")
+		}
+	case "domain_joined":
+		if rule.Input.Domain == "" {
+			missing = append(missing, "domain")
+		}
+	}
+	for _, field := range missing {
+		errs = append(errs, fmt.Errorf("device posture rule of type %q requires input %s", rule.Type, field))
+	}
+
+	if err := validateDevicePostureRuleInput(rule); err != nil {
+		errs = append(errs, err)
+	}
+
+	if len(errs) > 0 {
+		return &DevicePostureRuleValidationError{Errors: errs}
+	}
+
+	return nil
 }
 
 // DevicePostureRuleListResponse represents the response from the list
@@ -212,6 +414,37 @@ func (api *API) DevicePostureRules(ctx context.Context, accountID string) ([]Dev
 	return devicePostureRuleListResponse.Result, devicePostureRuleListResponse.ResultInfo, nil
 }
 
+// DevicePostureRulesAll returns all device posture rules within an account, following the pagination
+// until every page has been fetched. If a page after the first one fails,
+// the device posture rules fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#device-posture-rules-list-device-posture-rules
+func (api *API) DevicePostureRulesAll(ctx context.Context, accountID string) ([]DevicePostureRule, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/posture", AccountRouteRoot, accountID)
+
+	var rules []DevicePostureRule
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, PaginationOptions{Page: params.Page, PerPage: params.PerPage}), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var devicePostureRuleListResponse DevicePostureRuleListResponse
+		err = json.Unmarshal(res, &devicePostureRuleListResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		rules = append(rules, devicePostureRuleListResponse.Result...)
+		return len(devicePostureRuleListResponse.Result), devicePostureRuleListResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []DevicePostureRule{}, err
+	}
+
+	return rules, err
+}
+
 // DevicePostureRule returns a single device posture rule based on the rule ID.
 //
 // API reference: https://api.cloudflare.com/#device-posture-rules-device-posture-rules-details
@@ -241,6 +474,10 @@ func (api *API) DevicePostureRule(ctx context.Context, accountID, ruleID string)
 //
 // API reference: https://api.cloudflare.com/#device-posture-rules-create-device-posture-rule
 func (api *API) CreateDevicePostureRule(ctx context.Context, accountID string, rule DevicePostureRule) (DevicePostureRule, error) {
+	if err := validateDevicePostureRuleInput(rule); err != nil {
+		return DevicePostureRule{}, err
+	}
+
 	uri := fmt.Sprintf("/%s/%s/devices/posture", AccountRouteRoot, accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, rule)
@@ -265,6 +502,10 @@ func (api *API) UpdateDevicePostureRule(ctx context.Context, accountID string, r
 		return DevicePostureRule{}, fmt.Errorf("device posture rule ID cannot be empty")
 	}
 
+	if err := validateDevicePostureRuleInput(rule); err != nil {
+		return DevicePostureRule{}, err
+	}
+
 	uri := fmt.Sprintf(
 		"/%s/%s/devices/posture/%s",
 		AccountRouteRoot,
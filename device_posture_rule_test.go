@@ -2,6 +2,7 @@ package cloudflare
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net/http"
 	"testing"
@@ -51,12 +52,10 @@ func TestDevicePostureIntegrations(t *testing.T) {
 		Type:          "workspace_one",
 		Interval:      "1h",
 		Config: DevicePostureIntegrationConfig{
-			AuthUrl:      "https://auth_url.example.com",
-			ApiUrl:       "https://api_url.example.com",
-			ClientID:     "test_client_id",
-			ClientSecret: "test_client_secret",
-			CustomerID:   "test_customer_id",
-			ClientKey:    "test_client_key",
+			AuthUrl:    "https://auth_url.example.com",
+			ApiUrl:     "https://api_url.example.com",
+			ClientID:   "test_client_id",
+			CustomerID: "test_customer_id",
 		},
 	}}
 
@@ -104,12 +103,10 @@ func TestDevicePostureIntegration(t *testing.T) {
 		Type:          "workspace_one",
 		Interval:      "1h",
 		Config: DevicePostureIntegrationConfig{
-			AuthUrl:      "https://auth_url.example.com",
-			ApiUrl:       "https://api_url.example.com",
-			ClientID:     "test_client_id",
-			ClientSecret: "test_client_secret",
-			CustomerID:   "test_customer_id",
-			ClientKey:    "test_client_key",
+			AuthUrl:    "https://auth_url.example.com",
+			ApiUrl:     "https://api_url.example.com",
+			ClientID:   "test_client_id",
+			CustomerID: "test_customer_id",
 		},
 	}
 
@@ -170,6 +167,9 @@ func TestDevicePostureIntegrationUpdate(t *testing.T) {
 
 	actual, err := client.UpdateDevicePostureIntegration(context.Background(), testAccountID, want)
 
+	// secrets are write-only and cleared from the returned integration
+	want.Config.ClientSecret = ""
+	want.Config.ClientKey = ""
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, actual)
 	}
@@ -182,6 +182,13 @@ func TestDevicePostureIntegrationCreate(t *testing.T) {
 	id := "480f4f69-1a28-4fdd-9240-1ed29f0ac1db"
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body struct {
+			Config map[string]string `json:"config"`
+		}
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+			assert.Equal(t, "test_client_secret", body.Config["client_secret"])
+			assert.Equal(t, "test_client_key", body.Config["client_key"])
+		}
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
@@ -223,11 +230,26 @@ func TestDevicePostureIntegrationCreate(t *testing.T) {
 
 	actual, err := client.CreateDevicePostureIntegration(context.Background(), testAccountID, want)
 
+	// secrets are write-only and cleared from the returned integration
+	want.Config.ClientSecret = ""
+	want.Config.ClientKey = ""
 	if assert.NoError(t, err) {
 		assert.Equal(t, want, actual)
 	}
 }
 
+func TestDevicePostureIntegrationConfigUnmarshal(t *testing.T) {
+	var config DevicePostureIntegrationConfig
+	err := json.Unmarshal([]byte(`{"client_id": "test_client_id", "client_secret": "test_client_secret", "client_key": "test_client_key"}`), &config)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DevicePostureIntegrationConfig{
+			ClientID:     "test_client_id",
+			ClientSecret: "test_client_secret",
+			ClientKey:    "test_client_key",
+		}, config)
+	}
+}
 func TestDevicePostureIntegrationDelete(t *testing.T) {
 	setup()
 	defer teardown()
@@ -680,3 +702,157 @@ func TestDeleteDevicePostureRule(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestDevicePostureTaniumRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+				"schedule": "1h",
+				"type": "tanium_s2s",
+				"name": "My rule name",
+				"input": {
+					"connection_id": "bc7cbfbb-600a-42e4-8a23-9c5ca1e28fd9",
+					"eid_last_seen": "1d",
+					"risk_level": "high",
+					"scoreOperator": "<",
+					"total_score": 50
+				}
+			}
+		}
+		`)
+	}
+
+	want := DevicePostureRule{
+		ID:       "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+		Name:     "My rule name",
+		Type:     "tanium_s2s",
+		Schedule: "1h",
+		Input: DevicePostureRuleInput{
+			ConnectionID:  "bc7cbfbb-600a-42e4-8a23-9c5ca1e28fd9",
+			EidLastSeen:   "1d",
+			RiskLevel:     "high",
+			ScoreOperator: "<",
+			TotalScore:    50,
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/posture/480f4f69-1a28-4fdd-9240-1ed29f0ac1db", handler)
+
+	actual, err := client.DevicePostureRule(context.Background(), testAccountID, "480f4f69-1a28-4fdd-9240-1ed29f0ac1db")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestDevicePostureKolideRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+				"schedule": "1h",
+				"type": "kolide",
+				"name": "My rule name",
+				"input": {
+					"connection_id": "bc7cbfbb-600a-42e4-8a23-9c5ca1e28fd9",
+					"countOperator": ">",
+					"issue_count": "1"
+				}
+			}
+		}
+		`)
+	}
+
+	want := DevicePostureRule{
+		ID:       "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+		Name:     "My rule name",
+		Type:     "kolide",
+		Schedule: "1h",
+		Input: DevicePostureRuleInput{
+			ConnectionID:  "bc7cbfbb-600a-42e4-8a23-9c5ca1e28fd9",
+			CountOperator: ">",
+			IssueCount:    "1",
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/posture/480f4f69-1a28-4fdd-9240-1ed29f0ac1db", handler)
+
+	actual, err := client.DevicePostureRule(context.Background(), testAccountID, "480f4f69-1a28-4fdd-9240-1ed29f0ac1db")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestCreateDevicePostureRuleWithMissingConnectionID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.CreateDevicePostureRule(context.Background(), testAccountID, DevicePostureRule{
+		Name:  "My rule name",
+		Type:  "kolide",
+		Input: DevicePostureRuleInput{CountOperator: ">", IssueCount: "1"},
+	})
+	assert.EqualError(t, err, `device posture rule of type "kolide" requires an integration connection ID`)
+}
+
+func TestDevicePostureRuleInputOmitsUnusedFields(t *testing.T) {
+	input := DevicePostureRuleInput{Version: "10.0.1", Operator: ">="}
+
+	actual, err := json.Marshal(input)
+
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"version":"10.0.1","operator":">="}`, string(actual))
+	}
+}
+
+func TestDevicePostureRuleValidate(t *testing.T) {
+	valid := DevicePostureRule{
+		Type:     "os_version",
+		Schedule: "1h",
+		Match:    []DevicePostureRuleMatch{{Platform: "windows"}, {Platform: "ios"}},
+		Input:    DevicePostureRuleInput{Version: "10.0.0", Operator: ">="},
+	}
+	assert.NoError(t, valid.Validate())
+
+	invalid := DevicePostureRule{
+		Type:     "domain_joined",
+		Schedule: "hourly",
+		Match:    []DevicePostureRuleMatch{{Platform: "mac"}},
+	}
+	err := invalid.Validate()
+
+	var validationErr *DevicePostureRuleValidationError
+	if assert.ErrorAs(t, err, &validationErr) {
+		assert.Len(t, validationErr.Errors, 3)
+	}
+	assert.EqualError(t, err, `invalid platform "mac" for device posture rule of type "domain_joined", must be one of windows; `+
+		`invalid device posture rule schedule "hourly": must be a duration such as 5m or 1h; `+
+		`device posture rule of type "domain_joined" requires input domain`)
+
+	err = DevicePostureRule{Type: "kolide"}.Validate()
+	assert.EqualError(t, err, `device posture rule of type "kolide" requires an integration connection ID`)
+
+	err = DevicePostureRule{Type: "os_version", Input: DevicePostureRuleInput{Version: "13.4", Operator: ">="}}.Validate()
+	assert.EqualError(t, err, `invalid os_version version "13.4": must be a semantic version such as 13.4.1`)
+
+	err = DevicePostureRule{Type: "os_version", Input: DevicePostureRuleInput{Version: "13.4.0", Operator: "=>"}}.Validate()
+	assert.EqualError(t, err, `invalid os_version operator "=>", must be one of <, <=, >, >=, ==`)
+}
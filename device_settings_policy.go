@@ -0,0 +1,361 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+var ErrMissingPolicyID = errors.New("required missing policy ID")
+
+// ServiceMode is the mode the WARP client runs in.
+type ServiceMode = string
+
+const (
+	ServiceModeWARP        ServiceMode = "warp"
+	ServiceModeProxy       ServiceMode = "proxy"
+	ServiceModePostureOnly ServiceMode = "posture_only"
+)
+
+// ServiceModeV2 represents the WARP client mode of a device settings policy.
+// Port is the local proxy port and only applies to the proxy mode.
+type ServiceModeV2 struct {
+	Mode ServiceMode `json:"mode,omitempty"`
+	Port int         `json:"port,omitempty"`
+}
+
+// Validate checks that Port is only set in proxy mode.
+func (s ServiceModeV2) Validate() error {
+	if s.Port != 0 && s.Mode != ServiceModeProxy {
+		return fmt.Errorf("service mode port can only be set in %q mode, got mode %q", ServiceModeProxy, s.Mode)
+	}
+
+	return nil
+}
+
+// WARP tunnel protocols.
+const (
+	TunnelProtocolWireGuard = "wireguard"
+	TunnelProtocolMASQUE    = "masque"
+)
+
+// DeviceSettingsPolicy represents a named device settings profile that is
+// applied to the devices matching its expression.
+type DeviceSettingsPolicy struct {
+	PolicyID        string         `json:"policy_id,omitempty"`
+	Name            string         `json:"name,omitempty"`
+	Description     string         `json:"description,omitempty"`
+	Match           string         `json:"match,omitempty"`
+	Precedence      int            `json:"precedence,omitempty"`
+	Default         bool           `json:"default,omitempty"`
+	Enabled         *bool          `json:"enabled,omitempty"`
+	AllowModeSwitch *bool          `json:"allow_mode_switch,omitempty"`
+	SwitchLocked    *bool          `json:"switch_locked,omitempty"`
+	CaptivePortal   *int           `json:"captive_portal,omitempty"`
+	AllowUpdates    *bool          `json:"allow_updates,omitempty"`
+	ServiceModeV2   *ServiceModeV2 `json:"service_mode_v2,omitempty"`
+
+	// SupportURL is the target of the WARP client's "get help" link.
+	SupportURL *string `json:"support_url,omitempty"`
+	// AutoConnect is the number of seconds after which a WARP client
+	// turned off by the user reconnects, 0 to leave it off.
+	AutoConnect *int `json:"auto_connect,omitempty"`
+	// AllowedToLeave lets users leave the organization from the WARP
+	// client. Together with SwitchLocked set, false keeps users from
+	// disconnecting WARP.
+	AllowedToLeave *bool `json:"allowed_to_leave,omitempty"`
+
+	// TunnelProtocol pins the protocol of the WARP tunnel, one of
+	// TunnelProtocolWireGuard or TunnelProtocolMASQUE.
+	TunnelProtocol *string `json:"tunnel_protocol,omitempty"`
+	// DisableAutoFallback stops the WARP client from resolving fallback
+	// domains that have no DNS server set with the system resolvers.
+	DisableAutoFallback *bool `json:"disable_auto_fallback,omitempty"`
+}
+
+// Validate checks the service mode, that AutoConnect isn't negative and that
+// TunnelProtocol, when set, is a known protocol.
+func (p DeviceSettingsPolicy) Validate() error {
+	if p.ServiceModeV2 != nil {
+		if err := p.ServiceModeV2.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if p.AutoConnect != nil && *p.AutoConnect < 0 {
+		return fmt.Errorf("auto connect must be 0 or more seconds, got %d", *p.AutoConnect)
+	}
+
+	if p.TunnelProtocol != nil {
+		switch *p.TunnelProtocol {
+		case TunnelProtocolWireGuard, TunnelProtocolMASQUE:
+		default:
+			return fmt.Errorf("invalid tunnel protocol %q, must be one of %s, %s", *p.TunnelProtocol, TunnelProtocolWireGuard, TunnelProtocolMASQUE)
+		}
+	}
+
+	return nil
+}
+
+// DeviceSettingsPolicyResponse is the API response, containing a single
+// device settings policy.
+type DeviceSettingsPolicyResponse struct {
+	Response
+	Result DeviceSettingsPolicy `json:"result"`
+}
+
+// DeviceSettingsPolicyListResponse represents the response from the list
+// device settings policies endpoint.
+type DeviceSettingsPolicyListResponse struct {
+	Response
+	ResultInfo `json:"result_info"`
+	Result     []DeviceSettingsPolicy `json:"result"`
+}
+
+// DeviceSettingsPolicies returns all device settings policies within an
+// account, including the default policy.
+//
+// API reference: https://api.cloudflare.com/#devices-list-device-settings-policies
+func (api *API) DeviceSettingsPolicies(ctx context.Context, accountID string) ([]DeviceSettingsPolicy, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/policies", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []DeviceSettingsPolicy{}, err
+	}
+
+	var deviceSettingsPolicyListResponse DeviceSettingsPolicyListResponse
+	err = json.Unmarshal(res, &deviceSettingsPolicyListResponse)
+	if err != nil {
+		return []DeviceSettingsPolicy{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return deviceSettingsPolicyListResponse.Result, nil
+}
+
+// DeviceSettingsPoliciesAll returns all device settings policies within an
+// account, including the default policy, following the pagination until
+// every page has been fetched. If a page after the first one fails, the
+// policies fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#devices-list-device-settings-policies
+func (api *API) DeviceSettingsPoliciesAll(ctx context.Context, accountID string) ([]DeviceSettingsPolicy, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/policies", AccountRouteRoot, accountID)
+
+	var policies []DeviceSettingsPolicy
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, PaginationOptions{Page: params.Page, PerPage: params.PerPage}), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var deviceSettingsPolicyListResponse DeviceSettingsPolicyListResponse
+		err = json.Unmarshal(res, &deviceSettingsPolicyListResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		policies = append(policies, deviceSettingsPolicyListResponse.Result...)
+		return len(deviceSettingsPolicyListResponse.Result), deviceSettingsPolicyListResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []DeviceSettingsPolicy{}, err
+	}
+
+	return policies, err
+}
+
+// DeviceSettingsPolicy returns a single device settings policy.
+//
+// API reference: https://api.cloudflare.com/#devices-get-device-settings-policy-by-id
+func (api *API) DeviceSettingsPolicy(ctx context.Context, accountID, policyID string) (DeviceSettingsPolicy, error) {
+	if policyID == "" {
+		return DeviceSettingsPolicy{}, ErrMissingPolicyID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, policyID)
+
+	return api.deviceSettingsPolicyRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// DefaultDeviceSettingsPolicy returns the default device settings policy,
+// which applies to devices that no other policy matches.
+//
+// API reference: https://api.cloudflare.com/#devices-get-default-device-settings-policy
+func (api *API) DefaultDeviceSettingsPolicy(ctx context.Context, accountID string) (DeviceSettingsPolicy, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/policy", AccountRouteRoot, accountID)
+
+	return api.deviceSettingsPolicyRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// UpdateDefaultDeviceSettingsPolicy updates the default device settings
+// policy. Only the fields that are set are changed; the default policy has
+// no name, match or precedence.
+//
+// API reference: https://api.cloudflare.com/#devices-update-default-device-settings-policy
+func (api *API) UpdateDefaultDeviceSettingsPolicy(ctx context.Context, accountID string, policy DeviceSettingsPolicy) (DeviceSettingsPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return DeviceSettingsPolicy{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy", AccountRouteRoot, accountID)
+
+	return api.deviceSettingsPolicyRequest(ctx, http.MethodPatch, uri, policy)
+}
+
+// CreateDeviceSettingsPolicy creates a new device settings policy.
+//
+// API reference: https://api.cloudflare.com/#devices-create-device-settings-policy
+func (api *API) CreateDeviceSettingsPolicy(ctx context.Context, accountID string, policy DeviceSettingsPolicy) (DeviceSettingsPolicy, error) {
+	if err := policy.Validate(); err != nil {
+		return DeviceSettingsPolicy{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy", AccountRouteRoot, accountID)
+
+	return api.deviceSettingsPolicyRequest(ctx, http.MethodPost, uri, policy)
+}
+
+// UpdateDeviceSettingsPolicy updates an existing device settings policy.
+// Only the fields that are set are changed.
+//
+// API reference: https://api.cloudflare.com/#devices-update-device-settings-policy
+func (api *API) UpdateDeviceSettingsPolicy(ctx context.Context, accountID string, policy DeviceSettingsPolicy) (DeviceSettingsPolicy, error) {
+	if policy.PolicyID == "" {
+		return DeviceSettingsPolicy{}, ErrMissingPolicyID
+	}
+
+	if err := policy.Validate(); err != nil {
+		return DeviceSettingsPolicy{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, policy.PolicyID)
+
+	return api.deviceSettingsPolicyRequest(ctx, http.MethodPatch, uri, policy)
+}
+
+// DeleteDeviceSettingsPolicy deletes a device settings policy. The default
+// policy cannot be deleted.
+//
+// API reference: https://api.cloudflare.com/#devices-delete-device-settings-policy
+func (api *API) DeleteDeviceSettingsPolicy(ctx context.Context, accountID, policyID string) error {
+	if policyID == "" {
+		return ErrMissingPolicyID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, policyID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (api *API) deviceSettingsPolicyRequest(ctx context.Context, method, uri string, params interface{}) (DeviceSettingsPolicy, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return DeviceSettingsPolicy{}, err
+	}
+
+	var deviceSettingsPolicyResponse DeviceSettingsPolicyResponse
+	err = json.Unmarshal(res, &deviceSettingsPolicyResponse)
+	if err != nil {
+		return DeviceSettingsPolicy{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return deviceSettingsPolicyResponse.Result, nil
+}
+
+// deviceSettingsPolicyPrecedenceStep is the gap left between the policy
+// precedences assigned by ReorderDeviceSettingsPolicies.
+const deviceSettingsPolicyPrecedenceStep = 100
+
+// ReorderDeviceSettingsPolicies assigns ascending precedences to the
+// account's device settings policies in the order of orderedPolicyIDs, which
+// must list every policy except the default one exactly once. The default
+// policy has no precedence, it applies when no other policy matches, so
+// including it is an error. The policies are first moved above all current
+// precedences so no update collides with another policy's precedence.
+//
+// API reference: https://api.cloudflare.com/#devices-update-device-settings-policy
+func (api *API) ReorderDeviceSettingsPolicies(ctx context.Context, accountID string, orderedPolicyIDs []string) ([]DeviceSettingsPolicy, error) {
+	policies, err := api.DeviceSettingsPoliciesAll(ctx, accountID)
+	if err != nil {
+		return []DeviceSettingsPolicy{}, err
+	}
+
+	existing := make(map[string]DeviceSettingsPolicy, len(policies))
+	defaults := make(map[string]bool)
+	maxPrecedence := 0
+	for _, policy := range policies {
+		if policy.Default {
+			defaults[policy.PolicyID] = true
+			continue
+		}
+		existing[policy.PolicyID] = policy
+		if policy.Precedence > maxPrecedence {
+			maxPrecedence = policy.Precedence
+		}
+	}
+
+	seen := make(map[string]bool, len(orderedPolicyIDs))
+	var duplicates, unknown []string
+	for _, id := range orderedPolicyIDs {
+		if defaults[id] {
+			return []DeviceSettingsPolicy{}, fmt.Errorf("the default policy %s has no precedence and can't be reordered", id)
+		}
+		if seen[id] {
+			duplicates = append(duplicates, id)
+			continue
+		}
+		seen[id] = true
+		if _, ok := existing[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(duplicates) > 0 {
+		return []DeviceSettingsPolicy{}, fmt.Errorf("duplicate policy IDs in order: %s", strings.Join(duplicates, ", "))
+	}
+	if len(unknown) > 0 {
+		return []DeviceSettingsPolicy{}, fmt.Errorf("unknown policy IDs in order: %s", strings.Join(unknown, ", "))
+	}
+
+	var missing []string
+	for _, policy := range policies {
+		if !policy.Default && !seen[policy.PolicyID] {
+			missing = append(missing, policy.PolicyID)
+		}
+	}
+	if len(missing) > 0 {
+		return []DeviceSettingsPolicy{}, fmt.Errorf("policy IDs missing from order: %s", strings.Join(missing, ", "))
+	}
+
+	temporaryBase := len(orderedPolicyIDs) * deviceSettingsPolicyPrecedenceStep
+	if maxPrecedence > temporaryBase {
+		temporaryBase = maxPrecedence
+	}
+
+	for i, id := range orderedPolicyIDs {
+		policy := DeviceSettingsPolicy{PolicyID: id, Precedence: temporaryBase + i + 1}
+		if _, err := api.UpdateDeviceSettingsPolicy(ctx, accountID, policy); err != nil {
+			return []DeviceSettingsPolicy{}, err
+		}
+	}
+
+	reordered := make([]DeviceSettingsPolicy, 0, len(orderedPolicyIDs))
+	for i, id := range orderedPolicyIDs {
+		policy := DeviceSettingsPolicy{PolicyID: id, Precedence: (i + 1) * deviceSettingsPolicyPrecedenceStep}
+		updated, err := api.UpdateDeviceSettingsPolicy(ctx, accountID, policy)
+		if err != nil {
+			return []DeviceSettingsPolicy{}, err
+		}
+		reordered = append(reordered, updated)
+	}
+
+	return reordered, nil
+}
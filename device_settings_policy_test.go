@@ -0,0 +1,429 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDeviceSettingsPolicyID = "a842fa8a-a583-482e-9cd9-eb43362949fd"
+
+func TestDeviceSettingsPolicies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"policy_id": "a842fa8a-a583-482e-9cd9-eb43362949fd",
+				"name": "engineering",
+				"match": "identity.email == \"test@example.com\"",
+				"precedence": 10,
+				"enabled": true,
+				"allow_mode_switch": false,
+				"switch_locked": true,
+				"captive_portal": 180,
+				"allow_updates": true,
+				"service_mode_v2": {"mode": "proxy", "port": 3000}
+			}]
+		}`)
+	}
+
+	want := DeviceSettingsPolicy{
+		PolicyID:        testDeviceSettingsPolicyID,
+		Name:            "engineering",
+		Match:           `identity.email == "test@example.com"`,
+		Precedence:      10,
+		Enabled:         BoolPtr(true),
+		AllowModeSwitch: BoolPtr(false),
+		SwitchLocked:    BoolPtr(true),
+		CaptivePortal:   IntPtr(180),
+		AllowUpdates:    BoolPtr(true),
+		ServiceModeV2:   &ServiceModeV2{Mode: ServiceModeProxy, Port: 3000},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policies", handler)
+
+	actual, err := client.DeviceSettingsPolicies(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []DeviceSettingsPolicy{want}, actual)
+	}
+}
+
+func TestDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"policy_id": "a842fa8a-a583-482e-9cd9-eb43362949fd",
+				"name": "engineering",
+				"match": "identity.email == \"test@example.com\"",
+				"precedence": 10,
+				"enabled": true,
+				"allow_mode_switch": false,
+				"switch_locked": true,
+				"captive_portal": 180,
+				"allow_updates": true,
+				"service_mode_v2": {"mode": "proxy", "port": 3000}
+			}
+		}`)
+	}
+
+	want := DeviceSettingsPolicy{
+		PolicyID:        testDeviceSettingsPolicyID,
+		Name:            "engineering",
+		Match:           `identity.email == "test@example.com"`,
+		Precedence:      10,
+		Enabled:         BoolPtr(true),
+		AllowModeSwitch: BoolPtr(false),
+		SwitchLocked:    BoolPtr(true),
+		CaptivePortal:   IntPtr(180),
+		AllowUpdates:    BoolPtr(true),
+		ServiceModeV2:   &ServiceModeV2{Mode: ServiceModeProxy, Port: 3000},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+testDeviceSettingsPolicyID, handler)
+
+	actual, err := client.DeviceSettingsPolicy(context.Background(), testAccountID, testDeviceSettingsPolicyID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.DeviceSettingsPolicy(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingPolicyID, err)
+}
+
+func TestCreateDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"policy_id": "a842fa8a-a583-482e-9cd9-eb43362949fd",
+				"name": "engineering",
+				"match": "identity.email == \"test@example.com\"",
+				"precedence": 10,
+				"enabled": true,
+				"allow_mode_switch": false,
+				"switch_locked": true,
+				"captive_portal": 180,
+				"allow_updates": true,
+				"service_mode_v2": {"mode": "proxy", "port": 3000}
+			}
+		}`)
+	}
+
+	want := DeviceSettingsPolicy{
+		PolicyID:        testDeviceSettingsPolicyID,
+		Name:            "engineering",
+		Match:           `identity.email == "test@example.com"`,
+		Precedence:      10,
+		Enabled:         BoolPtr(true),
+		AllowModeSwitch: BoolPtr(false),
+		SwitchLocked:    BoolPtr(true),
+		CaptivePortal:   IntPtr(180),
+		AllowUpdates:    BoolPtr(true),
+		ServiceModeV2:   &ServiceModeV2{Mode: ServiceModeProxy, Port: 3000},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy", handler)
+
+	policy := want
+	policy.PolicyID = ""
+	actual, err := client.CreateDeviceSettingsPolicy(context.Background(), testAccountID, policy)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestUpdateDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, fmt.Sprintf(`{"policy_id":"%s","switch_locked":true}`, testDeviceSettingsPolicyID), string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"policy_id": "a842fa8a-a583-482e-9cd9-eb43362949fd",
+				"name": "engineering",
+				"match": "identity.email == \"test@example.com\"",
+				"precedence": 10,
+				"enabled": true,
+				"allow_mode_switch": false,
+				"switch_locked": true,
+				"captive_portal": 180,
+				"allow_updates": true,
+				"service_mode_v2": {"mode": "proxy", "port": 3000}
+			}
+		}`)
+	}
+
+	want := DeviceSettingsPolicy{
+		PolicyID:        testDeviceSettingsPolicyID,
+		Name:            "engineering",
+		Match:           `identity.email == "test@example.com"`,
+		Precedence:      10,
+		Enabled:         BoolPtr(true),
+		AllowModeSwitch: BoolPtr(false),
+		SwitchLocked:    BoolPtr(true),
+		CaptivePortal:   IntPtr(180),
+		AllowUpdates:    BoolPtr(true),
+		ServiceModeV2:   &ServiceModeV2{Mode: ServiceModeProxy, Port: 3000},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+testDeviceSettingsPolicyID, handler)
+
+	actual, err := client.UpdateDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{
+		PolicyID:     testDeviceSettingsPolicyID,
+		SwitchLocked: BoolPtr(true),
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.UpdateDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{})
+	assert.Equal(t, ErrMissingPolicyID, err)
+}
+
+func TestDeleteDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": []
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+testDeviceSettingsPolicyID, handler)
+
+	err := client.DeleteDeviceSettingsPolicy(context.Background(), testAccountID, testDeviceSettingsPolicyID)
+	assert.NoError(t, err)
+
+	err = client.DeleteDeviceSettingsPolicy(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingPolicyID, err)
+}
+
+func TestCreateDeviceSettingsPolicyInvalidServiceMode(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.CreateDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{
+		Name:          "kiosk",
+		ServiceModeV2: &ServiceModeV2{Mode: ServiceModeWARP, Port: 3000},
+	})
+	assert.EqualError(t, err, `service mode port can only be set in "proxy" mode, got mode "warp"`)
+}
+
+func TestDeviceSettingsPolicyTunnelProtocol(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"policy_id":"`+testDeviceSettingsPolicyID+`","tunnel_protocol":"wireguard","disable_auto_fallback":true}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"policy_id": "%s", "tunnel_protocol": "wireguard", "disable_auto_fallback": true}
+		}`, testDeviceSettingsPolicyID)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+testDeviceSettingsPolicyID, handler)
+
+	policy, err := client.UpdateDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{
+		PolicyID:            testDeviceSettingsPolicyID,
+		TunnelProtocol:      StringPtr(TunnelProtocolWireGuard),
+		DisableAutoFallback: BoolPtr(true),
+	})
+	if assert.NoError(t, err) {
+		assert.Equal(t, StringPtr(TunnelProtocolWireGuard), policy.TunnelProtocol)
+		assert.Equal(t, BoolPtr(true), policy.DisableAutoFallback)
+	}
+
+	_, err = client.UpdateDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{
+		PolicyID:       testDeviceSettingsPolicyID,
+		TunnelProtocol: StringPtr("openvpn"),
+	})
+	assert.EqualError(t, err, `invalid tunnel protocol "openvpn", must be one of wireguard, masque`)
+}
+
+func TestReorderDeviceSettingsPolicies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policies", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"policy_id": "default", "name": "Default", "default": true},
+					{"policy_id": "a", "name": "A", "precedence": 10}
+				],
+				"result_info": {"page": 1, "per_page": 2, "count": 2, "total_count": 3, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"policy_id": "b", "name": "B", "precedence": 20}],
+			"result_info": {"page": 2, "per_page": 2, "count": 1, "total_count": 3, "total_pages": 2}
+		}`)
+	})
+
+	var updates []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		updates = append(updates, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+	}
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/a", handler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/b", handler)
+
+	actual, err := client.ReorderDeviceSettingsPolicies(context.Background(), testAccountID, []string{"b", "a"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []DeviceSettingsPolicy{
+			{PolicyID: "b", Precedence: 100},
+			{PolicyID: "a", Precedence: 200},
+		}, actual)
+		assert.Equal(t, []string{
+			`{"policy_id":"b","precedence":201}`,
+			`{"policy_id":"a","precedence":202}`,
+			`{"policy_id":"b","precedence":100}`,
+			`{"policy_id":"a","precedence":200}`,
+		}, updates)
+	}
+
+	_, err = client.ReorderDeviceSettingsPolicies(context.Background(), testAccountID, []string{"default", "a", "b"})
+	assert.EqualError(t, err, "the default policy default has no precedence and can't be reordered")
+
+	_, err = client.ReorderDeviceSettingsPolicies(context.Background(), testAccountID, []string{"a"})
+	assert.EqualError(t, err, "policy IDs missing from order: b")
+
+	_, err = client.ReorderDeviceSettingsPolicies(context.Background(), testAccountID, []string{"a", "b", "c", "a"})
+	assert.EqualError(t, err, "duplicate policy IDs in order: a")
+}
+
+func TestDefaultDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"default": true,
+				"enabled": true,
+				"captive_portal": 180,
+				"allow_mode_switch": true,
+				"support_url": "https://help.example.com",
+				"auto_connect": 600
+			}
+		}`)
+	})
+
+	actual, err := client.DefaultDeviceSettingsPolicy(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DeviceSettingsPolicy{
+			Default:         true,
+			Enabled:         BoolPtr(true),
+			CaptivePortal:   IntPtr(180),
+			AllowModeSwitch: BoolPtr(true),
+			SupportURL:      StringPtr("https://help.example.com"),
+			AutoConnect:     IntPtr(600),
+		}, actual)
+	}
+}
+
+func TestUpdateDefaultDeviceSettingsPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"allow_mode_switch":false,"switch_locked":true,"captive_portal":300,"support_url":"https://help.example.com","auto_connect":0,"allowed_to_leave":false}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s
+		}`, body)
+	})
+
+	policy := DeviceSettingsPolicy{
+		AllowModeSwitch: BoolPtr(false),
+		SwitchLocked:    BoolPtr(true),
+		CaptivePortal:   IntPtr(300),
+		SupportURL:      StringPtr("https://help.example.com"),
+		AutoConnect:     IntPtr(0),
+		AllowedToLeave:  BoolPtr(false),
+	}
+	actual, err := client.UpdateDefaultDeviceSettingsPolicy(context.Background(), testAccountID, policy)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, policy, actual)
+	}
+
+	_, err = client.UpdateDefaultDeviceSettingsPolicy(context.Background(), testAccountID, DeviceSettingsPolicy{AutoConnect: IntPtr(-1)})
+	assert.EqualError(t, err, "auto connect must be 0 or more seconds, got -1")
+}
@@ -0,0 +1,174 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+var ErrMissingDatasetID = errors.New("required missing dataset ID")
+
+// DLPDataset represents a DLP exact data match dataset.
+type DLPDataset struct {
+	ID          string `json:"id,omitempty"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+	Status      string `json:"status,omitempty"`
+	NumCells    int    `json:"num_cells,omitempty"`
+	// Secret reports whether the dataset cells are hashed with a secret
+	// before upload.
+	Secret *bool `json:"secret,omitempty"`
+
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// DLPDatasetCreateResult is the result of creating a dataset. Secret is only
+// returned once and is needed to hash the cells of secret datasets.
+type DLPDatasetCreateResult struct {
+	Dataset  DLPDataset `json:"dataset"`
+	MaxCells int        `json:"max_cells"`
+	Secret   string     `json:"secret,omitempty"`
+	Version  int        `json:"version"`
+}
+
+// DLPDatasetUploadVersion is a new, not yet uploaded, version of a dataset.
+type DLPDatasetUploadVersion struct {
+	MaxCells int    `json:"max_cells"`
+	Secret   string `json:"secret,omitempty"`
+	Version  int    `json:"version"`
+}
+
+// DLPDatasetListResponse represents the response from the list DLP datasets
+// endpoint.
+type DLPDatasetListResponse struct {
+	Result []DLPDataset `json:"result"`
+	Response
+}
+
+// DLPDatasetResponse is the API response, containing a single DLP dataset.
+type DLPDatasetResponse struct {
+	Result DLPDataset `json:"result"`
+	Response
+}
+
+// DLPDatasetCreateResponse is the API response of creating a DLP dataset.
+type DLPDatasetCreateResponse struct {
+	Result DLPDatasetCreateResult `json:"result"`
+	Response
+}
+
+// DLPDatasetUploadVersionResponse is the API response of creating a new
+// version of a DLP dataset.
+type DLPDatasetUploadVersionResponse struct {
+	Result DLPDatasetUploadVersion `json:"result"`
+	Response
+}
+
+// DLPDatasets returns all DLP datasets within an account.
+//
+// API reference: https://api.cloudflare.com/#dlp-datasets-read-all
+func (api *API) DLPDatasets(ctx context.Context, accountID string) ([]DLPDataset, error) {
+	uri := fmt.Sprintf("/%s/%s/dlp/datasets", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []DLPDataset{}, err
+	}
+
+	var dlpDatasetListResponse DLPDatasetListResponse
+	err = json.Unmarshal(res, &dlpDatasetListResponse)
+	if err != nil {
+		return []DLPDataset{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpDatasetListResponse.Result, nil
+}
+
+// CreateDLPDataset creates a DLP dataset. The dataset has no cells until a
+// version is uploaded with UploadDLPDatasetVersion.
+//
+// API reference: https://api.cloudflare.com/#dlp-datasets-create
+func (api *API) CreateDLPDataset(ctx context.Context, accountID string, dataset DLPDataset) (DLPDatasetCreateResult, error) {
+	uri := fmt.Sprintf("/%s/%s/dlp/datasets", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, dataset)
+	if err != nil {
+		return DLPDatasetCreateResult{}, err
+	}
+
+	var dlpDatasetCreateResponse DLPDatasetCreateResponse
+	err = json.Unmarshal(res, &dlpDatasetCreateResponse)
+	if err != nil {
+		return DLPDatasetCreateResult{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpDatasetCreateResponse.Result, nil
+}
+
+// DeleteDLPDataset deletes a DLP dataset.
+//
+// API reference: https://api.cloudflare.com/#dlp-datasets-delete
+func (api *API) DeleteDLPDataset(ctx context.Context, accountID, datasetID string) error {
+	if datasetID == "" {
+		return ErrMissingDatasetID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/datasets/%s", AccountRouteRoot, accountID, datasetID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UploadDLPDatasetVersion replaces the cells of a dataset with the contents
+// of r. It creates a new version of the dataset and streams r to it as the
+// body of the upload, which makes the version active.
+//
+// As r can only be read once the upload isn't retried by the client. When it
+// fails, the new version is left without its cells and the caller has to
+// upload the dataset again from a fresh reader.
+//
+// API reference: https://api.cloudflare.com/#dlp-datasets-upload-dataset-version
+func (api *API) UploadDLPDatasetVersion(ctx context.Context, accountID, datasetID string, r io.Reader) (DLPDataset, error) {
+	if datasetID == "" {
+		return DLPDataset{}, ErrMissingDatasetID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/datasets/%s/upload", AccountRouteRoot, accountID, datasetID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, nil)
+	if err != nil {
+		return DLPDataset{}, err
+	}
+
+	var dlpDatasetUploadVersionResponse DLPDatasetUploadVersionResponse
+	err = json.Unmarshal(res, &dlpDatasetUploadVersionResponse)
+	if err != nil {
+		return DLPDataset{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	uri = fmt.Sprintf("%s/%d", uri, dlpDatasetUploadVersionResponse.Result.Version)
+
+	res, err = api.makeRequestContextWithHeaders(
+		withoutRetry(ctx), http.MethodPut, uri, r, http.Header{"Content-Type": []string{"application/octet-stream"}},
+	)
+	if err != nil {
+		return DLPDataset{}, err
+	}
+
+	var dlpDatasetResponse DLPDatasetResponse
+	err = json.Unmarshal(res, &dlpDatasetResponse)
+	if err != nil {
+		return DLPDataset{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpDatasetResponse.Result, nil
+}
@@ -0,0 +1,208 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDLPDatasetID = "497ed5ea-1ccd-4cf4-9c5b-39bd8d0e4f4d"
+
+func TestDLPDatasets(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "497ed5ea-1ccd-4cf4-9c5b-39bd8d0e4f4d",
+				"name": "employee ids",
+				"description": "exact data match for employee ids",
+				"status": "complete",
+				"num_cells": 3,
+				"secret": false
+			}]
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets", handler)
+
+	actual, err := client.DLPDatasets(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []DLPDataset{{
+			ID:          testDLPDatasetID,
+			Name:        "employee ids",
+			Description: "exact data match for employee ids",
+			Status:      "complete",
+			NumCells:    3,
+			Secret:      BoolPtr(false),
+		}}, actual)
+	}
+}
+
+func TestCreateDLPDataset(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"name":"employee ids","secret":true}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"dataset": {
+					"id": "497ed5ea-1ccd-4cf4-9c5b-39bd8d0e4f4d",
+					"name": "employee ids",
+					"description": "exact data match for employee ids",
+					"status": "complete",
+					"num_cells": 3,
+					"secret": false
+				},
+				"max_cells": 100000,
+				"secret": "c2VjcmV0",
+				"version": 1
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets", handler)
+
+	actual, err := client.CreateDLPDataset(context.Background(), testAccountID, DLPDataset{Name: "employee ids", Secret: BoolPtr(true)})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DLPDatasetCreateResult{
+			Dataset: DLPDataset{
+				ID:          testDLPDatasetID,
+				Name:        "employee ids",
+				Description: "exact data match for employee ids",
+				Status:      "complete",
+				NumCells:    3,
+				Secret:      BoolPtr(false),
+			},
+			MaxCells: 100000,
+			Secret:   "c2VjcmV0",
+			Version:  1,
+		}, actual)
+	}
+}
+
+func TestDeleteDLPDataset(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": null
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets/"+testDLPDatasetID, handler)
+
+	err := client.DeleteDLPDataset(context.Background(), testAccountID, testDLPDatasetID)
+	assert.NoError(t, err)
+
+	err = client.DeleteDLPDataset(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingDatasetID, err)
+}
+
+func TestUploadDLPDatasetVersion(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets/"+testDLPDatasetID+"/upload", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"max_cells": 100000, "version": 2}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets/"+testDLPDatasetID+"/upload/2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "1001\n1002\n1003\n", string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "497ed5ea-1ccd-4cf4-9c5b-39bd8d0e4f4d",
+				"name": "employee ids",
+				"description": "exact data match for employee ids",
+				"status": "complete",
+				"num_cells": 3,
+				"secret": false
+			}
+		}`)
+	})
+
+	actual, err := client.UploadDLPDatasetVersion(context.Background(), testAccountID, testDLPDatasetID, strings.NewReader("1001\n1002\n1003\n"))
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DLPDataset{
+			ID:          testDLPDatasetID,
+			Name:        "employee ids",
+			Description: "exact data match for employee ids",
+			Status:      "complete",
+			NumCells:    3,
+			Secret:      BoolPtr(false),
+		}, actual)
+	}
+
+	_, err = client.UploadDLPDatasetVersion(context.Background(), testAccountID, "", strings.NewReader(""))
+	assert.Equal(t, ErrMissingDatasetID, err)
+}
+
+func TestUploadDLPDatasetVersionIsNotRetried(t *testing.T) {
+	setup(UsingRetryPolicy(3, 0, 0))
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets/"+testDLPDatasetID+"/upload", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"max_cells": 100000, "version": 2}}`)
+	})
+
+	uploads := 0
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets/"+testDLPDatasetID+"/upload/2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		uploads++
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusInternalServerError)
+		fmt.Fprint(w, `{"success": false, "errors": [{"code": 1000, "message": "internal error"}], "messages": [], "result": null}`)
+	})
+
+	_, err := client.UploadDLPDatasetVersion(context.Background(), testAccountID, testDLPDatasetID, strings.NewReader("1001\n1002\n1003\n"))
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, uploads)
+}
@@ -0,0 +1,104 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// dlpPayloadLogPublicKeySize is the size of a NaCl box public key.
+const dlpPayloadLogPublicKeySize = 32
+
+// DLPPayloadLogSettings holds the public key used to encrypt the payloads
+// of requests matched by DLP rules. An empty PublicKey disables payload
+// logging.
+type DLPPayloadLogSettings struct {
+	PublicKey string     `json:"public_key"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// DLPPayloadLogSettingsResponse is the API response, containing the DLP
+// payload log settings.
+type DLPPayloadLogSettingsResponse struct {
+	Response
+	Result DLPPayloadLogSettings `json:"result"`
+}
+
+// Validate checks that PublicKey, when set, is a base64 encoded 32 byte key.
+func (s DLPPayloadLogSettings) Validate() error {
+	if s.PublicKey == "" {
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(s.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid DLP payload log public key: %w", err)
+	}
+
+	if len(key) != dlpPayloadLogPublicKeySize {
+		return fmt.Errorf("invalid DLP payload log public key: must be %d bytes, got %d", dlpPayloadLogPublicKeySize, len(key))
+	}
+
+	return nil
+}
+
+// Fingerprint returns the hex encoded SHA-256 digest of the decoded public
+// key, or an empty string when no key is set.
+func (s DLPPayloadLogSettings) Fingerprint() (string, error) {
+	if s.PublicKey == "" {
+		return "", nil
+	}
+
+	if err := s.Validate(); err != nil {
+		return "", err
+	}
+
+	key, _ := base64.StdEncoding.DecodeString(s.PublicKey)
+	sum := sha256.Sum256(key)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// GetDLPPayloadLogSettings returns the DLP payload log settings of an
+// account.
+//
+// API reference: https://api.cloudflare.com/#dlp-payload-log-settings-get-settings
+func (api *API) GetDLPPayloadLogSettings(ctx context.Context, accountID string) (DLPPayloadLogSettings, error) {
+	uri := fmt.Sprintf("/%s/%s/dlp/payload_log", AccountRouteRoot, accountID)
+
+	return api.dlpPayloadLogSettingsRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// UpdateDLPPayloadLogSettings sets the public key used for DLP payload
+// logging.
+//
+// API reference: https://api.cloudflare.com/#dlp-payload-log-settings-update-settings
+func (api *API) UpdateDLPPayloadLogSettings(ctx context.Context, accountID string, settings DLPPayloadLogSettings) (DLPPayloadLogSettings, error) {
+	if err := settings.Validate(); err != nil {
+		return DLPPayloadLogSettings{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/payload_log", AccountRouteRoot, accountID)
+
+	return api.dlpPayloadLogSettingsRequest(ctx, http.MethodPut, uri, DLPPayloadLogSettings{PublicKey: settings.PublicKey})
+}
+
+func (api *API) dlpPayloadLogSettingsRequest(ctx context.Context, method, uri string, params interface{}) (DLPPayloadLogSettings, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return DLPPayloadLogSettings{}, err
+	}
+
+	var dlpPayloadLogSettingsResponse DLPPayloadLogSettingsResponse
+	err = json.Unmarshal(res, &dlpPayloadLogSettingsResponse)
+	if err != nil {
+		return DLPPayloadLogSettings{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpPayloadLogSettingsResponse.Result, nil
+}
@@ -0,0 +1,88 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testDLPPayloadLogPublicKey = "EmpOvSXw8BfbrGCi0fhGiD/3yXk2SiV1Nzg2lru3oj0="
+
+func TestGetDLPPayloadLogSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"public_key": "%s",
+				"updated_at": "2022-12-22T21:02:39Z"
+			}
+		}`, testDLPPayloadLogPublicKey)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/payload_log", handler)
+
+	updatedAt := time.Date(2022, 12, 22, 21, 2, 39, 0, time.UTC)
+	want := DLPPayloadLogSettings{
+		PublicKey: testDLPPayloadLogPublicKey,
+		UpdatedAt: &updatedAt,
+	}
+
+	actual, err := client.GetDLPPayloadLogSettings(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+
+		fingerprint, err := actual.Fingerprint()
+		assert.NoError(t, err)
+		assert.Len(t, fingerprint, 64)
+	}
+}
+
+func TestUpdateDLPPayloadLogSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, fmt.Sprintf(`{"public_key":"%s"}`, testDLPPayloadLogPublicKey), string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"public_key": "%s"
+			}
+		}`, testDLPPayloadLogPublicKey)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/payload_log", handler)
+
+	actual, err := client.UpdateDLPPayloadLogSettings(context.Background(), testAccountID, DLPPayloadLogSettings{
+		PublicKey: testDLPPayloadLogPublicKey,
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, DLPPayloadLogSettings{PublicKey: testDLPPayloadLogPublicKey}, actual)
+	}
+
+	_, err = client.UpdateDLPPayloadLogSettings(context.Background(), testAccountID, DLPPayloadLogSettings{
+		PublicKey: "dG9vIHNob3J0",
+	})
+	assert.EqualError(t, err, "invalid DLP payload log public key: must be 32 bytes, got 9")
+}
@@ -0,0 +1,295 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"time"
+)
+
+var ErrMissingProfileID = errors.New("required missing profile ID")
+
+const (
+	DLPProfileTypePredefined = "predefined"
+	DLPProfileTypeCustom     = "custom"
+)
+
+// The range of matches a profile can allow before a request is blocked.
+const (
+	DLPMinAllowedMatchCount = 0
+	DLPMaxAllowedMatchCount = 40
+)
+
+// DLPPattern represents a DLP Pattern that matches an entry.
+type DLPPattern struct {
+	Regex      string `json:"regex,omitempty"`
+	Validation string `json:"validation,omitempty"`
+}
+
+// DLPEntry represents a DLP Entry, which can be matched in HTTP bodies or files.
+type DLPEntry struct {
+	ID        string `json:"id,omitempty"`
+	Name      string `json:"name,omitempty"`
+	ProfileID string `json:"profile_id,omitempty"`
+	Enabled   *bool  `json:"enabled,omitempty"`
+	Type      string `json:"type,omitempty"`
+
+	Pattern   *DLPPattern `json:"pattern,omitempty"`
+	CreatedAt *time.Time  `json:"created_at,omitempty"`
+	UpdatedAt *time.Time  `json:"updated_at,omitempty"`
+}
+
+// DLPProfile represents a DLP Profile, which contains a set
+// of entries.
+type DLPProfile struct {
+	ID                string `json:"id,omitempty"`
+	Name              string `json:"name,omitempty"`
+	Type              string `json:"type,omitempty"`
+	Description       string `json:"description,omitempty"`
+	AllowedMatchCount int    `json:"allowed_match_count"`
+	OCREnabled        *bool  `json:"ocr_enabled,omitempty"`
+
+	Entries []DLPEntry `json:"entries,omitempty"`
+
+	// The following fields are omitted for predefined DLP
+	// profiles
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// DLPProfilesCreateRequest represents a request to create a
+// set of profiles.
+type DLPProfilesCreateRequest struct {
+	Profiles []DLPProfile `json:"profiles"`
+}
+
+// DLPProfileListResponse represents the response from the list
+// dlp profiles endpoint.
+type DLPProfileListResponse struct {
+	Result []DLPProfile `json:"result"`
+	Response
+}
+
+// DLPProfileResponse is the API response, containing a single
+// DLP profile.
+type DLPProfileResponse struct {
+	Result DLPProfile `json:"result"`
+	Response
+}
+
+// DLPProfiles returns all DLP profiles within an account, both predefined
+// and custom.
+//
+// API reference: https://api.cloudflare.com/#dlp-profiles-list-all-profiles
+func (api *API) DLPProfiles(ctx context.Context, accountID string) ([]DLPProfile, error) {
+	uri := fmt.Sprintf("/%s/%s/dlp/profiles", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []DLPProfile{}, err
+	}
+
+	var dlpProfileListResponse DLPProfileListResponse
+	err = json.Unmarshal(res, &dlpProfileListResponse)
+	if err != nil {
+		return []DLPProfile{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpProfileListResponse.Result, nil
+}
+
+// DLPProfile returns a single DLP profile (custom or predefined) based on
+// the profile ID.
+//
+// API reference: https://api.cloudflare.com/#dlp-profiles-get-dlp-profile
+func (api *API) DLPProfile(ctx context.Context, accountID, profileID string) (DLPProfile, error) {
+	if profileID == "" {
+		return DLPProfile{}, ErrMissingProfileID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/profiles/%s", AccountRouteRoot, accountID, profileID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return DLPProfile{}, err
+	}
+
+	var dlpProfileResponse DLPProfileResponse
+	err = json.Unmarshal(res, &dlpProfileResponse)
+	if err != nil {
+		return DLPProfile{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpProfileResponse.Result, nil
+}
+
+// CreateDLPProfile creates a custom DLP profile.
+//
+// API reference: https://api.cloudflare.com/#dlp-profiles-create-custom-profiles
+func (api *API) CreateDLPProfile(ctx context.Context, accountID string, profile DLPProfile) (DLPProfile, error) {
+	if profile.Type != "" && profile.Type != DLPProfileTypeCustom {
+		return DLPProfile{}, fmt.Errorf("only custom DLP profiles can be created")
+	}
+
+	if err := validateDLPProfile(profile); err != nil {
+		return DLPProfile{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/profiles/custom", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, DLPProfilesCreateRequest{Profiles: []DLPProfile{profile}})
+	if err != nil {
+		return DLPProfile{}, err
+	}
+
+	var dlpProfileListResponse DLPProfileListResponse
+	err = json.Unmarshal(res, &dlpProfileListResponse)
+	if err != nil {
+		return DLPProfile{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	if len(dlpProfileListResponse.Result) == 0 {
+		return DLPProfile{}, fmt.Errorf("no DLP profile returned from the API")
+	}
+
+	return dlpProfileListResponse.Result[0], nil
+}
+
+// UpdateDLPProfile updates a DLP profile.
+//
+// Predefined profiles only allow AllowedMatchCount and the enablement of
+// their entries to be changed; an error is returned if any other field
+// differs from the current profile.
+//
+// API reference: https://api.cloudflare.com/#dlp-profiles-update-custom-profile
+// API reference: https://api.cloudflare.com/#dlp-profiles-update-predefined-profile
+func (api *API) UpdateDLPProfile(ctx context.Context, accountID string, profile DLPProfile) (DLPProfile, error) {
+	if profile.ID == "" {
+		return DLPProfile{}, ErrMissingProfileID
+	}
+
+	profileType := profile.Type
+	if profileType == "" {
+		profileType = DLPProfileTypeCustom
+	}
+
+	if err := validateDLPProfile(profile); err != nil {
+		return DLPProfile{}, err
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/profiles/%s/%s", AccountRouteRoot, accountID, profileType, profile.ID)
+
+	if profileType == DLPProfileTypePredefined {
+		current, err := api.DLPProfile(ctx, accountID, profile.ID)
+		if err != nil {
+			return DLPProfile{}, err
+		}
+
+		if err := validatePredefinedDLPProfileUpdate(current, profile); err != nil {
+			return DLPProfile{}, err
+		}
+
+		entries := make([]DLPEntry, 0, len(profile.Entries))
+		for _, entry := range profile.Entries {
+			entries = append(entries, DLPEntry{ID: entry.ID, Enabled: entry.Enabled})
+		}
+
+		profile = DLPProfile{
+			AllowedMatchCount: profile.AllowedMatchCount,
+			Entries:           entries,
+		}
+	}
+
+	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, profile)
+	if err != nil {
+		return DLPProfile{}, err
+	}
+
+	var dlpProfileResponse DLPProfileResponse
+	err = json.Unmarshal(res, &dlpProfileResponse)
+	if err != nil {
+		return DLPProfile{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return dlpProfileResponse.Result, nil
+}
+
+// validateDLPProfile checks the allowed match count is in range and that
+// the regular expression of every entry compiles, which the API otherwise
+// reports with an unhelpful error. Go's regexp package implements the RE2
+// syntax used by the API.
+func validateDLPProfile(profile DLPProfile) error {
+	if profile.AllowedMatchCount < DLPMinAllowedMatchCount || profile.AllowedMatchCount > DLPMaxAllowedMatchCount {
+		return fmt.Errorf("invalid allowed match count %d: must be between %d and %d", profile.AllowedMatchCount, DLPMinAllowedMatchCount, DLPMaxAllowedMatchCount)
+	}
+
+	for _, entry := range profile.Entries {
+		if entry.Pattern == nil {
+			continue
+		}
+		if _, err := regexp.Compile(entry.Pattern.Regex); err != nil {
+			return fmt.Errorf("invalid pattern for DLP entry %q: %w", entry.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// validatePredefinedDLPProfileUpdate returns an error if the update changes
+// anything other than the allowed match count or entry enablement.
+func validatePredefinedDLPProfileUpdate(current, update DLPProfile) error {
+	if update.Name != "" && update.Name != current.Name {
+		return fmt.Errorf("predefined DLP profile name cannot be changed")
+	}
+
+	if update.Description != "" && update.Description != current.Description {
+		return fmt.Errorf("predefined DLP profile description cannot be changed")
+	}
+
+	if update.OCREnabled != nil && (current.OCREnabled == nil || *update.OCREnabled != *current.OCREnabled) {
+		return fmt.Errorf("predefined DLP profile OCR setting cannot be changed")
+	}
+
+	entries := make(map[string]DLPEntry, len(current.Entries))
+	for _, entry := range current.Entries {
+		entries[entry.ID] = entry
+	}
+
+	for _, entry := range update.Entries {
+		existing, ok := entries[entry.ID]
+		if !ok {
+			return fmt.Errorf("predefined DLP profile entries cannot be added: %q", entry.ID)
+		}
+
+		if entry.Name != "" && entry.Name != existing.Name {
+			return fmt.Errorf("predefined DLP profile entry %q name cannot be changed", entry.ID)
+		}
+
+		if entry.Pattern != nil && (existing.Pattern == nil || *entry.Pattern != *existing.Pattern) {
+			return fmt.Errorf("predefined DLP profile entry %q pattern cannot be changed", entry.ID)
+		}
+	}
+
+	return nil
+}
+
+// DeleteDLPProfile deletes a custom DLP profile.
+//
+// API reference: https://api.cloudflare.com/#dlp-profiles-delete-custom-profile
+func (api *API) DeleteDLPProfile(ctx context.Context, accountID, profileID string) error {
+	if profileID == "" {
+		return ErrMissingProfileID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/dlp/profiles/custom/%s", AccountRouteRoot, accountID, profileID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,481 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testDLPPredefinedProfileID = "d658f520-6ecb-4a34-a725-ba37243c2d28"
+	testDLPCustomProfileID     = "29678c26-a191-428d-9f63-6e20a4a636a4"
+)
+
+func TestDLPProfiles(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+				"name": "U.S. Social Security Numbers",
+				"type": "predefined",
+				"allowed_match_count": 0,
+				"entries": [
+					{
+						"id": "111b9d4b-a5c6-40f0-957d-9d53b25dd84a",
+						"name": "SSN Numeric Detection",
+						"profile_id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+						"enabled": false,
+						"type": "predefined"
+					}
+				]
+			}, {
+				"id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+				"name": "Generic CVV Card Number",
+				"type": "custom",
+				"description": "custom profile with just a credit card number",
+				"allowed_match_count": 1,
+				"entries": [
+					{
+						"id": "ef79b054-12d4-4067-bb30-b85f6267b91c",
+						"name": "Generic CVV Card Number",
+						"profile_id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+						"enabled": true,
+						"type": "custom",
+						"pattern": {"regex": "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}", "validation": "luhn"},
+						"created_at": "2022-10-18T08:00:56Z",
+						"updated_at": "2022-10-18T08:00:57Z"
+					}
+				],
+				"created_at": "2022-10-18T08:00:56Z",
+				"updated_at": "2022-10-18T08:00:57Z"
+			}]
+		}`)
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:56Z")
+	updatedAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:57Z")
+
+	want := []DLPProfile{
+		{
+			ID:                testDLPPredefinedProfileID,
+			Name:              "U.S. Social Security Numbers",
+			Type:              DLPProfileTypePredefined,
+			AllowedMatchCount: 0,
+			Entries: []DLPEntry{
+				{
+					ID:        "111b9d4b-a5c6-40f0-957d-9d53b25dd84a",
+					Name:      "SSN Numeric Detection",
+					ProfileID: testDLPPredefinedProfileID,
+					Enabled:   BoolPtr(false),
+					Type:      "predefined",
+				},
+			},
+		},
+		{
+			ID:                testDLPCustomProfileID,
+			Name:              "Generic CVV Card Number",
+			Type:              DLPProfileTypeCustom,
+			Description:       "custom profile with just a credit card number",
+			AllowedMatchCount: 1,
+			Entries: []DLPEntry{
+				{
+					ID:        "ef79b054-12d4-4067-bb30-b85f6267b91c",
+					Name:      "Generic CVV Card Number",
+					ProfileID: testDLPCustomProfileID,
+					Enabled:   BoolPtr(true),
+					Type:      "custom",
+					Pattern: &DLPPattern{
+						Regex:      "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}",
+						Validation: "luhn",
+					},
+					CreatedAt: &createdAt,
+					UpdatedAt: &updatedAt,
+				},
+			},
+			CreatedAt: &createdAt,
+			UpdatedAt: &updatedAt,
+		},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles", handler)
+
+	actual, err := client.DLPProfiles(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestDLPProfile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+				"name": "Generic CVV Card Number",
+				"type": "custom",
+				"description": "custom profile with just a credit card number",
+				"allowed_match_count": 1,
+				"entries": [
+					{
+						"id": "ef79b054-12d4-4067-bb30-b85f6267b91c",
+						"name": "Generic CVV Card Number",
+						"profile_id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+						"enabled": true,
+						"type": "custom",
+						"pattern": {"regex": "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}", "validation": "luhn"},
+						"created_at": "2022-10-18T08:00:56Z",
+						"updated_at": "2022-10-18T08:00:57Z"
+					}
+				],
+				"created_at": "2022-10-18T08:00:56Z",
+				"updated_at": "2022-10-18T08:00:57Z"
+			}
+		}`)
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:56Z")
+	updatedAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:57Z")
+
+	want := DLPProfile{
+		ID:                testDLPCustomProfileID,
+		Name:              "Generic CVV Card Number",
+		Type:              DLPProfileTypeCustom,
+		Description:       "custom profile with just a credit card number",
+		AllowedMatchCount: 1,
+		Entries: []DLPEntry{
+			{
+				ID:        "ef79b054-12d4-4067-bb30-b85f6267b91c",
+				Name:      "Generic CVV Card Number",
+				ProfileID: testDLPCustomProfileID,
+				Enabled:   BoolPtr(true),
+				Type:      "custom",
+				Pattern: &DLPPattern{
+					Regex:      "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}",
+					Validation: "luhn",
+				},
+				CreatedAt: &createdAt,
+				UpdatedAt: &updatedAt,
+			},
+		},
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/"+testDLPCustomProfileID, handler)
+
+	actual, err := client.DLPProfile(context.Background(), testAccountID, testDLPCustomProfileID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.DLPProfile(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingProfileID, err)
+}
+
+func TestCreateDLPProfile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+				"name": "Generic CVV Card Number",
+				"type": "custom",
+				"description": "custom profile with just a credit card number",
+				"allowed_match_count": 1,
+				"entries": [
+					{
+						"id": "ef79b054-12d4-4067-bb30-b85f6267b91c",
+						"name": "Generic CVV Card Number",
+						"profile_id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+						"enabled": true,
+						"type": "custom",
+						"pattern": {"regex": "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}", "validation": "luhn"},
+						"created_at": "2022-10-18T08:00:56Z",
+						"updated_at": "2022-10-18T08:00:57Z"
+					}
+				],
+				"created_at": "2022-10-18T08:00:56Z",
+				"updated_at": "2022-10-18T08:00:57Z"
+			}]
+		}`)
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:56Z")
+	updatedAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:57Z")
+
+	want := DLPProfile{
+		ID:                testDLPCustomProfileID,
+		Name:              "Generic CVV Card Number",
+		Type:              DLPProfileTypeCustom,
+		Description:       "custom profile with just a credit card number",
+		AllowedMatchCount: 1,
+		Entries: []DLPEntry{
+			{
+				ID:        "ef79b054-12d4-4067-bb30-b85f6267b91c",
+				Name:      "Generic CVV Card Number",
+				ProfileID: testDLPCustomProfileID,
+				Enabled:   BoolPtr(true),
+				Type:      "custom",
+				Pattern: &DLPPattern{
+					Regex:      "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}",
+					Validation: "luhn",
+				},
+				CreatedAt: &createdAt,
+				UpdatedAt: &updatedAt,
+			},
+		},
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/custom", handler)
+
+	profile := want
+	profile.ID = ""
+	actual, err := client.CreateDLPProfile(context.Background(), testAccountID, profile)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.CreateDLPProfile(context.Background(), testAccountID, DLPProfile{Type: DLPProfileTypePredefined})
+	assert.Error(t, err)
+}
+
+func TestUpdateDLPProfile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+				"name": "Generic CVV Card Number",
+				"type": "custom",
+				"description": "custom profile with just a credit card number",
+				"allowed_match_count": 1,
+				"entries": [
+					{
+						"id": "ef79b054-12d4-4067-bb30-b85f6267b91c",
+						"name": "Generic CVV Card Number",
+						"profile_id": "29678c26-a191-428d-9f63-6e20a4a636a4",
+						"enabled": true,
+						"type": "custom",
+						"pattern": {"regex": "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}", "validation": "luhn"},
+						"created_at": "2022-10-18T08:00:56Z",
+						"updated_at": "2022-10-18T08:00:57Z"
+					}
+				],
+				"created_at": "2022-10-18T08:00:56Z",
+				"updated_at": "2022-10-18T08:00:57Z"
+			}
+		}`)
+	}
+
+	createdAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:56Z")
+	updatedAt, _ := time.Parse(time.RFC3339, "2022-10-18T08:00:57Z")
+
+	want := DLPProfile{
+		ID:                testDLPCustomProfileID,
+		Name:              "Generic CVV Card Number",
+		Type:              DLPProfileTypeCustom,
+		Description:       "custom profile with just a credit card number",
+		AllowedMatchCount: 1,
+		Entries: []DLPEntry{
+			{
+				ID:        "ef79b054-12d4-4067-bb30-b85f6267b91c",
+				Name:      "Generic CVV Card Number",
+				ProfileID: testDLPCustomProfileID,
+				Enabled:   BoolPtr(true),
+				Type:      "custom",
+				Pattern: &DLPPattern{
+					Regex:      "4\\d{3}([-\\. ])?\\d{4}([-\\. ])?\\d{4}([-\\. ])?\\d{4}",
+					Validation: "luhn",
+				},
+				CreatedAt: &createdAt,
+				UpdatedAt: &updatedAt,
+			},
+		},
+		CreatedAt: &createdAt,
+		UpdatedAt: &updatedAt,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/custom/"+testDLPCustomProfileID, handler)
+
+	actual, err := client.UpdateDLPProfile(context.Background(), testAccountID, want)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	_, err = client.UpdateDLPProfile(context.Background(), testAccountID, DLPProfile{})
+	assert.Equal(t, ErrMissingProfileID, err)
+}
+
+func TestUpdateDLPProfilePredefined(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/"+testDLPPredefinedProfileID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+				"name": "U.S. Social Security Numbers",
+				"type": "predefined",
+				"allowed_match_count": 0,
+				"entries": [
+					{
+						"id": "111b9d4b-a5c6-40f0-957d-9d53b25dd84a",
+						"name": "SSN Numeric Detection",
+						"profile_id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+						"enabled": false,
+						"type": "predefined"
+					}
+				]
+			}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/predefined/"+testDLPPredefinedProfileID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"allowed_match_count": 5,
+				"entries": [{"id": "111b9d4b-a5c6-40f0-957d-9d53b25dd84a", "enabled": true}]
+			}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+				"name": "U.S. Social Security Numbers",
+				"type": "predefined",
+				"allowed_match_count": 0,
+				"entries": [
+					{
+						"id": "111b9d4b-a5c6-40f0-957d-9d53b25dd84a",
+						"name": "SSN Numeric Detection",
+						"profile_id": "d658f520-6ecb-4a34-a725-ba37243c2d28",
+						"enabled": false,
+						"type": "predefined"
+					}
+				]
+			}
+		}`)
+	})
+
+	profile := DLPProfile{
+		ID:                testDLPPredefinedProfileID,
+		Name:              "U.S. Social Security Numbers",
+		Type:              DLPProfileTypePredefined,
+		AllowedMatchCount: 5,
+		Entries: []DLPEntry{
+			{
+				ID:        "111b9d4b-a5c6-40f0-957d-9d53b25dd84a",
+				Name:      "SSN Numeric Detection",
+				ProfileID: testDLPPredefinedProfileID,
+				Enabled:   BoolPtr(true),
+				Type:      "predefined",
+			},
+		},
+	}
+
+	_, err := client.UpdateDLPProfile(context.Background(), testAccountID, profile)
+	assert.NoError(t, err)
+
+	profile.Name = "Renamed"
+	_, err = client.UpdateDLPProfile(context.Background(), testAccountID, profile)
+	assert.EqualError(t, err, "predefined DLP profile name cannot be changed")
+}
+
+func TestDeleteDLPProfile(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": null
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/profiles/custom/"+testDLPCustomProfileID, handler)
+
+	err := client.DeleteDLPProfile(context.Background(), testAccountID, testDLPCustomProfileID)
+	assert.NoError(t, err)
+
+	err = client.DeleteDLPProfile(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingProfileID, err)
+}
+
+func TestCreateDLPProfileValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	profile := DLPProfile{
+		Name:              "Generic CVV Card Number",
+		Type:              DLPProfileTypeCustom,
+		AllowedMatchCount: 41,
+		Entries: []DLPEntry{
+			{
+				Name:    "Generic CVV Card Number",
+				Enabled: BoolPtr(true),
+				Pattern: &DLPPattern{Regex: "4\\d{3}", Validation: "luhn"},
+			},
+		},
+	}
+	_, err := client.CreateDLPProfile(context.Background(), testAccountID, profile)
+	assert.EqualError(t, err, "invalid allowed match count 41: must be between 0 and 40")
+
+	profile.AllowedMatchCount = 1
+	profile.Entries[0].Pattern = &DLPPattern{Regex: "4(\\d{3}"}
+	_, err = client.CreateDLPProfile(context.Background(), testAccountID, profile)
+	assert.EqualError(t, err, fmt.Sprintf("invalid pattern for DLP entry %q: error parsing regexp: missing closing ): `4(\\d{3}`", profile.Entries[0].Name))
+}
@@ -302,3 +302,41 @@ func (e *Error) ErrorMessageContains(s string) bool {
 	}
 	return false
 }
+
+// ErrorCodes returns the internal error codes of an API error returned by
+// any of the methods, or nil if err doesn't carry any.
+func ErrorCodes(err error) []int {
+	var apiErr interface{ ErrorCodes() []int }
+	if errors.As(err, &apiErr) {
+		return apiErr.ErrorCodes()
+	}
+
+	return nil
+}
+
+// ErrorCodeIs returns a boolean whether or not err is an API error carrying
+// the desired internal error code.
+func ErrorCodeIs(err error, code int) bool {
+	for _, errCode := range ErrorCodes(err) {
+		if errCode == code {
+			return true
+		}
+	}
+
+	return false
+}
+
+// BatchError holds the errors of a method applying a change to several
+// objects, which carries on past a failed object. It has one error for each
+// object that failed.
+type BatchError struct {
+	Errors []error
+}
+
+func (e *BatchError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -1,6 +1,8 @@
 package cloudflare
 
 import (
+	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +52,15 @@ func TestError_Error(t *testing.T) {
 		})
 	}
 }
+
+func TestErrorCodeIs(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &RequestError{cloudflareError: &Error{
+		StatusCode: 400,
+		ErrorCodes: []int{2001},
+	}})
+
+	assert.Equal(t, []int{2001}, ErrorCodes(err))
+	assert.True(t, ErrorCodeIs(err, 2001))
+	assert.False(t, ErrorCodeIs(err, 10000))
+	assert.False(t, ErrorCodeIs(errors.New("not an API error"), 2001))
+}
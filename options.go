@@ -57,11 +57,38 @@ func UsingRateLimit(rps float64) Option {
 func UsingRetryPolicy(maxRetries int, minRetryDelaySecs int, maxRetryDelaySecs int) Option {
 	// seconds is very granular for a minimum delay - but this is only in case of failure
 	return func(api *API) error {
-		api.retryPolicy = RetryPolicy{
-			MaxRetries:    maxRetries,
-			MinRetryDelay: time.Duration(minRetryDelaySecs) * time.Second,
-			MaxRetryDelay: time.Duration(maxRetryDelaySecs) * time.Second,
-		}
+		api.retryPolicy.MaxRetries = maxRetries
+		api.retryPolicy.MinRetryDelay = time.Duration(minRetryDelaySecs) * time.Second
+		api.retryPolicy.MaxRetryDelay = time.Duration(maxRetryDelaySecs) * time.Second
+		return nil
+	}
+}
+
+// UsingRetryJitter adds a random delay of up to jitter to each retry backoff
+// so that concurrent clients don't retry in lockstep.
+func UsingRetryJitter(jitter time.Duration) Option {
+	return func(api *API) error {
+		api.retryPolicy.Jitter = jitter
+		return nil
+	}
+}
+
+// UsingIdempotentRetries only retries requests with idempotent methods and
+// those marked safe to retry with WithRetryableRequest.
+func UsingIdempotentRetries() Option {
+	return func(api *API) error {
+		api.retryPolicy.IdempotentOnly = true
+		return nil
+	}
+}
+
+// UsingRequestTimeout sets a timeout for each API call, including its
+// retries, made with a context that has no deadline. A deadline already set
+// on the context is never shortened. The timeout applies per call, not to
+// the lifetime of the client.
+func UsingRequestTimeout(timeout time.Duration) Option {
+	return func(api *API) error {
+		api.requestTimeout = timeout
 		return nil
 	}
 }
@@ -75,6 +102,38 @@ func UsingLogger(logger Logger) Option {
 	}
 }
 
+// UsingRequestLogger installs a logger called with every request and its
+// response, for capturing exactly what is sent and received when debugging.
+// Credentials and known secret fields are redacted before the logger sees
+// them.
+func UsingRequestLogger(logger RequestLogger) Option {
+	return func(api *API) error {
+		api.requestLogger = logger
+		return nil
+	}
+}
+
+// UsingTeamsCatalogCache caches the Gateway application and category
+// catalogs per account for ttl, DefaultTeamsCatalogCacheTTL being a suitable
+// value. Rule helpers resolving names to IDs then only fetch the catalogs
+// once per ttl. Use InvalidateTeamsCatalogCache to drop cached catalogs.
+func UsingTeamsCatalogCache(ttl time.Duration) Option {
+	return func(api *API) error {
+		api.teamsCatalogCache = newTeamsCatalogCache(ttl)
+		return nil
+	}
+}
+
+// UsingASNPrefixResolver sets the source of the prefixes announced by an
+// ASN used by UpdateDeviceSplitTunnelExcludesFromASN, in place of
+// IntelligenceASNSubnets.
+func UsingASNPrefixResolver(resolver ASNPrefixResolver) Option {
+	return func(api *API) error {
+		api.asnPrefixResolver = resolver
+		return nil
+	}
+}
+
 // UserAgent can be set if you want to send a software name and version for HTTP access logs.
 // It is recommended to set it in order to help future Customer Support diagnostics
 // and prevent collateral damage by sharing generic User-Agent string with abusive users.
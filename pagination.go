@@ -1,5 +1,11 @@
 package cloudflare
 
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
 // Done returns true for the last page and false otherwise.
 func (p ResultInfo) Done() bool {
 	return p.Page > 1 && p.Page > p.TotalPages
@@ -17,3 +23,62 @@ func (p ResultInfo) Next() ResultInfo {
 func (p ResultInfo) HasMorePages() bool {
 	return p.Page > 1 && p.Page < p.TotalPages
 }
+
+// PaginationError is returned by the methods fetching every page of a
+// paginated endpoint, such as TeamsRulesAll or TeamsListsAll, when a page
+// after the first one fails. The results of the earlier pages are returned
+// along with it; a failure of the first page is returned as is with no
+// results.
+type PaginationError struct {
+	Page int
+	Err  error
+}
+
+func (e *PaginationError) Error() string {
+	return fmt.Sprintf("failed to fetch page %d: %s", e.Page, e.Err)
+}
+
+func (e *PaginationError) Unwrap() error {
+	return e.Err
+}
+
+// fetchAllPages calls fetchPage for every page of a paginated endpoint,
+// from the first page on with perPage results per page. fetchPage keeps the
+// results of the page and returns their count along with the result info of
+// the response. Fetching stops at the last page, at an empty page or at a
+// response without pagination details, which is taken to hold every result.
+// The context is checked before each page. An error of the first page is
+// returned as is and the error of a later page as a *PaginationError,
+// letting callers return the results fetched so far.
+func fetchAllPages(ctx context.Context, perPage int, fetchPage func(params ResultInfo) (int, ResultInfo, error)) error {
+	params := ResultInfo{Page: 1, PerPage: perPage}
+	for {
+		if err := ctx.Err(); err != nil {
+			return pageError(params.Page, err)
+		}
+
+		count, resultInfo, err := fetchPage(params)
+		if err != nil {
+			return pageError(params.Page, err)
+		}
+
+		if count == 0 || resultInfo.Page == 0 || resultInfo.Page >= resultInfo.TotalPages {
+			return nil
+		}
+		params = resultInfo.Next()
+	}
+}
+
+// isPaginationError reports whether err is a *PaginationError, after which
+// the results fetched so far are returned.
+func isPaginationError(err error) bool {
+	var pageErr *PaginationError
+	return errors.As(err, &pageErr)
+}
+
+func pageError(page int, err error) error {
+	if page == 1 {
+		return err
+	}
+	return &PaginationError{Page: page, Err: err}
+}
@@ -0,0 +1,76 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFetchAllPages(t *testing.T) {
+	pages := [][]int{{1, 2}, {3, 4}, {5}}
+
+	var results []int
+	err := fetchAllPages(context.Background(), 2, func(params ResultInfo) (int, ResultInfo, error) {
+		assert.Equal(t, 2, params.PerPage)
+		page := pages[params.Page-1]
+		results = append(results, page...)
+		return len(page), ResultInfo{Page: params.Page, PerPage: 2, TotalPages: len(pages)}, nil
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, results)
+	}
+}
+
+func TestFetchAllPagesWithoutResultInfo(t *testing.T) {
+	calls := 0
+	err := fetchAllPages(context.Background(), 50, func(params ResultInfo) (int, ResultInfo, error) {
+		calls++
+		return 3, ResultInfo{}, nil
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestFetchAllPagesError(t *testing.T) {
+	failure := errors.New("internal server error")
+
+	var results []int
+	err := fetchAllPages(context.Background(), 1, func(params ResultInfo) (int, ResultInfo, error) {
+		if params.Page == 3 {
+			return 0, ResultInfo{}, failure
+		}
+		results = append(results, params.Page)
+		return 1, ResultInfo{Page: params.Page, PerPage: 1, TotalPages: 5}, nil
+	})
+
+	assert.Equal(t, []int{1, 2}, results)
+	var pageErr *PaginationError
+	if assert.ErrorAs(t, err, &pageErr) {
+		assert.Equal(t, 3, pageErr.Page)
+		assert.ErrorIs(t, err, failure)
+	}
+
+	err = fetchAllPages(context.Background(), 1, func(params ResultInfo) (int, ResultInfo, error) {
+		return 0, ResultInfo{}, failure
+	})
+	assert.Equal(t, failure, err)
+}
+
+func TestFetchAllPagesContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	calls := 0
+	err := fetchAllPages(ctx, 1, func(params ResultInfo) (int, ResultInfo, error) {
+		calls++
+		cancel()
+		return 1, ResultInfo{Page: params.Page, PerPage: 1, TotalPages: 5}, nil
+	})
+
+	assert.Equal(t, 1, calls)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.EqualError(t, err, "failed to fetch page 2: context canceled")
+}
@@ -0,0 +1,108 @@
+package cloudflare
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+)
+
+const redactedValue = "REDACTED"
+
+// RequestLogger receives every request made by the client together with its
+// response and response body. Credentials and known secret fields are
+// redacted from both bodies and the request headers before it's called.
+// The request body can be read from req.Body.
+type RequestLogger func(req *http.Request, resp *http.Response, body []byte)
+
+// redactedHeaders are the request headers carrying credentials.
+var redactedHeaders = []string{
+	"Authorization",
+	"X-Auth-Key",
+	"X-Auth-User-Service-Key",
+}
+
+// redactedFields are the JSON keys whose string values are secrets, such as
+// DLP dataset secrets, tunnel secrets, private keys and SSH seeds.
+var redactedFields = map[string]bool{
+	"secret":          true,
+	"client_secret":   true,
+	"tunnel_secret":   true,
+	"private_key":     true,
+	"api_key":         true,
+	"api_token":       true,
+	"token":           true,
+	"password":        true,
+	"activation_key":  true,
+	"seed":            true,
+	"ssh_seed":        true,
+	"ssh_private_key": true,
+}
+
+// logRequest passes a redacted copy of the request and response to the
+// request logger.
+func (api *API) logRequest(resp *http.Response, reqBody, respBody []byte) {
+	var req *http.Request
+	if resp.Request != nil {
+		req = resp.Request.Clone(resp.Request.Context())
+		for _, name := range redactedHeaders {
+			if req.Header.Get(name) != "" {
+				req.Header.Set(name, redactedValue)
+			}
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(redactJSON(reqBody)))
+	}
+
+	body := redactJSON(respBody)
+	logged := *resp
+	logged.Request = req
+	logged.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	api.requestLogger(req, &logged, body)
+}
+
+// redactJSON replaces the string values of secret fields in a JSON body.
+// Bodies that aren't JSON are returned unchanged.
+func redactJSON(body []byte) []byte {
+	var v interface{}
+	if len(body) == 0 || json.Unmarshal(body, &v) != nil {
+		return body
+	}
+
+	if !redactJSONValue(v) {
+		return body
+	}
+
+	redacted, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+
+	return redacted
+}
+
+// redactJSONValue redacts v in place and reports whether anything changed.
+func redactJSONValue(v interface{}) bool {
+	changed := false
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if _, ok := value.(string); ok && redactedFields[key] {
+				v[key] = redactedValue
+				changed = true
+				continue
+			}
+			if redactJSONValue(value) {
+				changed = true
+			}
+		}
+	case []interface{}:
+		for _, value := range v {
+			if redactJSONValue(value) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
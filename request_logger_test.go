@@ -0,0 +1,53 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var loggedReq *http.Request
+	var loggedResp *http.Response
+	var loggedBody []byte
+	setup(UsingRequestLogger(func(req *http.Request, resp *http.Response, body []byte) {
+		loggedReq, loggedResp, loggedBody = req, resp, body
+	}))
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/dlp/datasets", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "deadbeef", r.Header.Get("X-Auth-Key"))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success":true,"errors":[],"messages":[],"result":{"secret":"s3cr3t","name":"dataset","enabled":true}}`)
+	})
+
+	params := map[string]interface{}{
+		"name":  "dataset",
+		"rules": []interface{}{map[string]interface{}{"ssh_seed": "seed", "secret": true}},
+	}
+	_, err := client.makeRequestContext(context.Background(), http.MethodPost, "/accounts/"+testAccountID+"/dlp/datasets", params)
+	assert.NoError(t, err)
+
+	if assert.NotNil(t, loggedReq) {
+		assert.Equal(t, "REDACTED", loggedReq.Header.Get("X-Auth-Key"))
+		assert.Equal(t, "cloudflare@example.org", loggedReq.Header.Get("X-Auth-Email"))
+		reqBody, err := ioutil.ReadAll(loggedReq.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"name":"dataset","rules":[{"ssh_seed":"REDACTED","secret":true}]}`, string(reqBody))
+	}
+	if assert.NotNil(t, loggedResp) {
+		assert.Equal(t, http.StatusOK, loggedResp.StatusCode)
+		assert.JSONEq(t, `{"success":true,"errors":[],"messages":[],"result":{"secret":"REDACTED","name":"dataset","enabled":true}}`, string(loggedBody))
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	assert.Equal(t, "not json", string(redactJSON([]byte("not json"))))
+	assert.Equal(t, `{"name":"a"}`, string(redactJSON([]byte(`{"name":"a"}`))))
+	assert.JSONEq(t, `{"tunnel_secret":"REDACTED","nested":{"client_secret":"REDACTED"}}`,
+		string(redactJSON([]byte(`{"tunnel_secret":"abc","nested":{"client_secret":"def"}}`))))
+}
@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
 )
 
 // SplitTunnelResponse represents the response from the get split
@@ -26,7 +29,27 @@ type SplitTunnel struct {
 // API reference for include: https://api.cloudflare.com/#device-policy-get-split-tunnel-include-list
 // API reference for exclude: https://api.cloudflare.com/#device-policy-get-split-tunnel-exclude-list
 func (api *API) ListSplitTunnels(ctx context.Context, accountID string, mode string) ([]SplitTunnel, error) {
-	uri := fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, mode)
+	return api.listSplitTunnels(ctx, accountID, "", mode)
+}
+
+// ListDeviceSplitTunnelExcludes returns the split tunnel exclude list of a
+// device settings policy. An empty policyID targets the default policy.
+//
+// API reference: https://api.cloudflare.com/#device-policy-get-split-tunnel-exclude-list
+func (api *API) ListDeviceSplitTunnelExcludes(ctx context.Context, accountID, policyID string) ([]SplitTunnel, error) {
+	return api.listSplitTunnels(ctx, accountID, policyID, "exclude")
+}
+
+// ListDeviceSplitTunnelIncludes returns the split tunnel include list of a
+// device settings policy. An empty policyID targets the default policy.
+//
+// API reference: https://api.cloudflare.com/#device-policy-get-split-tunnel-include-list
+func (api *API) ListDeviceSplitTunnelIncludes(ctx context.Context, accountID, policyID string) ([]SplitTunnel, error) {
+	return api.listSplitTunnels(ctx, accountID, policyID, "include")
+}
+
+func (api *API) listSplitTunnels(ctx context.Context, accountID, policyID, mode string) ([]SplitTunnel, error) {
+	uri := splitTunnelURI(accountID, policyID, mode)
 
 	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
@@ -47,7 +70,190 @@ func (api *API) ListSplitTunnels(ctx context.Context, accountID string, mode str
 // API reference for include: https://api.cloudflare.com/#device-policy-set-split-tunnel-include-list
 // API reference for exclude: https://api.cloudflare.com/#device-policy-set-split-tunnel-exclude-list
 func (api *API) UpdateSplitTunnel(ctx context.Context, accountID string, mode string, tunnels []SplitTunnel) ([]SplitTunnel, error) {
-	uri := fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, mode)
+	return api.updateSplitTunnels(ctx, accountID, "", mode, tunnels)
+}
+
+// UpdateDeviceSplitTunnelExcludes replaces the split tunnel exclude list of a
+// device settings policy. An empty policyID targets the default policy and an
+// empty list clears the existing routes.
+//
+// API reference: https://api.cloudflare.com/#device-policy-set-split-tunnel-exclude-list
+func (api *API) UpdateDeviceSplitTunnelExcludes(ctx context.Context, accountID, policyID string, tunnels []SplitTunnel) ([]SplitTunnel, error) {
+	return api.updateSplitTunnels(ctx, accountID, policyID, "exclude", tunnels)
+}
+
+// UpdateDeviceSplitTunnelIncludes replaces the split tunnel include list of a
+// device settings policy. An empty policyID targets the default policy and an
+// empty list clears the existing routes.
+//
+// API reference: https://api.cloudflare.com/#device-policy-set-split-tunnel-include-list
+func (api *API) UpdateDeviceSplitTunnelIncludes(ctx context.Context, accountID, policyID string, tunnels []SplitTunnel) ([]SplitTunnel, error) {
+	return api.updateSplitTunnels(ctx, accountID, policyID, "include", tunnels)
+}
+
+// splitTunnelHostDescriptionPrefix tags the split tunnel entries managed by
+// UpdateDeviceSplitTunnelExcludesFromHosts, followed by the host name.
+const splitTunnelHostDescriptionPrefix = "resolved from host: "
+
+// splitTunnelLookupIP resolves host names for
+// UpdateDeviceSplitTunnelExcludesFromHosts.
+var splitTunnelLookupIP = net.DefaultResolver.LookupIPAddr
+
+// UpdateDeviceSplitTunnelExcludesFromHosts excludes the addresses that hosts
+// currently resolve to from the tunnel of a device settings policy. The
+// entries it adds are tagged in their description and replaced on every
+// call, so addresses a host no longer resolves to are removed. All other
+// entries, including Host based ones, are kept and addresses they already
+// cover aren't added again. An empty policyID targets the default policy.
+func (api *API) UpdateDeviceSplitTunnelExcludesFromHosts(ctx context.Context, accountID, policyID string, hosts []string) ([]SplitTunnel, error) {
+	existing, err := api.ListDeviceSplitTunnelExcludes(ctx, accountID, policyID)
+	if err != nil {
+		return []SplitTunnel{}, err
+	}
+
+	tunnels := []SplitTunnel{}
+	var covered []*net.IPNet
+	for _, tunnel := range existing {
+		if strings.HasPrefix(tunnel.Description, splitTunnelHostDescriptionPrefix) {
+			continue
+		}
+		tunnels = append(tunnels, tunnel)
+		if _, network, err := net.ParseCIDR(tunnel.Address); err == nil {
+			covered = append(covered, network)
+		} else if ip := net.ParseIP(tunnel.Address); ip != nil {
+			covered = append(covered, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+		}
+	}
+
+	isCovered := func(ip net.IP) bool {
+		for _, network := range covered {
+			if network.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, host := range hosts {
+		addrs, err := splitTunnelLookupIP(ctx, host)
+		if err != nil {
+			return []SplitTunnel{}, fmt.Errorf("error resolving split tunnel host %q: %w", host, err)
+		}
+
+		var resolved []SplitTunnel
+		for _, addr := range addrs {
+			ip := addr.IP
+			bits := 128
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 32
+			}
+			if isCovered(ip) {
+				continue
+			}
+			covered = append(covered, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			resolved = append(resolved, SplitTunnel{
+				Address:     fmt.Sprintf("%s/%d", ip, bits),
+				Description: splitTunnelHostDescriptionPrefix + host,
+			})
+		}
+
+		sort.Slice(resolved, func(i, j int) bool { return resolved[i].Address < resolved[j].Address })
+		tunnels = append(tunnels, resolved...)
+	}
+
+	return api.UpdateDeviceSplitTunnelExcludes(ctx, accountID, policyID, tunnels)
+}
+
+// ASNPrefixResolver returns the IP prefixes, in CIDR notation, announced by
+// an autonomous system. It is used by
+// UpdateDeviceSplitTunnelExcludesFromASN and can be replaced with
+// UsingASNPrefixResolver to use another source of routing data.
+type ASNPrefixResolver interface {
+	ASNPrefixes(ctx context.Context, asn int) ([]string, error)
+}
+
+// ASNPrefixResolverFunc adapts a function to an ASNPrefixResolver.
+type ASNPrefixResolverFunc func(ctx context.Context, asn int) ([]string, error)
+
+// ASNPrefixes calls f.
+func (f ASNPrefixResolverFunc) ASNPrefixes(ctx context.Context, asn int) ([]string, error) {
+	return f(ctx, asn)
+}
+
+// splitTunnelASNDescriptionPrefix tags the split tunnel entries managed by
+// UpdateDeviceSplitTunnelExcludesFromASN, followed by the ASN.
+const splitTunnelASNDescriptionPrefix = "announced by AS"
+
+// UpdateDeviceSplitTunnelExcludesFromASN excludes the prefixes announced by
+// asn from the tunnel of a device settings policy. Prefixes are resolved with
+// the resolver set with UsingASNPrefixResolver or, by default, with
+// IntelligenceASNSubnets. Like UpdateDeviceSplitTunnelExcludesFromHosts the
+// entries it adds are tagged in their description and replaced on every
+// call, so prefixes no longer announced are removed while other entries are
+// kept. An empty policyID targets the default policy.
+func (api *API) UpdateDeviceSplitTunnelExcludesFromASN(ctx context.Context, accountID, policyID string, asn int) ([]SplitTunnel, error) {
+	if asn == 0 {
+		return []SplitTunnel{}, ErrMissingASN
+	}
+
+	resolver := api.asnPrefixResolver
+	if resolver == nil {
+		resolver = ASNPrefixResolverFunc(func(ctx context.Context, asn int) ([]string, error) {
+			info, err := api.IntelligenceASNSubnets(ctx, IntelligenceASNSubnetsParameters{AccountID: accountID, ASN: asn})
+			return info.Subnets, err
+		})
+	}
+
+	prefixes, err := resolver.ASNPrefixes(ctx, asn)
+	if err != nil {
+		return []SplitTunnel{}, fmt.Errorf("error resolving prefixes of AS%d: %w", asn, err)
+	}
+
+	existing, err := api.ListDeviceSplitTunnelExcludes(ctx, accountID, policyID)
+	if err != nil {
+		return []SplitTunnel{}, err
+	}
+
+	tag := fmt.Sprintf("%s%d", splitTunnelASNDescriptionPrefix, asn)
+
+	tunnels := []SplitTunnel{}
+	addresses := make(map[string]bool)
+	for _, tunnel := range existing {
+		if tunnel.Description == tag {
+			continue
+		}
+		tunnels = append(tunnels, tunnel)
+		addresses[tunnel.Address] = true
+	}
+
+	var announced []SplitTunnel
+	for _, prefix := range prefixes {
+		_, network, err := net.ParseCIDR(prefix)
+		if err != nil {
+			return []SplitTunnel{}, fmt.Errorf("invalid prefix %q announced by AS%d: %w", prefix, asn, err)
+		}
+		address := network.String()
+		if addresses[address] {
+			continue
+		}
+		addresses[address] = true
+		announced = append(announced, SplitTunnel{Address: address, Description: tag})
+	}
+
+	sort.Slice(announced, func(i, j int) bool { return announced[i].Address < announced[j].Address })
+	tunnels = append(tunnels, announced...)
+
+	return api.UpdateDeviceSplitTunnelExcludes(ctx, accountID, policyID, tunnels)
+}
+
+func (api *API) updateSplitTunnels(ctx context.Context, accountID, policyID, mode string, tunnels []SplitTunnel) ([]SplitTunnel, error) {
+	uri := splitTunnelURI(accountID, policyID, mode)
+
+	// A nil slice would be sent as null, send an empty list to clear it.
+	if tunnels == nil {
+		tunnels = []SplitTunnel{}
+	}
 
 	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, tunnels)
 	if err != nil {
@@ -62,3 +268,13 @@ func (api *API) UpdateSplitTunnel(ctx context.Context, accountID string, mode st
 
 	return splitTunnelResponse.Result, nil
 }
+
+// splitTunnelURI returns the include or exclude endpoint of a device settings
+// policy, falling back to the default policy when policyID is empty.
+func splitTunnelURI(accountID, policyID, mode string) string {
+	if policyID == "" {
+		return fmt.Sprintf("/%s/%s/devices/policy/%s", AccountRouteRoot, accountID, mode)
+	}
+
+	return fmt.Sprintf("/%s/%s/devices/policy/%s/%s", AccountRouteRoot, accountID, policyID, mode)
+}
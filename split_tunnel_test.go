@@ -2,7 +2,10 @@ package cloudflare
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
+	"net"
 	"net/http"
 	"testing"
 
@@ -260,3 +263,204 @@ func TestUpdateSplitTunnelExclude(t *testing.T) {
 		assert.Equal(t, tunnels, actual)
 	}
 }
+
+func TestListDeviceSplitTunnelExcludesWithPolicy(t *testing.T) {
+	setup()
+	defer teardown()
+
+	policyID := "a842fa8a-a583-482e-9cd9-eb43362949fd"
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"address": "192.0.2.0/24", "description": "TEST-NET-1"}]
+		}`)
+	}
+
+	want := []SplitTunnel{{
+		Address:     "192.0.2.0/24",
+		Description: "TEST-NET-1",
+	}}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+policyID+"/exclude", handler)
+
+	actual, err := client.ListDeviceSplitTunnelExcludes(context.Background(), testAccountID, policyID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestUpdateDeviceSplitTunnelIncludesClear(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `[]`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": []
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/include", handler)
+
+	actual, err := client.UpdateDeviceSplitTunnelIncludes(context.Background(), testAccountID, "", nil)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []SplitTunnel{}, actual)
+	}
+}
+
+func TestUpdateDeviceSplitTunnelExcludesFromHosts(t *testing.T) {
+	setup()
+	defer teardown()
+
+	lookupIP := splitTunnelLookupIP
+	defer func() { splitTunnelLookupIP = lookupIP }()
+	splitTunnelLookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		switch host {
+		case "office.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.20")}, {IP: net.ParseIP("198.51.100.7")}, {IP: net.ParseIP("2001:db8::7")}}, nil
+		case "vpn.example.com":
+			return []net.IPAddr{{IP: net.ParseIP("203.0.113.20")}, {IP: net.ParseIP("10.1.2.3")}}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"address": "10.0.0.0/8", "description": "private"},
+					{"host": "*.example.net", "description": "partner"},
+					{"address": "192.0.2.1/32", "description": "resolved from host: old.example.com"}
+				]
+			}`)
+			return
+		}
+
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			var tunnels []SplitTunnel
+			assert.NoError(t, json.Unmarshal(body, &tunnels))
+			assert.Equal(t, []SplitTunnel{
+				{Address: "10.0.0.0/8", Description: "private"},
+				{Host: "*.example.net", Description: "partner"},
+				{Address: "198.51.100.7/32", Description: "resolved from host: office.example.com"},
+				{Address: "2001:db8::7/128", Description: "resolved from host: office.example.com"},
+				{Address: "203.0.113.20/32", Description: "resolved from host: office.example.com"},
+			}, tunnels)
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/exclude", handler)
+
+	actual, err := client.UpdateDeviceSplitTunnelExcludesFromHosts(context.Background(), testAccountID, "", []string{"office.example.com", "vpn.example.com"})
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 5)
+	}
+
+	_, err = client.UpdateDeviceSplitTunnelExcludesFromHosts(context.Background(), testAccountID, "", []string{"missing.example.com"})
+	assert.EqualError(t, err, `error resolving split tunnel host "missing.example.com": no such host`)
+}
+
+func TestUpdateDeviceSplitTunnelExcludesFromASN(t *testing.T) {
+	setup(UsingASNPrefixResolver(ASNPrefixResolverFunc(func(ctx context.Context, asn int) ([]string, error) {
+		assert.Equal(t, 64500, asn)
+		return []string{"203.0.113.0/24", "198.51.100.0/24", "10.0.0.0/8", "2001:db8::/32"}, nil
+	})))
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"address": "10.0.0.0/8", "description": "private"},
+					{"address": "192.0.2.0/24", "description": "announced by AS64500"},
+					{"address": "192.0.2.128/25", "description": "announced by AS64501"}
+				]
+			}`)
+			return
+		}
+
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			var tunnels []SplitTunnel
+			assert.NoError(t, json.Unmarshal(body, &tunnels))
+			assert.Equal(t, []SplitTunnel{
+				{Address: "10.0.0.0/8", Description: "private"},
+				{Address: "192.0.2.128/25", Description: "announced by AS64501"},
+				{Address: "198.51.100.0/24", Description: "announced by AS64500"},
+				{Address: "2001:db8::/32", Description: "announced by AS64500"},
+				{Address: "203.0.113.0/24", Description: "announced by AS64500"},
+			}, tunnels)
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/"+testDeviceSettingsPolicyID+"/exclude", handler)
+
+	actual, err := client.UpdateDeviceSplitTunnelExcludesFromASN(context.Background(), testAccountID, testDeviceSettingsPolicyID, 64500)
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 5)
+	}
+
+	_, err = client.UpdateDeviceSplitTunnelExcludesFromASN(context.Background(), testAccountID, testDeviceSettingsPolicyID, 0)
+	assert.Equal(t, ErrMissingASN, err)
+}
+
+func TestUpdateDeviceSplitTunnelExcludesFromASNIntelligence(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/intel/asn/64500/subnets", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"asn": 64500, "subnets": ["203.0.113.0/24"], "count": 1}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policy/exclude", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+			return
+		}
+
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `[{"address": "203.0.113.0/24", "description": "announced by AS64500"}]`, string(body))
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+	})
+
+	_, err := client.UpdateDeviceSplitTunnelExcludesFromASN(context.Background(), testAccountID, "", 64500)
+	assert.NoError(t, err)
+}
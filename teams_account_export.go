@@ -0,0 +1,284 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"time"
+)
+
+// TeamsAccountExport is a snapshot of the Gateway state of an account. It is
+// produced by ExportTeamsAccount and can be serialised to JSON and restored on
+// another account with ImportTeamsAccount.
+type TeamsAccountExport struct {
+	Configuration TeamsConfiguration `json:"configuration"`
+	Rules         []TeamsRule        `json:"rules"`
+	Lists         []TeamsList        `json:"lists"`
+	Locations     []TeamsLocation    `json:"locations"`
+}
+
+// ImportOptions controls how ImportTeamsAccount treats objects that already
+// exist on the target account. Lists, locations and rules are matched by name.
+type ImportOptions struct {
+	// Overwrite replaces existing objects with the exported ones. When false
+	// existing objects are left untouched and only missing ones are created.
+	Overwrite bool
+
+	// SkipConfiguration leaves the account configuration untouched.
+	SkipConfiguration bool
+}
+
+// ExportTeamsAccount returns the configuration, rules, lists (including their
+// items) and locations of an account.
+func (api *API) ExportTeamsAccount(ctx context.Context, accountID string) (TeamsAccountExport, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsAccountExport{}, err
+	}
+
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return TeamsAccountExport{}, err
+	}
+
+	lists, err := api.TeamsListsAll(ctx, accountID)
+	if err != nil {
+		return TeamsAccountExport{}, err
+	}
+
+	for i := range lists {
+		items, err := api.teamsListAllItems(ctx, accountID, lists[i].ID)
+		if err != nil {
+			return TeamsAccountExport{}, err
+		}
+		lists[i].Items = items
+	}
+
+	locations, err := api.TeamsLocationsAll(ctx, accountID)
+	if err != nil {
+		return TeamsAccountExport{}, err
+	}
+
+	return TeamsAccountExport{
+		Configuration: config,
+		Rules:         rules,
+		Lists:         lists,
+		Locations:     locations,
+	}, nil
+}
+
+// ImportTeamsAccount restores an export onto an account. Lists and locations
+// are imported first so the list and location IDs referenced by the rule
+// expressions can be rewritten to the IDs on the target account. Location
+// networks are imported without their IDs, while the subdomains, IPs and
+// policy IDs of locations are assigned by the target account and are not
+// imported. Like CopyTeamsConfiguration, the configuration is applied
+// without its timestamps and custom certificate, which belongs to the
+// exported account.
+func (api *API) ImportTeamsAccount(ctx context.Context, accountID string, export TeamsAccountExport, opts ImportOptions) error {
+	ids := make(map[string]string)
+
+	if err := api.importTeamsLists(ctx, accountID, export.Lists, opts, ids); err != nil {
+		return err
+	}
+
+	if err := api.importTeamsLocations(ctx, accountID, export.Locations, opts, ids); err != nil {
+		return err
+	}
+
+	if !opts.SkipConfiguration {
+		config := export.Configuration
+		config.CreatedAt, config.UpdatedAt = time.Time{}, time.Time{}
+		config.Settings.CustomCertificate = nil
+
+		if _, err := api.TeamsAccountUpdateConfiguration(ctx, accountID, config); err != nil {
+			return fmt.Errorf("error importing configuration: %w", err)
+		}
+	}
+
+	return api.importTeamsRules(ctx, accountID, export.Rules, opts, ids)
+}
+
+// importTeamsLists creates the exported lists, recording the ID of each list
+// on the target account in ids.
+func (api *API) importTeamsLists(ctx context.Context, accountID string, lists []TeamsList, opts ImportOptions, ids map[string]string) error {
+	for _, list := range lists {
+		oldID := list.ID
+		list.ID, list.Count, list.CreatedAt, list.UpdatedAt = "", 0, nil, nil
+
+		var imported TeamsList
+		var err error
+		if opts.Overwrite {
+			imported, _, err = api.TeamsUpsertListByName(ctx, accountID, list)
+		} else {
+			var found bool
+			imported, found, err = api.teamsListByName(ctx, accountID, list.Name)
+			if err == nil && !found {
+				imported, err = api.CreateTeamsList(ctx, accountID, list)
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("error importing list %q: %w", list.Name, err)
+		}
+
+		ids[oldID] = imported.ID
+	}
+
+	return nil
+}
+
+// importTeamsLocations creates the exported locations, recording the ID of
+// each location on the target account in ids.
+func (api *API) importTeamsLocations(ctx context.Context, accountID string, locations []TeamsLocation, opts ImportOptions, ids map[string]string) error {
+	existing, err := api.TeamsLocationsAll(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]TeamsLocation, len(existing))
+	for _, location := range existing {
+		byName[location.Name] = location
+	}
+
+	for _, location := range locations {
+		oldID := location.ID
+		location.ID, location.CreatedAt, location.UpdatedAt = "", nil, nil
+		location.PolicyIDs = nil
+		networks := make([]TeamsLocationNetwork, 0, len(location.Networks))
+		for _, network := range location.Networks {
+			networks = append(networks, TeamsLocationNetwork{Network: network.Network})
+		}
+		location.Networks = networks
+		location.Ip, location.Subdomain, location.IPv4Destination = "", "", ""
+
+		imported, found := byName[location.Name]
+		switch {
+		case !found:
+			imported, err = api.CreateTeamsLocation(ctx, accountID, location)
+		case opts.Overwrite:
+			location.ID = imported.ID
+			imported, err = api.UpdateTeamsLocation(ctx, accountID, location)
+		}
+		if err != nil {
+			return fmt.Errorf("error importing location %q: %w", location.Name, err)
+		}
+
+		ids[oldID] = imported.ID
+	}
+
+	return nil
+}
+
+// importTeamsRules creates the exported rules after replacing the list and
+// location IDs in their expressions with the ones in ids.
+func (api *API) importTeamsRules(ctx context.Context, accountID string, rules []TeamsRule, opts ImportOptions, ids map[string]string) error {
+	existing, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	byName := make(map[string]TeamsRule, len(existing))
+	for _, rule := range existing {
+		byName[rule.Name] = rule
+	}
+
+	for _, rule := range rules {
+		rule = stripTeamsRuleServerFields(rule)
+		rule.Traffic = remapTeamsIDs(rule.Traffic, ids)
+		rule.Identity = remapTeamsIDs(rule.Identity, ids)
+		rule.DevicePosture = remapTeamsIDs(rule.DevicePosture, ids)
+
+		current, found := byName[rule.Name]
+		switch {
+		case !found:
+			_, err = api.TeamsCreateRule(ctx, accountID, rule)
+		case opts.Overwrite:
+			_, err = api.TeamsUpdateRule(ctx, accountID, current.ID, rule)
+		}
+		if err != nil {
+			return fmt.Errorf("error importing rule %q: %w", rule.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// stripTeamsRuleServerFields clears the fields of rule assigned by the API so
+// that it can be created on an account.
+func stripTeamsRuleServerFields(rule TeamsRule) TeamsRule {
+	rule.ID, rule.Version = "", 0
+	rule.CreatedAt, rule.UpdatedAt, rule.DeletedAt = nil, nil, nil
+	return rule
+}
+
+// ParseTeamsRulesExport reads rules exported from the dashboard and returns
+// them ready to be created with TeamsCreateRule, without their IDs, versions
+// and timestamps. The export may hold a single rule, an array of rules or an
+// API response with the rules as its result. List and location IDs in the
+// rule expressions are kept as is, so rules exported from another account
+// may reference lists that don't exist on the target account.
+func ParseTeamsRulesExport(r io.Reader) ([]TeamsRule, error) {
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("error reading rules export: %w", err)
+	}
+
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return nil, errors.New("rules export is empty")
+	}
+
+	var rules []TeamsRule
+	switch data[0] {
+	case '[':
+		err = json.Unmarshal(data, &rules)
+	case '{':
+		var envelope struct {
+			Result json.RawMessage `json:"result"`
+		}
+		if err = json.Unmarshal(data, &envelope); err != nil {
+			break
+		}
+		if len(envelope.Result) > 0 {
+			return ParseTeamsRulesExport(bytes.NewReader(envelope.Result))
+		}
+
+		var rule TeamsRule
+		err = json.Unmarshal(data, &rule)
+		rules = []TeamsRule{rule}
+	default:
+		return nil, errors.New("rules export must be a JSON rule or array of rules")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error parsing rules export: %w", err)
+	}
+
+	for i := range rules {
+		rules[i] = stripTeamsRuleServerFields(rules[i])
+	}
+
+	return rules, nil
+}
+
+// teamsIDTokenRegexp matches the tokens of a rule expression that may be a
+// list or location ID, such as the ID following the $ of a list reference
+// or a quoted location ID.
+var teamsIDTokenRegexp = regexp.MustCompile(`[\w-]+`)
+
+// remapTeamsIDs replaces every token of expression that is an ID with an
+// entry in ids. Only whole tokens are replaced, in a single pass, so an ID
+// that is a substring of another one or that is also a replacement isn't
+// rewritten twice.
+func remapTeamsIDs(expression string, ids map[string]string) string {
+	return teamsIDTokenRegexp.ReplaceAllStringFunc(expression, func(token string) string {
+		if newID, ok := ids[token]; ok && newID != "" {
+			return newID
+		}
+		return token
+	})
+}
@@ -0,0 +1,334 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportTeamsAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"activity_log": {"enabled": true}}}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "rule-1", "name": "block list", "action": "block", "traffic": "any(dns.domains[*] in $11111111-1111-1111-1111-111111111111)"}]
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "11111111-1111-1111-1111-111111111111", "name": "blocked", "type": "DOMAIN", "count": 1}]
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/11111111-1111-1111-1111-111111111111/items", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"value": "example.com"}]
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/locations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "location-1", "name": "office"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "location-3", "name": "warehouse"}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	})
+
+	actual, err := client.ExportTeamsAccount(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.True(t, actual.Configuration.Settings.ActivityLog.Enabled)
+		assert.Equal(t, []TeamsRule{{
+			ID:      "rule-1",
+			Name:    "block list",
+			Action:  Block,
+			Traffic: "any(dns.domains[*] in $11111111-1111-1111-1111-111111111111)",
+		}}, actual.Rules)
+		assert.Equal(t, []TeamsList{{
+			ID:    "11111111-1111-1111-1111-111111111111",
+			Name:  "blocked",
+			Type:  "DOMAIN",
+			Count: 1,
+			Items: []TeamsListItem{{Value: "example.com"}},
+		}}, actual.Lists)
+		assert.Equal(t, []TeamsLocation{{ID: "location-1", Name: "office"}, {ID: "location-3", Name: "warehouse"}}, actual.Locations)
+	}
+}
+
+func TestImportTeamsAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	export := TeamsAccountExport{
+		Configuration: TeamsConfiguration{Settings: TeamsAccountSettings{
+			ActivityLog:       &TeamsActivityLog{Enabled: true},
+			CustomCertificate: &TeamsCustomCertificate{Enabled: true, ID: "d1b364c5-1311-466e-a194-f0e943e0799f"},
+		}},
+		Rules: []TeamsRule{
+			{ID: "rule-1", Name: "block list", Action: Block, Traffic: "any(dns.domains[*] in $11111111-1111-1111-1111-111111111111) and dns.location in {\"location-1\"}"},
+			{ID: "rule-2", Name: "existing", Action: Allow},
+		},
+		Lists: []TeamsList{{ID: "11111111-1111-1111-1111-111111111111", Name: "blocked", Type: "DOMAIN", Items: []TeamsListItem{{Value: "example.com"}}}},
+		Locations: []TeamsLocation{{
+			ID:        "location-1",
+			Name:      "office",
+			Subdomain: "abc",
+			Networks:  []TeamsLocationNetwork{{ID: "network-1", Network: "198.51.100.0/24"}},
+		}},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"name":"blocked","type":"DOMAIN","items":[{"value":"example.com"}]}`, string(body))
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "22222222-2222-2222-2222-222222222222", "name": "blocked", "type": "DOMAIN"}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/locations", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var location TeamsLocation
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&location)) {
+			assert.Equal(t, TeamsLocation{Name: "office", Networks: []TeamsLocationNetwork{{Network: "198.51.100.0/24"}}}, location)
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "location-2", "name": "office"}
+		}`)
+	})
+
+	configured := false
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings": {"activity_log": {"enabled": true}},
+				"created_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z"
+			}`, string(body))
+		}
+		configured = true
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"activity_log": {"enabled": true}}}
+		}`)
+	})
+
+	var created []TeamsRule
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [{"id": "rule-3", "name": "existing", "action": "block"}]}`)
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var rule TeamsRule
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&rule)) {
+			created = append(created, rule)
+		}
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "rule-4"}}`)
+	})
+
+	err := client.ImportTeamsAccount(context.Background(), testAccountID, export, ImportOptions{})
+
+	if assert.NoError(t, err) {
+		assert.True(t, configured)
+		if assert.Len(t, created, 1) {
+			assert.Equal(t, "", created[0].ID)
+			assert.Equal(t, "any(dns.domains[*] in $22222222-2222-2222-2222-222222222222) and dns.location in {\"location-2\"}", created[0].Traffic)
+		}
+	}
+}
+
+func TestRemapTeamsIDs(t *testing.T) {
+	ids := map[string]string{
+		"location-1":                           "location-2",
+		"location-2":                           "location-3",
+		"11111111-1111-1111-1111-111111111111": "22222222-2222-2222-2222-222222222222",
+	}
+
+	assert.Equal(t,
+		`any(dns.domains[*] in $22222222-2222-2222-2222-222222222222) and dns.location in {"location-2" "location-3" "location-10"}`,
+		remapTeamsIDs(`any(dns.domains[*] in $11111111-1111-1111-1111-111111111111) and dns.location in {"location-1" "location-2" "location-10"}`, ids),
+	)
+}
+
+func TestImportTeamsAccountOverwrite(t *testing.T) {
+	setup()
+	defer teardown()
+
+	export := TeamsAccountExport{
+		Rules:     []TeamsRule{{ID: "rule-1", Name: "existing", Action: Allow}},
+		Locations: []TeamsLocation{{ID: "location-1", Name: "office"}},
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/locations", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "location-3", "name": "warehouse"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "location-2", "name": "office"}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	})
+
+	locationUpdated := false
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/locations/location-2", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		locationUpdated = true
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "location-2", "name": "office"}}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [{"id": "rule-3", "name": "existing", "action": "block"}]}`)
+	})
+
+	updated := false
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/rule-3", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		updated = true
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "rule-3", "name": "existing", "action": "allow"}}`)
+	})
+
+	err := client.ImportTeamsAccount(context.Background(), testAccountID, export, ImportOptions{Overwrite: true, SkipConfiguration: true})
+
+	if assert.NoError(t, err) {
+		assert.True(t, locationUpdated)
+		assert.True(t, updated)
+	}
+}
+
+func TestParseTeamsRulesExport(t *testing.T) {
+	rule := `{
+		"id": "7559a944-3dd7-41bf-b183-360a814a8c36",
+		"name": "block bad websites",
+		"description": "rule description",
+		"precedence": 1000,
+		"enabled": true,
+		"action": "block",
+		"filters": ["dns"],
+		"traffic": "any(dns.domains[*] in $ab2c4f5e-2f4a-4f7a-b0d1-a1d3a6e5c1f9)",
+		"identity": "",
+		"device_posture": "",
+		"version": 3,
+		"created_at": "2014-01-01T05:20:00.12345Z",
+		"updated_at": "2014-01-01T05:20:00.12345Z"
+	}`
+	want := TeamsRule{
+		Name:        "block bad websites",
+		Description: "rule description",
+		Precedence:  1000,
+		Enabled:     true,
+		Action:      Block,
+		Filters:     []TeamsFilterType{DnsFilter},
+		Traffic:     "any(dns.domains[*] in $ab2c4f5e-2f4a-4f7a-b0d1-a1d3a6e5c1f9)",
+	}
+
+	for name, export := range map[string]string{
+		"single":   rule,
+		"array":    "[" + rule + "]",
+		"response": `{"success": true, "errors": [], "messages": [], "result": [` + rule + `]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			rules, err := ParseTeamsRulesExport(strings.NewReader(export))
+			if assert.NoError(t, err) {
+				assert.Equal(t, []TeamsRule{want}, rules)
+			}
+		})
+	}
+
+	_, err := ParseTeamsRulesExport(strings.NewReader("  "))
+	assert.EqualError(t, err, "rules export is empty")
+
+	_, err = ParseTeamsRulesExport(strings.NewReader(`"rule"`))
+	assert.EqualError(t, err, "rules export must be a JSON rule or array of rules")
+
+	_, err = ParseTeamsRulesExport(strings.NewReader(`[{"name": 1}]`))
+	assert.Error(t, err)
+}
@@ -0,0 +1,148 @@
+package cloudflare
+
+import "context"
+
+// TeamsAccountAPI is a handle to the Gateway resources of a single account.
+// Its methods mirror the API methods of the same name without the account
+// ID argument, which is bound when the handle is created.
+//
+//	account := api.WithAccount(accountID)
+//	rules, err := account.TeamsRules(ctx)
+//
+// Use the API methods directly when working with several accounts.
+type TeamsAccountAPI struct {
+	api       *API
+	accountID string
+}
+
+// WithAccount returns a handle to the Gateway resources of accountID.
+func (api *API) WithAccount(accountID string) *TeamsAccountAPI {
+	return &TeamsAccountAPI{api: api, accountID: accountID}
+}
+
+// AccountID returns the account ID the handle is bound to.
+func (a *TeamsAccountAPI) AccountID() string {
+	return a.accountID
+}
+
+// TeamsAccount returns teams account information with internal and external ID.
+func (a *TeamsAccountAPI) TeamsAccount(ctx context.Context) (TeamsAccount, error) {
+	return a.api.TeamsAccount(ctx, a.accountID)
+}
+
+// TeamsAccountConfiguration returns teams account configuration.
+func (a *TeamsAccountAPI) TeamsAccountConfiguration(ctx context.Context) (TeamsConfiguration, error) {
+	return a.api.TeamsAccountConfiguration(ctx, a.accountID)
+}
+
+// TeamsAccountUpdateConfiguration updates a teams account configuration.
+func (a *TeamsAccountAPI) TeamsAccountUpdateConfiguration(ctx context.Context, config TeamsConfiguration) (TeamsConfiguration, error) {
+	return a.api.TeamsAccountUpdateConfiguration(ctx, a.accountID, config)
+}
+
+// TeamsAccountPatchConfiguration updates only the fields of a teams account
+// configuration that are set in patch.
+func (a *TeamsAccountAPI) TeamsAccountPatchConfiguration(ctx context.Context, patch TeamsAccountSettingsPatch) (TeamsConfiguration, error) {
+	return a.api.TeamsAccountPatchConfiguration(ctx, a.accountID, patch)
+}
+
+// TeamsRules returns all rules within the account.
+func (a *TeamsAccountAPI) TeamsRules(ctx context.Context) ([]TeamsRule, error) {
+	return a.api.TeamsRules(ctx, a.accountID)
+}
+
+// TeamsRulesAll returns all rules within the account, following the
+// pagination.
+func (a *TeamsAccountAPI) TeamsRulesAll(ctx context.Context) ([]TeamsRule, error) {
+	return a.api.TeamsRulesAll(ctx, a.accountID)
+}
+
+// TeamsRule returns the rule with ruleID.
+func (a *TeamsAccountAPI) TeamsRule(ctx context.Context, ruleID string) (TeamsRule, error) {
+	return a.api.TeamsRule(ctx, a.accountID, ruleID)
+}
+
+// TeamsCreateRule creates a rule with wirefilter expression.
+func (a *TeamsAccountAPI) TeamsCreateRule(ctx context.Context, rule TeamsRule, opts ...TeamsRuleOption) (TeamsRule, error) {
+	return a.api.TeamsCreateRule(ctx, a.accountID, rule, opts...)
+}
+
+// TeamsUpdateRule updates a rule with wirefilter expression.
+func (a *TeamsAccountAPI) TeamsUpdateRule(ctx context.Context, ruleID string, rule TeamsRule, opts ...TeamsRuleOption) (TeamsRule, error) {
+	return a.api.TeamsUpdateRule(ctx, a.accountID, ruleID, rule, opts...)
+}
+
+// TeamsPatchRule patches a rule associated values.
+func (a *TeamsAccountAPI) TeamsPatchRule(ctx context.Context, ruleID string, rule TeamsRulePatchRequest) (TeamsRule, error) {
+	return a.api.TeamsPatchRule(ctx, a.accountID, ruleID, rule)
+}
+
+// TeamsEnableRule enables a rule.
+func (a *TeamsAccountAPI) TeamsEnableRule(ctx context.Context, ruleID string) (TeamsRule, error) {
+	return a.api.TeamsEnableRule(ctx, a.accountID, ruleID)
+}
+
+// TeamsDisableRule disables a rule.
+func (a *TeamsAccountAPI) TeamsDisableRule(ctx context.Context, ruleID string) (TeamsRule, error) {
+	return a.api.TeamsDisableRule(ctx, a.accountID, ruleID)
+}
+
+// TeamsDeleteRule deletes a rule.
+func (a *TeamsAccountAPI) TeamsDeleteRule(ctx context.Context, ruleID string) error {
+	return a.api.TeamsDeleteRule(ctx, a.accountID, ruleID)
+}
+
+// TeamsLists returns all lists within the account.
+func (a *TeamsAccountAPI) TeamsLists(ctx context.Context, params ...TeamsListsParams) ([]TeamsList, ResultInfo, error) {
+	return a.api.TeamsLists(ctx, a.accountID, params...)
+}
+
+// TeamsList returns a single list based on the list ID.
+func (a *TeamsAccountAPI) TeamsList(ctx context.Context, listID string) (TeamsList, error) {
+	return a.api.TeamsList(ctx, a.accountID, listID)
+}
+
+// CreateTeamsList creates a new teams list.
+func (a *TeamsAccountAPI) CreateTeamsList(ctx context.Context, teamsList TeamsList) (TeamsList, error) {
+	return a.api.CreateTeamsList(ctx, a.accountID, teamsList)
+}
+
+// UpdateTeamsList updates an existing teams list.
+func (a *TeamsAccountAPI) UpdateTeamsList(ctx context.Context, teamsList TeamsList) (TeamsList, error) {
+	return a.api.UpdateTeamsList(ctx, a.accountID, teamsList)
+}
+
+// PatchTeamsList updates the items in an existing teams list.
+func (a *TeamsAccountAPI) PatchTeamsList(ctx context.Context, listPatch PatchTeamsList) (TeamsList, error) {
+	return a.api.PatchTeamsList(ctx, a.accountID, listPatch)
+}
+
+// DeleteTeamsList deletes a teams list.
+func (a *TeamsAccountAPI) DeleteTeamsList(ctx context.Context, teamsListID string) error {
+	return a.api.DeleteTeamsList(ctx, a.accountID, teamsListID)
+}
+
+// TeamsLocations returns all locations within the account.
+func (a *TeamsAccountAPI) TeamsLocations(ctx context.Context) ([]TeamsLocation, ResultInfo, error) {
+	return a.api.TeamsLocations(ctx, a.accountID)
+}
+
+// TeamsLocation returns a single location based on the ID.
+func (a *TeamsAccountAPI) TeamsLocation(ctx context.Context, locationID string) (TeamsLocation, error) {
+	return a.api.TeamsLocation(ctx, a.accountID, locationID)
+}
+
+// CreateTeamsLocation creates a new teams location.
+func (a *TeamsAccountAPI) CreateTeamsLocation(ctx context.Context, teamsLocation TeamsLocation) (TeamsLocation, error) {
+	return a.api.CreateTeamsLocation(ctx, a.accountID, teamsLocation)
+}
+
+// UpdateTeamsLocation updates an existing teams location.
+func (a *TeamsAccountAPI) UpdateTeamsLocation(ctx context.Context, teamsLocation TeamsLocation) (TeamsLocation, error) {
+	return a.api.UpdateTeamsLocation(ctx, a.accountID, teamsLocation)
+}
+
+// DeleteTeamsLocation deletes a teams location.
+func (a *TeamsAccountAPI) DeleteTeamsLocation(ctx context.Context, teamsLocationID string) error {
+	return a.api.DeleteTeamsLocation(ctx, a.accountID, teamsLocationID)
+}
@@ -0,0 +1,37 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsAccountAPI(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "7559a944-3dd7-41bf-b183-360a814a8c36", "name": "rule1"}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/7559a944-3dd7-41bf-b183-360a814a8c36", handler)
+
+	account := client.WithAccount(testAccountID)
+	assert.Equal(t, testAccountID, account.AccountID())
+
+	actual, err := account.TeamsRule(context.Background(), "7559a944-3dd7-41bf-b183-360a814a8c36")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{ID: "7559a944-3dd7-41bf-b183-360a814a8c36", Name: "rule1"}, actual)
+	}
+}
@@ -0,0 +1,170 @@
+package cloudflare
+
+import (
+	"context"
+)
+
+// The API has no endpoints for individual settings, so the methods below
+// read the whole configuration and, for updates, write it back with only the
+// one setting replaced. Updates are made with
+// TeamsAccountUpdateConfigurationIfUnmodified and return ErrConfigConflict
+// rather than overwrite a configuration changed since it was read.
+
+// updateTeamsAccountSetting reads the configuration of the account, applies
+// set to its settings and writes it back.
+func (api *API) updateTeamsAccountSetting(ctx context.Context, accountID string, set func(*TeamsAccountSettings)) (TeamsAccountSettings, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsAccountSettings{}, err
+	}
+
+	set(&config.Settings)
+
+	// a configuration that was never updated has no UpdatedAt to compare
+	if config.UpdatedAt.IsZero() {
+		config, err = api.TeamsAccountUpdateConfiguration(ctx, accountID, config)
+	} else {
+		config, err = api.TeamsAccountUpdateConfigurationIfUnmodified(ctx, accountID, config)
+	}
+	if err != nil {
+		return TeamsAccountSettings{}, err
+	}
+
+	return config.Settings, nil
+}
+
+// TeamsAccountAntivirus returns the antivirus settings of the account.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountAntivirus(ctx context.Context, accountID string) (TeamsAntivirus, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil || config.Settings.Antivirus == nil {
+		return TeamsAntivirus{}, err
+	}
+
+	return *config.Settings.Antivirus, nil
+}
+
+// UpdateTeamsAccountAntivirus replaces the antivirus settings of the
+// account, leaving the other settings untouched.
+//
+// API reference: TBA.
+func (api *API) UpdateTeamsAccountAntivirus(ctx context.Context, accountID string, antivirus TeamsAntivirus) (TeamsAntivirus, error) {
+	settings, err := api.updateTeamsAccountSetting(ctx, accountID, func(s *TeamsAccountSettings) {
+		s.Antivirus = &antivirus
+	})
+	if err != nil || settings.Antivirus == nil {
+		return TeamsAntivirus{}, err
+	}
+
+	return *settings.Antivirus, nil
+}
+
+// TeamsAccountTLSDecrypt returns the TLS decryption settings of the account.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountTLSDecrypt(ctx context.Context, accountID string) (TeamsTLSDecrypt, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil || config.Settings.TLSDecrypt == nil {
+		return TeamsTLSDecrypt{}, err
+	}
+
+	return *config.Settings.TLSDecrypt, nil
+}
+
+// UpdateTeamsAccountTLSDecrypt replaces the TLS decryption settings of the
+// account, leaving the other settings untouched.
+//
+// API reference: TBA.
+func (api *API) UpdateTeamsAccountTLSDecrypt(ctx context.Context, accountID string, tlsDecrypt TeamsTLSDecrypt) (TeamsTLSDecrypt, error) {
+	settings, err := api.updateTeamsAccountSetting(ctx, accountID, func(s *TeamsAccountSettings) {
+		s.TLSDecrypt = &tlsDecrypt
+	})
+	if err != nil || settings.TLSDecrypt == nil {
+		return TeamsTLSDecrypt{}, err
+	}
+
+	return *settings.TLSDecrypt, nil
+}
+
+// TeamsAccountActivityLog returns the activity log settings of the account.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountActivityLog(ctx context.Context, accountID string) (TeamsActivityLog, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil || config.Settings.ActivityLog == nil {
+		return TeamsActivityLog{}, err
+	}
+
+	return *config.Settings.ActivityLog, nil
+}
+
+// UpdateTeamsAccountActivityLog replaces the activity log settings of the
+// account, leaving the other settings untouched.
+//
+// API reference: TBA.
+func (api *API) UpdateTeamsAccountActivityLog(ctx context.Context, accountID string, activityLog TeamsActivityLog) (TeamsActivityLog, error) {
+	settings, err := api.updateTeamsAccountSetting(ctx, accountID, func(s *TeamsAccountSettings) {
+		s.ActivityLog = &activityLog
+	})
+	if err != nil || settings.ActivityLog == nil {
+		return TeamsActivityLog{}, err
+	}
+
+	return *settings.ActivityLog, nil
+}
+
+// TeamsAccountFIPS returns the FIPS settings of the account.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountFIPS(ctx context.Context, accountID string) (TeamsFIPS, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil || config.Settings.FIPS == nil {
+		return TeamsFIPS{}, err
+	}
+
+	return *config.Settings.FIPS, nil
+}
+
+// UpdateTeamsAccountFIPS replaces the FIPS settings of the account, leaving
+// the other settings untouched.
+//
+// API reference: TBA.
+func (api *API) UpdateTeamsAccountFIPS(ctx context.Context, accountID string, fips TeamsFIPS) (TeamsFIPS, error) {
+	settings, err := api.updateTeamsAccountSetting(ctx, accountID, func(s *TeamsAccountSettings) {
+		s.FIPS = &fips
+	})
+	if err != nil || settings.FIPS == nil {
+		return TeamsFIPS{}, err
+	}
+
+	return *settings.FIPS, nil
+}
+
+// TeamsAccountBrowserIsolation returns the browser isolation settings of the
+// account.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountBrowserIsolation(ctx context.Context, accountID string) (BrowserIsolation, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil || config.Settings.BrowserIsolation == nil {
+		return BrowserIsolation{}, err
+	}
+
+	return *config.Settings.BrowserIsolation, nil
+}
+
+// UpdateTeamsAccountBrowserIsolation replaces the browser isolation settings
+// of the account, leaving the other settings untouched.
+//
+// API reference: TBA.
+func (api *API) UpdateTeamsAccountBrowserIsolation(ctx context.Context, accountID string, settings BrowserIsolation) (BrowserIsolation, error) {
+	updated, err := api.updateTeamsAccountSetting(ctx, accountID, func(s *TeamsAccountSettings) {
+		s.BrowserIsolation = &settings
+	})
+	if err != nil || updated.BrowserIsolation == nil {
+		return BrowserIsolation{}, err
+	}
+
+	return *updated.BrowserIsolation, nil
+}
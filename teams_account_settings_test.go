@@ -0,0 +1,148 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateTeamsAccountAntivirus(t *testing.T) {
+	setup()
+	defer teardown()
+
+	config := `{
+		"settings": {
+			"antivirus": {"enabled_download_phase": false, "enabled_upload_phase": false, "fail_closed": false},
+			"fips": {"tls": true},
+			"activity_log": {"enabled": true}
+		},
+		"created_at": "2022-10-01T12:00:00Z",
+		"updated_at": "2022-10-02T12:00:00Z"
+	}`
+
+	var put string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			put = string(body)
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+			return
+		}
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, config)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	antivirus, err := client.UpdateTeamsAccountAntivirus(context.Background(), testAccountID, TeamsAntivirus{
+		EnabledDownloadPhase: true,
+		FailClosed:           true,
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsAntivirus{EnabledDownloadPhase: true, FailClosed: true}, antivirus)
+		assert.JSONEq(t, `{
+			"settings": {
+				"antivirus": {"enabled_download_phase": true, "enabled_upload_phase": false, "fail_closed": true},
+				"fips": {"tls": true},
+				"activity_log": {"enabled": true}
+			},
+			"created_at": "2022-10-01T12:00:00Z",
+			"updated_at": "2022-10-02T12:00:00Z"
+		}`, put)
+	}
+
+	fips, err := client.TeamsAccountFIPS(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsFIPS{TLS: true}, fips)
+	}
+
+	tlsDecrypt, err := client.TeamsAccountTLSDecrypt(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsTLSDecrypt{}, tlsDecrypt)
+	}
+}
+
+func TestUpdateTeamsAccountBrowserIsolation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var put string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodPut {
+			body, _ := ioutil.ReadAll(r.Body)
+			put = string(body)
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+			return
+		}
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"antivirus": {"enabled_download_phase": true, "enabled_upload_phase": false, "fail_closed": true},
+					"fips": {"tls": true},
+					"browser_isolation": {"url_browser_isolation_enabled": false}
+				},
+				"created_at": "2022-10-01T12:00:00Z",
+				"updated_at": "2022-10-02T12:00:00Z"
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	isolation, err := client.UpdateTeamsAccountBrowserIsolation(context.Background(), testAccountID, BrowserIsolation{
+		UrlBrowserIsolationEnabled: true,
+		NonIdentityEnabled:         BoolPtr(true),
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, BrowserIsolation{UrlBrowserIsolationEnabled: true, NonIdentityEnabled: BoolPtr(true)}, isolation)
+		assert.JSONEq(t, `{
+			"settings": {
+				"antivirus": {"enabled_download_phase": true, "enabled_upload_phase": false, "fail_closed": true},
+				"fips": {"tls": true},
+				"browser_isolation": {"url_browser_isolation_enabled": true, "non_identity_enabled": true}
+			},
+			"created_at": "2022-10-01T12:00:00Z",
+			"updated_at": "2022-10-02T12:00:00Z"
+		}`, put)
+	}
+}
+
+func TestUpdateTeamsAccountActivityLogConflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	gets := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		gets++
+		w.Header().Set("content-type", "application/json")
+		// the configuration changes between the read and the write
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {"activity_log": {"enabled": true}},
+				"created_at": "2022-10-01T12:00:00Z",
+				"updated_at": "2022-10-0%dT12:00:00Z"
+			}
+		}`, gets)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	_, err := client.UpdateTeamsAccountActivityLog(context.Background(), testAccountID, TeamsActivityLog{})
+	assert.Equal(t, ErrConfigConflict, err)
+}
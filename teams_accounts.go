@@ -1,10 +1,19 @@
 package cloudflare
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -35,46 +44,567 @@ type TeamsConfiguration struct {
 	UpdatedAt time.Time            `json:"updated_at,omitempty"`
 }
 
+// UnmarshalJSON decodes the configuration, treating empty or null
+// timestamps, which the API returns for newly created configurations, as
+// zero times.
+func (c *TeamsConfiguration) UnmarshalJSON(data []byte) error {
+	type Alias TeamsConfiguration
+	aux := struct {
+		*Alias
+		CreatedAt *string `json:"created_at"`
+		UpdatedAt *string `json:"updated_at"`
+	}{Alias: (*Alias)(c)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	var err error
+	if c.CreatedAt, err = parseTeamsConfigurationTime(aux.CreatedAt); err != nil {
+		return err
+	}
+	if c.UpdatedAt, err = parseTeamsConfigurationTime(aux.UpdatedAt); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func parseTeamsConfigurationTime(value *string) (time.Time, error) {
+	if value == nil || *value == "" {
+		return time.Time{}, nil
+	}
+	return time.Parse(time.RFC3339Nano, *value)
+}
+
 type TeamsAccountSettings struct {
-	Antivirus        *TeamsAntivirus   `json:"antivirus,omitempty"`
-	TLSDecrypt       *TeamsTLSDecrypt  `json:"tls_decrypt,omitempty"`
-	ActivityLog      *TeamsActivityLog `json:"activity_log,omitempty"`
-	BlockPage        *TeamsBlockPage   `json:"block_page,omitempty"`
-	BrowserIsolation *BrowserIsolation `json:"browser_isolation,omitempty"`
-	FIPS             *TeamsFIPS        `json:"fips,omitempty"`
+	Antivirus             *TeamsAntivirus             `json:"antivirus,omitempty"`
+	TLSDecrypt            *TeamsTLSDecrypt            `json:"tls_decrypt,omitempty"`
+	ActivityLog           *TeamsActivityLog           `json:"activity_log,omitempty"`
+	BlockPage             *TeamsBlockPage             `json:"block_page,omitempty"`
+	BrowserIsolation      *BrowserIsolation           `json:"browser_isolation,omitempty"`
+	FIPS                  *TeamsFIPS                  `json:"fips,omitempty"`
+	ProtocolDetection     *TeamsProtocolDetection     `json:"protocol_detection,omitempty"`
+	BodyScanning          *TeamsBodyScanning          `json:"body_scanning,omitempty"`
+	ExtendedEmailMatching *TeamsExtendedEmailMatching `json:"extended_email_matching,omitempty"`
+	CustomCertificate     *TeamsCustomCertificate     `json:"custom_certificate,omitempty"`
+	UntrustedCertSettings *TeamsUntrustedCertSettings `json:"untrusted_cert,omitempty"`
+	Sandbox               *TeamsSandbox               `json:"sandbox,omitempty"`
+	HostSelector          *TeamsHostSelector          `json:"host_selector,omitempty"`
+	EmailLinkIsolation    *TeamsEmailLinkIsolation    `json:"email_link_isolation,omitempty"`
+
+	// InspectionMode is the account wide traffic inspection level. It is
+	// separate from the TLS decryption of individual rules and from the
+	// file type detection of BodyScanning.
+	InspectionMode *TeamsInspectionMode `json:"inspection_mode,omitempty"`
+
+	// Extra holds the settings returned by the API that aren't modelled
+	// above so that they survive a read, modify, write round-trip. Settings
+	// without a documented schema can be read and set here by their JSON
+	// key.
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+// UnmarshalJSON decodes the known settings and keeps any others in Extra.
+func (s *TeamsAccountSettings) UnmarshalJSON(data []byte) error {
+	type Alias TeamsAccountSettings
+	var known Alias
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	extra, err := unmodelledJSONFields(data, TeamsAccountSettings{})
+	if err != nil {
+		return err
+	}
+
+	known.Extra = extra
+	*s = TeamsAccountSettings(known)
+	return nil
+}
+
+// MarshalJSON encodes the known settings along with any in Extra. Known
+// settings take precedence over an Extra entry with the same key.
+func (s TeamsAccountSettings) MarshalJSON() ([]byte, error) {
+	type Alias TeamsAccountSettings
+	data, err := json.Marshal(Alias(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeJSONFields(data, s.Extra)
+}
+
+// teamsAccountSettingsKeys returns the JSON keys of the settings modelled by
+// TeamsAccountSettings.
+func teamsAccountSettingsKeys() []string {
+	return jsonFieldNames(TeamsAccountSettings{})
 }
 
+// jsonFieldNames returns the JSON keys of the fields of the struct v.
+func jsonFieldNames(v interface{}) []string {
+	t := reflect.TypeOf(v)
+	keys := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// unmodelledJSONFields returns the fields of the JSON object data that
+// aren't fields of the struct v, or nil if there are none.
+func unmodelledJSONFields(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for _, key := range jsonFieldNames(v) {
+		delete(fields, key)
+	}
+
+	if len(fields) == 0 {
+		return nil, nil
+	}
+	return fields, nil
+}
+
+// mergeJSONFields adds the extra fields missing from the JSON object data.
+func mergeJSONFields(data []byte, extra map[string]json.RawMessage) ([]byte, error) {
+	if len(extra) == 0 {
+		return data, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	for key, value := range extra {
+		if _, ok := fields[key]; !ok {
+			fields[key] = value
+		}
+	}
+
+	return json.Marshal(fields)
+}
+
+// TeamsSettingChange is a single difference between two configurations.
+// Path is the dotted JSON path of the setting, such as
+// "settings.antivirus.fail_closed". Old and New are nil when the setting is
+// unset on that side.
+type TeamsSettingChange struct {
+	Path string
+	Old  interface{}
+	New  interface{}
+}
+
+// Diff returns the settings that differ between c and other, recursing into
+// the nested settings. A nil setting differs from a set one even when all of
+// its fields hold zero values.
+func (c TeamsConfiguration) Diff(other TeamsConfiguration) []TeamsSettingChange {
+	return diffTeamsSettings("settings", reflect.ValueOf(c.Settings), reflect.ValueOf(other.Settings))
+}
+
+func diffTeamsSettings(path string, from, to reflect.Value) []TeamsSettingChange {
+	switch from.Kind() {
+	case reflect.Ptr:
+		if from.IsNil() && to.IsNil() {
+			return nil
+		}
+		if from.IsNil() || to.IsNil() {
+			return []TeamsSettingChange{{Path: path, Old: teamsSettingValue(from), New: teamsSettingValue(to)}}
+		}
+		return diffTeamsSettings(path, from.Elem(), to.Elem())
+
+	case reflect.Struct:
+		if _, ok := from.Interface().(time.Time); ok {
+			break
+		}
+
+		var changes []TeamsSettingChange
+		for i := 0; i < from.NumField(); i++ {
+			field := from.Type().Field(i)
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "" || name == "-" {
+				if field.Type.Kind() != reflect.Map {
+					continue
+				}
+				// unmodelled settings are keyed by their JSON name
+				changes = append(changes, diffTeamsSettings(path, from.Field(i), to.Field(i))...)
+				continue
+			}
+			changes = append(changes, diffTeamsSettings(path+"."+name, from.Field(i), to.Field(i))...)
+		}
+		return changes
+
+	case reflect.Map:
+		keys := map[string]reflect.Value{}
+		for _, key := range append(from.MapKeys(), to.MapKeys()...) {
+			keys[fmt.Sprint(key.Interface())] = key
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var changes []TeamsSettingChange
+		for _, name := range names {
+			fromValue, toValue := from.MapIndex(keys[name]), to.MapIndex(keys[name])
+			if fromValue.IsValid() && toValue.IsValid() && teamsSettingValuesEqual(fromValue.Interface(), toValue.Interface()) {
+				continue
+			}
+			changes = append(changes, TeamsSettingChange{Path: path + "." + name, Old: teamsSettingValue(fromValue), New: teamsSettingValue(toValue)})
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(from.Interface(), to.Interface()) {
+		return nil
+	}
+	return []TeamsSettingChange{{Path: path, Old: from.Interface(), New: to.Interface()}}
+}
+
+// teamsSettingValuesEqual reports whether a and b hold the same setting.
+// Unmodelled settings are compared by their decoded JSON so formatting and
+// key order don't count as a difference.
+func teamsSettingValuesEqual(a, b interface{}) bool {
+	rawA, okA := a.(json.RawMessage)
+	rawB, okB := b.(json.RawMessage)
+	if !okA || !okB {
+		return reflect.DeepEqual(a, b)
+	}
+
+	var decodedA, decodedB interface{}
+	if json.Unmarshal(rawA, &decodedA) != nil || json.Unmarshal(rawB, &decodedB) != nil {
+		return bytes.Equal(rawA, rawB)
+	}
+
+	return reflect.DeepEqual(decodedA, decodedB)
+}
+
+// teamsSettingValue returns the value v holds, dereferencing pointers, or nil
+// when it's unset.
+func teamsSettingValue(v reflect.Value) interface{} {
+	if !v.IsValid() || (v.Kind() == reflect.Ptr && v.IsNil()) {
+		return nil
+	}
+	if v.Kind() == reflect.Ptr {
+		return v.Elem().Interface()
+	}
+	return v.Interface()
+}
+
+// BrowserIsolation contains the account wide browser isolation settings.
+//
+// NonIdentityEnabled allows traffic from devices that aren't enrolled in
+// WARP to be isolated; it is the only non-identity field of this setting.
+// The configuration API has no on-ramp subdomain or pool: that traffic
+// reaches isolation through a Gateway proxy endpoint, so the subdomain and
+// source IPs are managed with TeamsProxyEndpoint instead.
+//
+// Clipboard, printing, upload, download and keyboard controls aren't account
+// wide settings. They are set per isolate rule with the BISOAdminControls
+// rule setting (see TeamsBISOAdminControlSettings).
+//
+// Isolation is limited by the account's seats; see
+// TeamsBrowserIsolationSeats for the seats in use.
 type BrowserIsolation struct {
-	UrlBrowserIsolationEnabled bool `json:"url_browser_isolation_enabled"`
+	UrlBrowserIsolationEnabled bool  `json:"url_browser_isolation_enabled"`
+	NonIdentityEnabled         *bool `json:"non_identity_enabled,omitempty"`
 }
 
+// TeamsAntivirus contains the account wide antivirus scanning settings.
+// The API doesn't expose a file-size limit for scanning: files above the
+// limit Gateway applies are never scanned, so there's no field for it here.
 type TeamsAntivirus struct {
 	EnabledDownloadPhase bool `json:"enabled_download_phase"`
 	EnabledUploadPhase   bool `json:"enabled_upload_phase"`
-	FailClosed           bool `json:"fail_closed"`
+
+	// FailClosed blocks requests that can't be scanned. The API only
+	// exposes a single flag which applies to both the upload and download
+	// phases.
+	FailClosed bool `json:"fail_closed"`
+
+	NotificationSettings *TeamsNotificationSettings `json:"notification_settings,omitempty"`
+}
+
+// TeamsNotificationSettings controls the notification the WARP client shows
+// to users when a file is blocked by antivirus scanning or, when set in the
+// rule settings, when a rule matches.
+type TeamsNotificationSettings struct {
+	Enabled    *bool  `json:"enabled,omitempty"`
+	Message    string `json:"msg,omitempty"`
+	SupportURL string `json:"support_url,omitempty"`
+}
+
+// Validate checks that SupportURL, when set, is an absolute http(s) URL.
+func (s TeamsNotificationSettings) Validate() error {
+	if s.SupportURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(s.SupportURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid notification support URL %q: must be an absolute http or https URL", s.SupportURL)
+	}
+
+	return nil
 }
 
+type TeamsProtocolDetection struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TeamsBodyScanningMode is how deep file bodies are inspected to detect
+// their type.
+type TeamsBodyScanningMode = string
+
+const (
+	TeamsBodyScanningDeep    TeamsBodyScanningMode = "deep"
+	TeamsBodyScanningShallow TeamsBodyScanningMode = "shallow"
+)
+
+// TeamsInspectionMode is the account wide traffic inspection level.
+type TeamsInspectionMode = string
+
+const (
+	TeamsInspectionNone     TeamsInspectionMode = "none"
+	TeamsInspectionStandard TeamsInspectionMode = "standard"
+	TeamsInspectionDeep     TeamsInspectionMode = "deep"
+)
+
+type TeamsBodyScanning struct {
+	InspectionMode TeamsBodyScanningMode `json:"inspection_mode,omitempty"`
+}
+
+// TeamsUntrustedCertAction is what Gateway does when an upstream server
+// presents an untrusted certificate.
+type TeamsUntrustedCertAction = string
+
+const (
+	TeamsUntrustedCertPassThrough TeamsUntrustedCertAction = "pass_through"
+	TeamsUntrustedCertBlock       TeamsUntrustedCertAction = "block"
+	TeamsUntrustedCertError       TeamsUntrustedCertAction = "error"
+)
+
+type TeamsUntrustedCertSettings struct {
+	Action TeamsUntrustedCertAction `json:"action,omitempty"`
+}
+
+// TeamsSandboxFallbackAction is what happens to a file when sandbox
+// detonation doesn't finish in time.
+type TeamsSandboxFallbackAction = string
+
+const (
+	TeamsSandboxFallbackAllow TeamsSandboxFallbackAction = "allow"
+	TeamsSandboxFallbackBlock TeamsSandboxFallbackAction = "block"
+)
+
+// TeamsSandbox contains the account wide file sandbox settings.
+type TeamsSandbox struct {
+	Enabled        bool                       `json:"enabled"`
+	FallbackAction TeamsSandboxFallbackAction `json:"fallback_action,omitempty"`
+}
+
+// TeamsHostSelector enables selecting traffic by host name rather than
+// destination IP in policies.
+type TeamsHostSelector struct {
+	Enabled bool `json:"enabled"`
+}
+
+type TeamsExtendedEmailMatching struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TeamsEmailLinkIsolation opens the links of emails in an isolated browser
+// when they lead to suspicious or unknown sites.
+type TeamsEmailLinkIsolation struct {
+	Enabled bool `json:"enabled"`
+}
+
+// TeamsFIPS contains the FIPS compliance settings.
+//
+// TLS restricts Gateway to FIPS 140-2 compliant cipher suites. The API
+// doesn't report the resulting cipher suites. Any other FIPS fields
+// returned are kept in Extra so that they are reported and survive a read,
+// modify, write round-trip.
 type TeamsFIPS struct {
 	TLS bool `json:"tls"`
+	// Required is set on accounts where FIPS compliance is mandated.
+	Required *bool `json:"required,omitempty"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// UnmarshalJSON decodes the FIPS settings and keeps unknown ones in Extra.
+func (f *TeamsFIPS) UnmarshalJSON(data []byte) error {
+	type Alias TeamsFIPS
+	var known Alias
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	extra, err := unmodelledJSONFields(data, TeamsFIPS{})
+	if err != nil {
+		return err
+	}
+
+	known.Extra = extra
+	*f = TeamsFIPS(known)
+	return nil
+}
+
+// MarshalJSON encodes the FIPS settings along with any in Extra.
+func (f TeamsFIPS) MarshalJSON() ([]byte, error) {
+	type Alias TeamsFIPS
+	data, err := json.Marshal(Alias(f))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeJSONFields(data, f.Extra)
+}
+
+// TeamsTLSDecrypt turns TLS decryption on or off for the whole account. The
+// API only has this single flag, it has no per protocol toggles; which
+// traffic is decrypted is chosen per rule with the do not inspect action
+// (Off). Any other fields returned are kept in Extra so that they are
+// reported and survive a read, modify, write round-trip.
 type TeamsTLSDecrypt struct {
 	Enabled bool `json:"enabled"`
+
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
+// UnmarshalJSON decodes the TLS decryption settings and keeps unknown ones
+// in Extra.
+func (d *TeamsTLSDecrypt) UnmarshalJSON(data []byte) error {
+	type Alias TeamsTLSDecrypt
+	var known Alias
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+
+	extra, err := unmodelledJSONFields(data, TeamsTLSDecrypt{})
+	if err != nil {
+		return err
+	}
+
+	known.Extra = extra
+	*d = TeamsTLSDecrypt(known)
+	return nil
+}
+
+// MarshalJSON encodes the TLS decryption settings along with any in Extra.
+func (d TeamsTLSDecrypt) MarshalJSON() ([]byte, error) {
+	type Alias TeamsTLSDecrypt
+	data, err := json.Marshal(Alias(d))
+	if err != nil {
+		return nil, err
+	}
+
+	return mergeJSONFields(data, d.Extra)
+}
+
+// TeamsCustomCertificate selects a certificate uploaded by the customer for
+// TLS inspection instead of the Cloudflare managed one. BindingStatus and
+// UpdatedAt are reported by the API; they're sent back unchanged when the
+// configuration is updated, so leave them as read or clear them.
+type TeamsCustomCertificate struct {
+	Enabled       bool       `json:"enabled"`
+	ID            string     `json:"id,omitempty"`
+	BindingStatus string     `json:"binding_status,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// TeamsActivityLog turns Gateway activity logging on or off for the whole
+// account. The configuration endpoint only exposes this toggle. Which rule
+// types are logged is set per type with TeamsLoggingSettings through
+// TeamsAccountUpdateLoggingConfiguration.
+//
+// The API neither reports nor accepts a retention period or the volume of
+// logs. Logs are kept for the retention of the account's plan; to keep them
+// longer or measure their volume, push them with Logpush, see
+// GatewayLogpushJobs.
 type TeamsActivityLog struct {
 	Enabled bool `json:"enabled"`
 }
 
+// TeamsBlockPageMode is how users are shown that a request was blocked.
+type TeamsBlockPageMode = string
+
+const (
+	// TeamsBlockPageCustomized shows the block page described by the
+	// visual fields of TeamsBlockPage.
+	TeamsBlockPageCustomized TeamsBlockPageMode = "customized_block_page"
+	// TeamsBlockPageRedirect redirects users to TargetURI.
+	TeamsBlockPageRedirect TeamsBlockPageMode = "redirect_uri"
+)
+
+// TeamsBlockPage is the page shown to users whose requests are blocked. In
+// redirect mode users are sent to TargetURI and the visual fields are unused.
 type TeamsBlockPage struct {
-	Enabled         *bool  `json:"enabled,omitempty"`
-	FooterText      string `json:"footer_text,omitempty"`
-	HeaderText      string `json:"header_text,omitempty"`
-	LogoPath        string `json:"logo_path,omitempty"`
-	BackgroundColor string `json:"background_color,omitempty"`
-	Name            string `json:"name,omitempty"`
-	MailtoAddress   string `json:"mailto_address,omitempty"`
-	MailtoSubject   string `json:"mailto_subject,omitempty"`
+	Enabled         *bool              `json:"enabled,omitempty"`
+	Mode            TeamsBlockPageMode `json:"mode,omitempty"`
+	TargetURI       string             `json:"target_uri,omitempty"`
+	FooterText      string             `json:"footer_text,omitempty"`
+	HeaderText      string             `json:"header_text,omitempty"`
+	LogoPath        string             `json:"logo_path,omitempty"`
+	BackgroundColor string             `json:"background_color,omitempty"`
+	Name            string             `json:"name,omitempty"`
+	MailtoAddress   string             `json:"mailto_address,omitempty"`
+	MailtoSubject   string             `json:"mailto_subject,omitempty"`
+	// IncludeContext appends the rule and user context to the mailto link.
+	IncludeContext *bool `json:"include_context,omitempty"`
+	// SuppressFooter hides the Cloudflare footer on the block page.
+	SuppressFooter *bool `json:"suppress_footer,omitempty"`
+}
+
+var teamsBlockPageColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// Validate checks that BackgroundColor, when set, is a #RRGGBB hex color and
+// that MailtoAddress, when set, is an email address. In redirect mode only
+// TargetURI is checked, which must be an absolute http(s) URL.
+func (b TeamsBlockPage) Validate() error {
+	if errs := b.validationErrors(); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+func (b TeamsBlockPage) validationErrors() []error {
+	var errs []error
+	switch b.Mode {
+	case "", TeamsBlockPageCustomized:
+	case TeamsBlockPageRedirect:
+		u, err := url.Parse(b.TargetURI)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("invalid block page target URI %q: must be an absolute http or https URL", b.TargetURI))
+		}
+		return errs
+	default:
+		errs = append(errs, fmt.Errorf("invalid block page mode %q, must be one of %s, %s", b.Mode, TeamsBlockPageCustomized, TeamsBlockPageRedirect))
+	}
+
+	if b.BackgroundColor != "" && !teamsBlockPageColorRegexp.MatchString(b.BackgroundColor) {
+		errs = append(errs, fmt.Errorf("invalid block page background color %q: must be a #RRGGBB hex color", b.BackgroundColor))
+	}
+
+	if b.MailtoAddress != "" {
+		if _, err := mail.ParseAddress(b.MailtoAddress); err != nil {
+			errs = append(errs, fmt.Errorf("invalid block page mailto address %q: %w", b.MailtoAddress, err))
+		}
+	} else if b.MailtoSubject != "" {
+		errs = append(errs, errors.New("block page mailto subject requires a mailto address"))
+	}
+
+	return errs
 }
 
 type TeamsRuleType = string
@@ -85,19 +615,120 @@ const (
 	TeamsL4RuleType   TeamsRuleType = "l4"
 )
 
+// teamsRuleTypes are the rule types that logging can be configured for.
+var teamsRuleTypes = []TeamsRuleType{TeamsHttpRuleType, TeamsDnsRuleType, TeamsL4RuleType}
+
 type TeamsAccountLoggingConfiguration struct {
 	LogAll    bool `json:"log_all"`
 	LogBlocks bool `json:"log_blocks"`
 }
 
+// TeamsLoggingSettings controls what Gateway logs for each rule type.
+//
+// RedactPii applies to the logs of every rule type; the API has no per rule
+// type redaction. To keep PII out of one rule type's logs while keeping the
+// others complete, turn logging off for that rule type instead.
 type TeamsLoggingSettings struct {
 	LoggingSettingsByRuleType map[TeamsRuleType]TeamsAccountLoggingConfiguration `json:"settings_by_rule_type"`
 	RedactPii                 bool                                               `json:"redact_pii,omitempty"`
 }
 
+// TeamsAccountLoggingDefaults returns the recommended logging settings for a
+// teams account: every rule type logs all requests and PII is not redacted.
+// The API doesn't expose defaults so these mirror a newly provisioned account.
+func TeamsAccountLoggingDefaults() TeamsLoggingSettings {
+	return TeamsLoggingSettings{
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsHttpRuleType: {LogAll: true, LogBlocks: true},
+			TeamsDnsRuleType:  {LogAll: true, LogBlocks: true},
+			TeamsL4RuleType:   {LogAll: true, LogBlocks: true},
+		},
+	}
+}
+
+// DefaultTeamsLoggingSettings returns logging settings that log blocked
+// requests of every rule type and no others. Unlike
+// TeamsAccountLoggingDefaults, which logs everything, it keeps the volume of
+// logs down while still recording what Gateway blocks.
+func DefaultTeamsLoggingSettings() TeamsLoggingSettings {
+	return TeamsLoggingSettings{
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsHttpRuleType: {LogBlocks: true},
+			TeamsDnsRuleType:  {LogBlocks: true},
+			TeamsL4RuleType:   {LogBlocks: true},
+		},
+	}
+}
+
+// WithRuleTypeLogging returns a copy of the settings with the logging of
+// ruleType replaced, keeping the settings of the other rule types. It can be
+// chained to build settings from the current ones:
+//
+//	settings = settings.
+//		WithRuleTypeLogging(TeamsDnsRuleType, false, true).
+//		WithRuleTypeLogging(TeamsL4RuleType, true, true)
+//
+// The settings it's called on are left unchanged.
+func (s TeamsLoggingSettings) WithRuleTypeLogging(ruleType TeamsRuleType, logAll, logBlocks bool) TeamsLoggingSettings {
+	settings := make(map[TeamsRuleType]TeamsAccountLoggingConfiguration, len(s.LoggingSettingsByRuleType)+1)
+	for t, config := range s.LoggingSettingsByRuleType {
+		settings[t] = config
+	}
+	settings[ruleType] = TeamsAccountLoggingConfiguration{LogAll: logAll, LogBlocks: logBlocks}
+
+	s.LoggingSettingsByRuleType = settings
+	return s
+}
+
+// Logpush datasets holding the Gateway activity logs. Gateway logs are
+// pushed to a destination by an account Logpush job for each dataset, see
+// CreateAccountLogpushJob; the logging settings only control what is logged.
+const (
+	GatewayDNSLogpushDataset     = "gateway_dns"
+	GatewayHTTPLogpushDataset    = "gateway_http"
+	GatewayNetworkLogpushDataset = "gateway_network"
+)
+
+// GatewayLogpushJobs returns the account Logpush jobs that push Gateway
+// activity logs.
+func (api *API) GatewayLogpushJobs(ctx context.Context, accountID string) ([]LogpushJob, error) {
+	jobs, err := api.ListAccountLogpushJobs(ctx, accountID)
+	if err != nil {
+		return []LogpushJob{}, err
+	}
+
+	gatewayJobs := []LogpushJob{}
+	for _, job := range jobs {
+		switch job.Dataset {
+		case GatewayDNSLogpushDataset, GatewayHTTPLogpushDataset, GatewayNetworkLogpushDataset:
+			gatewayJobs = append(gatewayJobs, job)
+		}
+	}
+
+	return gatewayJobs, nil
+}
+
+// TeamsDeviceSettings holds the account wide Gateway proxy settings of WARP
+// devices. WARP client settings such as the captive portal timeout, support
+// URL, mode switch, auto connect and automatic updates (AllowUpdates) are
+// part of the default device settings policy, see
+// DefaultDeviceSettingsPolicy.
+//
+// RootCertificateInstallationEnabled makes the WARP client install the
+// Gateway root certificate, which TLS inspection relies on. It applies to
+// every device of the account; the API has no per policy setting for it.
 type TeamsDeviceSettings struct {
-	GatewayProxyEnabled    bool `json:"gateway_proxy_enabled"`
-	GatewayProxyUDPEnabled bool `json:"gateway_udp_proxy_enabled"`
+	GatewayProxyEnabled                bool  `json:"gateway_proxy_enabled"`
+	GatewayProxyUDPEnabled             bool  `json:"gateway_udp_proxy_enabled"`
+	RootCertificateInstallationEnabled *bool `json:"root_certificate_installation_enabled,omitempty"`
+}
+
+// TeamsDeviceSettingsUpdateParams holds the device settings to change. Fields
+// left nil keep their current value.
+type TeamsDeviceSettingsUpdateParams struct {
+	GatewayProxyEnabled                *bool `json:"gateway_proxy_enabled,omitempty"`
+	GatewayProxyUDPEnabled             *bool `json:"gateway_udp_proxy_enabled,omitempty"`
+	RootCertificateInstallationEnabled *bool `json:"root_certificate_installation_enabled,omitempty"`
 }
 
 type TeamsDeviceSettingsResponse struct {
@@ -110,14 +741,245 @@ type TeamsLoggingSettingsResponse struct {
 	Result TeamsLoggingSettings `json:"result"`
 }
 
+// ValidateTeamsConfiguration checks a configuration without sending it to
+// the API, which has no validation endpoint, and returns every problem found.
+// It covers formats (colors, email addresses, URLs), enum values and
+// settings that depend on each other. The same checks run before a
+// configuration is updated.
+func ValidateTeamsConfiguration(config TeamsConfiguration) []error {
+	var errs []error
+	settings := config.Settings
+
+	if av := settings.Antivirus; av != nil && av.NotificationSettings != nil {
+		if err := av.NotificationSettings.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if settings.BlockPage != nil {
+		errs = append(errs, settings.BlockPage.validationErrors()...)
+	}
+
+	if bs := settings.BodyScanning; bs != nil && bs.InspectionMode != "" {
+		if bs.InspectionMode != TeamsBodyScanningDeep && bs.InspectionMode != TeamsBodyScanningShallow {
+			errs = append(errs, fmt.Errorf("invalid body scanning inspection mode %q, must be one of %s, %s", bs.InspectionMode, TeamsBodyScanningDeep, TeamsBodyScanningShallow))
+		}
+	}
+
+	if im := settings.InspectionMode; im != nil {
+		switch *im {
+		case TeamsInspectionNone, TeamsInspectionStandard, TeamsInspectionDeep:
+		default:
+			errs = append(errs, fmt.Errorf("invalid inspection mode %q, must be one of %s, %s, %s", *im, TeamsInspectionNone, TeamsInspectionStandard, TeamsInspectionDeep))
+		}
+	}
+
+	if uc := settings.UntrustedCertSettings; uc != nil && uc.Action != "" {
+		switch uc.Action {
+		case TeamsUntrustedCertPassThrough, TeamsUntrustedCertBlock, TeamsUntrustedCertError:
+		default:
+			errs = append(errs, fmt.Errorf("invalid untrusted certificate action %q, must be one of %s, %s, %s", uc.Action, TeamsUntrustedCertPassThrough, TeamsUntrustedCertBlock, TeamsUntrustedCertError))
+		}
+	}
+
+	if sb := settings.Sandbox; sb != nil && sb.FallbackAction != "" {
+		if sb.FallbackAction != TeamsSandboxFallbackAllow && sb.FallbackAction != TeamsSandboxFallbackBlock {
+			errs = append(errs, fmt.Errorf("invalid sandbox fallback action %q, must be one of %s, %s", sb.FallbackAction, TeamsSandboxFallbackAllow, TeamsSandboxFallbackBlock))
+		}
+	}
+
+	if cc := settings.CustomCertificate; cc != nil && cc.Enabled && cc.ID == "" {
+		errs = append(errs, errors.New("custom certificate must set an ID when enabled"))
+	}
+
+	return errs
+}
+
+// validateTeamsConfiguration checks the settings that the API would otherwise
+// reject with an unhelpful error.
+func validateTeamsConfiguration(config TeamsConfiguration) error {
+	if errs := ValidateTeamsConfiguration(config); len(errs) > 0 {
+		return errs[0]
+	}
+
+	return nil
+}
+
+// ErrTeamsAccountNotProvisioned is returned when an account has no Gateway
+// provisioned. Use errors.Is to check for it, the API error is kept.
+var ErrTeamsAccountNotProvisioned = errors.New("no gateway provisioned for account")
+
+// TeamsAccountNotProvisionedErrorCode is the internal error code the API
+// returns along with a 404 when an account has no Gateway provisioned.
+const TeamsAccountNotProvisionedErrorCode = 2005
+
+// teamsAccountNotProvisionedError is ErrTeamsAccountNotProvisioned along with
+// the API error reporting it.
+type teamsAccountNotProvisionedError struct {
+	err error
+}
+
+func (e *teamsAccountNotProvisionedError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrTeamsAccountNotProvisioned, e.err)
+}
+
+func (e *teamsAccountNotProvisionedError) Is(target error) bool {
+	return target == ErrTeamsAccountNotProvisioned
+}
+
+func (e *teamsAccountNotProvisionedError) Unwrap() error {
+	return e.err
+}
+
+var teamsDoHSubdomainRegexp = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// TeamsGatewayDoHURL returns the DNS over HTTPS URL that clients use to reach
+// a location, given the location's DoH subdomain (TeamsLocation.Subdomain).
+// The URL itself only depends on the subdomain; the account is looked up to
+// fail early when Gateway isn't provisioned.
+func (api *API) TeamsGatewayDoHURL(ctx context.Context, accountID, locationSubdomain string) (string, error) {
+	if !teamsDoHSubdomainRegexp.MatchString(locationSubdomain) {
+		return "", fmt.Errorf("invalid location DoH subdomain %q", locationSubdomain)
+	}
+
+	account, err := api.TeamsAccount(ctx, accountID)
+	if err != nil {
+		return "", err
+	}
+
+	if account.GatewayTag == "" {
+		return "", ErrTeamsAccountNotProvisioned
+	}
+
+	return fmt.Sprintf("https://%s.cloudflare-gateway.com/dns-query", locationSubdomain), nil
+}
+
 // TeamsAccount returns teams account information with internal and external ID.
 //
+// When the API reports that the account has no Gateway provisioned, with a
+// 404 carrying TeamsAccountNotProvisionedErrorCode, the error matches
+// ErrTeamsAccountNotProvisioned, unlike other not found, authentication and
+// authorization failures; use TeamsProvisionAccount to provision it.
+//
 // API reference: TBA.
 func (api *API) TeamsAccount(ctx context.Context, accountID string) (TeamsAccount, error) {
 	uri := fmt.Sprintf("/accounts/%s/gateway", accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
+		// a 404 is also returned for accounts that don't exist, only the
+		// error code tells that Gateway isn't provisioned
+		var notFoundErr *NotFoundError
+		if errors.As(err, &notFoundErr) {
+			for _, code := range notFoundErr.ErrorCodes() {
+				if code == TeamsAccountNotProvisionedErrorCode {
+					return TeamsAccount{}, &teamsAccountNotProvisionedError{err: err}
+				}
+			}
+		}
+		return TeamsAccount{}, err
+	}
+
+	var teamsAccountResponse TeamsAccountResponse
+	err = json.Unmarshal(res, &teamsAccountResponse)
+	if err != nil {
+		return TeamsAccount{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsAccountResponse.Result, nil
+}
+
+// TeamsAccountsBatch fetches the teams accounts of accountIDs, fetching at
+// most concurrency accounts at a time. Every account ID is a key of exactly
+// one of the returned maps: accounts holds those fetched and errs those that
+// failed, including with the context error for accounts not fetched because
+// ctx was cancelled. Requests remain subject to the client's rate limit.
+func (api *API) TeamsAccountsBatch(ctx context.Context, accountIDs []string, concurrency int) (map[string]TeamsAccount, map[string]error) {
+	fetched := make([]TeamsAccount, len(accountIDs))
+	results := forEachTeamsAccount(ctx, accountIDs, concurrency, func(i int, accountID string) error {
+		account, err := api.TeamsAccount(ctx, accountID)
+		fetched[i] = account
+		return err
+	})
+
+	accounts := make(map[string]TeamsAccount, len(accountIDs))
+	errs := make(map[string]error)
+	for i, accountID := range accountIDs {
+		if results[i] != nil {
+			errs[accountID] = results[i]
+			continue
+		}
+		accounts[accountID] = fetched[i]
+	}
+
+	return accounts, errs
+}
+
+// forEachTeamsAccount calls fn for every account of accountIDs, running at
+// most concurrency calls at a time, and returns the error of each account in
+// the order of accountIDs. Accounts not reached because ctx was cancelled
+// hold the context error. fn is called concurrently, so it must only write
+// to the state of index i.
+func forEachTeamsAccount(ctx context.Context, accountIDs []string, concurrency int, fn func(i int, accountID string) error) []error {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	var (
+		wg   sync.WaitGroup
+		sem  = make(chan struct{}, concurrency)
+		errs = make([]error, len(accountIDs))
+	)
+
+	for i, accountID := range accountIDs {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			errs[i] = ctx.Err()
+			continue
+		}
+
+		wg.Add(1)
+		go func(i int, accountID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			if err := ctx.Err(); err != nil {
+				errs[i] = err
+				return
+			}
+
+			errs[i] = fn(i, accountID)
+		}(i, accountID)
+	}
+
+	wg.Wait()
+
+	return errs
+}
+
+// TeamsProvisionAccount provisions Gateway on an account and returns the new
+// teams account with its GatewayTag. It is idempotent: an account that
+// already has Gateway provisioned is returned unchanged.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-accounts-create-zero-trust-account
+func (api *API) TeamsProvisionAccount(ctx context.Context, accountID string) (TeamsAccount, error) {
+	account, err := api.TeamsAccount(ctx, accountID)
+	if err == nil && account.GatewayTag != "" {
+		return account, nil
+	}
+	if err != nil && !errors.Is(err, ErrTeamsAccountNotProvisioned) {
+		return TeamsAccount{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/gateway", accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, nil)
+	if err != nil {
+		// another client may have provisioned the account in the meantime
+		if account, getErr := api.TeamsAccount(ctx, accountID); getErr == nil && account.GatewayTag != "" {
+			return account, nil
+		}
 		return TeamsAccount{}, err
 	}
 
@@ -130,6 +992,26 @@ func (api *API) TeamsAccount(ctx context.Context, accountID string) (TeamsAccoun
 	return teamsAccountResponse.Result, nil
 }
 
+// TeamsAccountIdentityProviders returns the identity providers configured for
+// Access and Gateway on an account, such as the ones identity based Gateway
+// rules match on. Secrets are removed from the provider configuration so the
+// result only holds the non-secret fields.
+//
+// API reference: https://api.cloudflare.com/#access-identity-providers-list-access-identity-providers
+func (api *API) TeamsAccountIdentityProviders(ctx context.Context, accountID string) ([]AccessIdentityProvider, error) {
+	providers, err := api.AccessIdentityProviders(ctx, accountID)
+	if err != nil {
+		return []AccessIdentityProvider{}, err
+	}
+
+	for i := range providers {
+		providers[i].Config.ClientSecret = ""
+		providers[i].Config.APIToken = ""
+	}
+
+	return providers, nil
+}
+
 // TeamsAccountConfiguration returns teams account configuration.
 //
 // API reference: TBA.
@@ -150,6 +1032,104 @@ func (api *API) TeamsAccountConfiguration(ctx context.Context, accountID string)
 	return teamsConfigResponse.Result, nil
 }
 
+// TeamsAccountConfigurationParams selects the settings returned by
+// TeamsAccountConfigurationWithParams.
+type TeamsAccountConfigurationParams struct {
+	// Settings lists the JSON names of the settings to return, such as
+	// "antivirus" or "fips". Every setting is returned when it is empty.
+	Settings []string
+}
+
+// TeamsAccountConfigurationWithParams returns the teams account
+// configuration with only the settings selected by params. The API has no
+// field selection, so the whole configuration is still fetched and the
+// other settings are dropped from the result; this trims what callers keep
+// and compare, not the response payload.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountConfigurationWithParams(ctx context.Context, accountID string, params TeamsAccountConfigurationParams) (TeamsConfiguration, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsConfiguration{}, err
+	}
+
+	if len(params.Settings) > 0 {
+		config.Settings = config.Settings.Select(params.Settings...)
+	}
+
+	return config, nil
+}
+
+// Select returns a copy of s holding only the settings with the JSON names,
+// as accepted by Setting.
+func (s TeamsAccountSettings) Select(names ...string) TeamsAccountSettings {
+	var selected TeamsAccountSettings
+
+	src := reflect.ValueOf(s)
+	dst := reflect.ValueOf(&selected).Elem()
+	for i := 0; i < src.NumField(); i++ {
+		name := strings.Split(src.Type().Field(i).Tag.Get("json"), ",")[0]
+		if contains(names, name) {
+			dst.Field(i).Set(src.Field(i))
+		}
+	}
+
+	for name, value := range s.Extra {
+		if !contains(names, name) {
+			continue
+		}
+		if selected.Extra == nil {
+			selected.Extra = make(map[string]json.RawMessage)
+		}
+		selected.Extra[name] = value
+	}
+
+	return selected
+}
+
+// TeamsAccountSetting returns a single setting of a teams account
+// configuration by its JSON name, such as "antivirus". Modelled settings are
+// returned as their typed value, e.g. *TeamsAntivirus, and others as the
+// json.RawMessage held in Extra. The result is nil when the setting isn't set.
+//
+// The API has no endpoint for a single setting, so the whole configuration is
+// fetched and the setting is extracted from it.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountSetting(ctx context.Context, accountID, name string) (interface{}, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Settings.Setting(name)
+}
+
+// Setting returns the setting with the JSON name, as described for
+// TeamsAccountSetting.
+func (s TeamsAccountSettings) Setting(name string) (interface{}, error) {
+	if name == "" {
+		return nil, errors.New("setting name cannot be empty")
+	}
+
+	v := reflect.ValueOf(s)
+	for i := 0; i < v.NumField(); i++ {
+		if strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0] != name {
+			continue
+		}
+		if field := v.Field(i); field.Kind() != reflect.Ptr || !field.IsNil() {
+			return field.Interface(), nil
+		}
+		return nil, nil
+	}
+
+	if value, ok := s.Extra[name]; ok {
+		return value, nil
+	}
+
+	return nil, nil
+}
+
 // TeamsAccountDeviceConfiguration returns teams account device configuration with udp status.
 //
 // API reference: TBA.
@@ -194,6 +1174,10 @@ func (api *API) TeamsAccountLoggingConfiguration(ctx context.Context, accountID
 //
 // API reference: TBA.
 func (api *API) TeamsAccountUpdateConfiguration(ctx context.Context, accountID string, config TeamsConfiguration) (TeamsConfiguration, error) {
+	if err := validateTeamsConfiguration(config); err != nil {
+		return TeamsConfiguration{}, err
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/configuration", accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, config)
@@ -210,10 +1194,281 @@ func (api *API) TeamsAccountUpdateConfiguration(ctx context.Context, accountID s
 	return teamsConfigResponse.Result, nil
 }
 
+// CopyTeamsConfiguration applies the configuration of srcAccountID to
+// dstAccountID and returns the resulting destination configuration. The
+// timestamps and the custom certificate, which belongs to the source
+// account, aren't copied.
+func (api *API) CopyTeamsConfiguration(ctx context.Context, srcAccountID, dstAccountID string) (TeamsConfiguration, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, srcAccountID)
+	if err != nil {
+		return TeamsConfiguration{}, fmt.Errorf("error reading source configuration: %w", err)
+	}
+
+	config.CreatedAt, config.UpdatedAt = time.Time{}, time.Time{}
+	config.Settings.CustomCertificate = nil
+
+	return api.TeamsAccountUpdateConfiguration(ctx, dstAccountID, config)
+}
+
+// ErrConfigConflict is returned by TeamsAccountUpdateConfigurationIfUnmodified
+// when the configuration was changed since it was read.
+var ErrConfigConflict = errors.New("teams configuration was modified since it was read")
+
+// TeamsAccountUpdateConfigurationIfUnmodified updates the configuration only
+// if it hasn't changed since config was read, as reported by its UpdatedAt.
+// The API has no conditional update, so the current configuration is read
+// and compared first; an update made between that read and the write is
+// still overwritten, but the window is much smaller than for a plain update.
+func (api *API) TeamsAccountUpdateConfigurationIfUnmodified(ctx context.Context, accountID string, config TeamsConfiguration) (TeamsConfiguration, error) {
+	if config.UpdatedAt.IsZero() {
+		return TeamsConfiguration{}, errors.New("teams configuration must have UpdatedAt set to be updated conditionally")
+	}
+
+	current, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsConfiguration{}, err
+	}
+
+	if !current.UpdatedAt.Equal(config.UpdatedAt) {
+		return TeamsConfiguration{}, ErrConfigConflict
+	}
+
+	return api.TeamsAccountUpdateConfiguration(ctx, accountID, config)
+}
+
+// TeamsAccountConfigurationDrift reports whether the live configuration of
+// the account is in sync with desired, returning true when nothing differs,
+// along with the changes that applying desired would make. Old holds the
+// live value and New the desired one. Only settings are compared, and
+// read-only fields such as the timestamps and the binding status of the
+// custom certificate are ignored. Settings unset in desired count as drift
+// when they are set on the account since updating the configuration would
+// clear them.
+func (api *API) TeamsAccountConfigurationDrift(ctx context.Context, accountID string, desired TeamsConfiguration) (bool, []TeamsSettingChange, error) {
+	live, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return false, nil, err
+	}
+
+	changes := withoutTeamsReadOnlySettings(live).Diff(withoutTeamsReadOnlySettings(desired))
+	return len(changes) == 0, changes, nil
+}
+
+// withoutTeamsReadOnlySettings returns a copy of config without the setting
+// fields managed by the API.
+func withoutTeamsReadOnlySettings(config TeamsConfiguration) TeamsConfiguration {
+	if cc := config.Settings.CustomCertificate; cc != nil {
+		certificate := *cc
+		certificate.BindingStatus, certificate.UpdatedAt = "", nil
+		config.Settings.CustomCertificate = &certificate
+	}
+
+	return config
+}
+
+// TeamsConfigurationResult is the outcome of updating the configuration of a
+// single account with TeamsAccountUpdateConfigurationBulk.
+type TeamsConfigurationResult struct {
+	Configuration TeamsConfiguration
+	Err           error
+}
+
+// TeamsAccountUpdateConfigurationBulk applies the same configuration to many
+// accounts, updating at most concurrency accounts at a time. The returned map
+// holds a result for every account; accounts that weren't updated because ctx
+// was cancelled hold the context error. The configuration is validated once
+// up front and an invalid one is returned as an error without any request.
+func (api *API) TeamsAccountUpdateConfigurationBulk(ctx context.Context, accountIDs []string, config TeamsConfiguration, concurrency int) (map[string]TeamsConfigurationResult, error) {
+	if err := validateTeamsConfiguration(config); err != nil {
+		return nil, err
+	}
+
+	configurations := make([]TeamsConfiguration, len(accountIDs))
+	errs := forEachTeamsAccount(ctx, accountIDs, concurrency, func(i int, accountID string) error {
+		configuration, err := api.TeamsAccountUpdateConfiguration(ctx, accountID, config)
+		configurations[i] = configuration
+		return err
+	})
+
+	results := make(map[string]TeamsConfigurationResult, len(accountIDs))
+	for i, accountID := range accountIDs {
+		results[accountID] = TeamsConfigurationResult{Configuration: configurations[i], Err: errs[i]}
+	}
+
+	return results, nil
+}
+
+// TeamsAccountSettingsPatch is a partial update of the settings of a teams
+// account configuration, see TeamsAccountPatchConfiguration. It mirrors
+// TeamsAccountSettings, but the booleans of the sub-settings are pointers
+// so that a false value can be told apart from a field left unset.
+type TeamsAccountSettingsPatch struct {
+	Antivirus             *TeamsAntivirusPatch         `json:"antivirus,omitempty"`
+	TLSDecrypt            *TeamsSettingTogglePatch     `json:"tls_decrypt,omitempty"`
+	ActivityLog           *TeamsSettingTogglePatch     `json:"activity_log,omitempty"`
+	BlockPage             *TeamsBlockPage              `json:"block_page,omitempty"`
+	BrowserIsolation      *BrowserIsolationPatch       `json:"browser_isolation,omitempty"`
+	FIPS                  *TeamsFIPSPatch              `json:"fips,omitempty"`
+	ProtocolDetection     *TeamsSettingTogglePatch     `json:"protocol_detection,omitempty"`
+	BodyScanning          *TeamsBodyScanning           `json:"body_scanning,omitempty"`
+	ExtendedEmailMatching *TeamsSettingTogglePatch     `json:"extended_email_matching,omitempty"`
+	CustomCertificate     *TeamsCustomCertificatePatch `json:"custom_certificate,omitempty"`
+	UntrustedCertSettings *TeamsUntrustedCertSettings  `json:"untrusted_cert,omitempty"`
+	Sandbox               *TeamsSandboxPatch           `json:"sandbox,omitempty"`
+	HostSelector          *TeamsSettingTogglePatch     `json:"host_selector,omitempty"`
+	EmailLinkIsolation    *TeamsSettingTogglePatch     `json:"email_link_isolation,omitempty"`
+	InspectionMode        *TeamsInspectionMode         `json:"inspection_mode,omitempty"`
+}
+
+// TeamsSettingTogglePatch patches a sub-setting made of an enabled flag,
+// such as TeamsTLSDecrypt, TeamsActivityLog or TeamsEmailLinkIsolation.
+type TeamsSettingTogglePatch struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// TeamsAntivirusPatch patches TeamsAntivirus.
+type TeamsAntivirusPatch struct {
+	EnabledDownloadPhase *bool                      `json:"enabled_download_phase,omitempty"`
+	EnabledUploadPhase   *bool                      `json:"enabled_upload_phase,omitempty"`
+	FailClosed           *bool                      `json:"fail_closed,omitempty"`
+	NotificationSettings *TeamsNotificationSettings `json:"notification_settings,omitempty"`
+}
+
+// BrowserIsolationPatch patches BrowserIsolation.
+type BrowserIsolationPatch struct {
+	UrlBrowserIsolationEnabled *bool `json:"url_browser_isolation_enabled,omitempty"`
+	NonIdentityEnabled         *bool `json:"non_identity_enabled,omitempty"`
+}
+
+// TeamsFIPSPatch patches TeamsFIPS.
+type TeamsFIPSPatch struct {
+	TLS *bool `json:"tls,omitempty"`
+}
+
+// TeamsCustomCertificatePatch patches TeamsCustomCertificate.
+type TeamsCustomCertificatePatch struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	ID      string `json:"id,omitempty"`
+}
+
+// TeamsSandboxPatch patches TeamsSandbox.
+type TeamsSandboxPatch struct {
+	Enabled        *bool                      `json:"enabled,omitempty"`
+	FallbackAction TeamsSandboxFallbackAction `json:"fallback_action,omitempty"`
+}
+
+// TeamsAccountPatchConfiguration updates only the fields of a teams account
+// configuration that are set in patch, leaving the others unchanged. Unset
+// sub-settings and unset fields within a sub-setting aren't sent. An error
+// is returned without sending anything when patch, or one of its
+// sub-settings, sets no field.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountPatchConfiguration(ctx context.Context, accountID string, patch TeamsAccountSettingsPatch) (TeamsConfiguration, error) {
+	data, err := json.Marshal(patch)
+	if err != nil {
+		return TeamsConfiguration{}, fmt.Errorf("error marshalling teams account configuration patch: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return TeamsConfiguration{}, fmt.Errorf("error marshalling teams account configuration patch: %w", err)
+	}
+	if len(fields) == 0 {
+		return TeamsConfiguration{}, errors.New("teams account configuration patch must set at least one setting")
+	}
+	for name, value := range fields {
+		if string(value) == "{}" {
+			return TeamsConfiguration{}, fmt.Errorf("teams account configuration patch of %s must set at least one field", name)
+		}
+	}
+
+	// the fields left unset decode to their zero value, which the
+	// validation of the settings accepts
+	var settings TeamsAccountSettings
+	if err := json.Unmarshal(data, &settings); err != nil {
+		return TeamsConfiguration{}, fmt.Errorf("error marshalling teams account configuration patch: %w", err)
+	}
+	if err := validateTeamsConfiguration(TeamsConfiguration{Settings: settings}); err != nil {
+		return TeamsConfiguration{}, err
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/gateway/configuration", accountID)
+
+	body := struct {
+		Settings TeamsAccountSettingsPatch `json:"settings"`
+	}{patch}
+
+	res, err := api.makeRequestContext(ctx, http.MethodPatch, uri, body)
+	if err != nil {
+		return TeamsConfiguration{}, err
+	}
+
+	var teamsConfigResponse TeamsConfigResponse
+	err = json.Unmarshal(res, &teamsConfigResponse)
+	if err != nil {
+		return TeamsConfiguration{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsConfigResponse.Result, nil
+}
+
+// TeamsLoggingOption is a functional option for updating the logging
+// configuration.
+type TeamsLoggingOption func(opt *teamsLoggingOption)
+
+type teamsLoggingOption struct {
+	fillMissingRuleTypes bool
+}
+
+// WithAllTeamsRuleTypes sends every rule type, turning logging off for those
+// missing from the settings, so that a partial map states the intended
+// settings for the omitted types explicitly.
+func WithAllTeamsRuleTypes() TeamsLoggingOption {
+	return func(opt *teamsLoggingOption) {
+		opt.fillMissingRuleTypes = true
+	}
+}
+
+// validateTeamsLoggingSettings returns an error for rule types the API
+// doesn't know about, which it would otherwise silently ignore.
+func validateTeamsLoggingSettings(config TeamsLoggingSettings) error {
+	var unknown []string
+	for ruleType := range config.LoggingSettingsByRuleType {
+		if !contains(teamsRuleTypes, ruleType) {
+			unknown = append(unknown, ruleType)
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("invalid logging rule type %q, must be one of %s", unknown[0], strings.Join(teamsRuleTypes, ", "))
+	}
+
+	return nil
+}
+
 // TeamsAccountUpdateLoggingConfiguration updates the log settings and returns new teams account logging configuration.
 //
 // API reference: TBA.
-func (api *API) TeamsAccountUpdateLoggingConfiguration(ctx context.Context, accountID string, config TeamsLoggingSettings) (TeamsLoggingSettings, error) {
+func (api *API) TeamsAccountUpdateLoggingConfiguration(ctx context.Context, accountID string, config TeamsLoggingSettings, opts ...TeamsLoggingOption) (TeamsLoggingSettings, error) {
+	opt := teamsLoggingOption{}
+	for _, of := range opts {
+		of(&opt)
+	}
+
+	if err := validateTeamsLoggingSettings(config); err != nil {
+		return TeamsLoggingSettings{}, err
+	}
+
+	if opt.fillMissingRuleTypes {
+		settings := make(map[TeamsRuleType]TeamsAccountLoggingConfiguration, len(teamsRuleTypes))
+		for _, ruleType := range teamsRuleTypes {
+			settings[ruleType] = config.LoggingSettingsByRuleType[ruleType]
+		}
+		config.LoggingSettingsByRuleType = settings
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/logging", accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, config)
@@ -230,6 +1485,14 @@ func (api *API) TeamsAccountUpdateLoggingConfiguration(ctx context.Context, acco
 	return teamsConfigResponse.Result, nil
 }
 
+// TeamsAccountResetLoggingConfiguration restores the teams account logging
+// configuration to the values returned by TeamsAccountLoggingDefaults.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountResetLoggingConfiguration(ctx context.Context, accountID string) (TeamsLoggingSettings, error) {
+	return api.TeamsAccountUpdateLoggingConfiguration(ctx, accountID, TeamsAccountLoggingDefaults())
+}
+
 // TeamsAccountDeviceUpdateConfiguration updates teams account device configuration including udp filtering status.
 //
 // API reference: TBA.
@@ -249,3 +1512,27 @@ func (api *API) TeamsAccountDeviceUpdateConfiguration(ctx context.Context, accou
 
 	return teamsDeviceResponse.Result, nil
 }
+
+// TeamsAccountDeviceUpdateConfigurationWithParams updates only the teams
+// account device settings that are set in params. The current settings are
+// read first so that unset fields are sent unchanged.
+//
+// API reference: TBA.
+func (api *API) TeamsAccountDeviceUpdateConfigurationWithParams(ctx context.Context, accountID string, params TeamsDeviceSettingsUpdateParams) (TeamsDeviceSettings, error) {
+	settings, err := api.TeamsAccountDeviceConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsDeviceSettings{}, err
+	}
+
+	if params.GatewayProxyEnabled != nil {
+		settings.GatewayProxyEnabled = *params.GatewayProxyEnabled
+	}
+	if params.GatewayProxyUDPEnabled != nil {
+		settings.GatewayProxyUDPEnabled = *params.GatewayProxyUDPEnabled
+	}
+	if params.RootCertificateInstallationEnabled != nil {
+		settings.RootCertificateInstallationEnabled = params.RootCertificateInstallationEnabled
+	}
+
+	return api.TeamsAccountDeviceUpdateConfiguration(ctx, accountID, settings)
+}
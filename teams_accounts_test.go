@@ -2,9 +2,15 @@ package cloudflare
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -40,6 +46,139 @@ func TestTeamsAccount(t *testing.T) {
 	}
 }
 
+func TestTeamsAccountNotProvisioned(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.URL.Path {
+		case "/accounts/" + testAccountID + "/gateway":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{
+				"success": false,
+				"errors": [{"code": 2005, "message": "gateway not provisioned"}],
+				"messages": [],
+				"result": null
+			}`)
+		case "/accounts/missing/gateway":
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{
+				"success": false,
+				"errors": [{"code": 7003, "message": "Not found"}],
+				"messages": [],
+				"result": null
+			}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{
+				"success": false,
+				"errors": [{"code": 10000, "message": "Authentication error"}],
+				"messages": [],
+				"result": null
+			}`)
+		}
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway", handler)
+	mux.HandleFunc("/accounts/missing/gateway", handler)
+	mux.HandleFunc("/accounts/other/gateway", handler)
+
+	_, err := client.TeamsAccount(context.Background(), testAccountID)
+	assert.True(t, errors.Is(err, ErrTeamsAccountNotProvisioned))
+	var notFoundErr *NotFoundError
+	assert.True(t, errors.As(err, &notFoundErr))
+
+	_, err = client.TeamsAccount(context.Background(), "missing")
+	assert.True(t, errors.As(err, &notFoundErr))
+	assert.False(t, errors.Is(err, ErrTeamsAccountNotProvisioned))
+
+	_, err = client.TeamsAccount(context.Background(), "other")
+	var authenticationErr *AuthenticationError
+	assert.True(t, errors.As(err, &authenticationErr))
+	assert.False(t, errors.Is(err, ErrTeamsAccountNotProvisioned))
+}
+
+func TestTeamsProvisionAccount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	provisioned := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch {
+		case r.Method == http.MethodPost:
+			assert.False(t, provisioned, "account provisioned twice")
+			provisioned = true
+		case !provisioned:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 2005, "message": "gateway not provisioned"}], "messages": [], "result": null}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "provider_name": "cf", "gateway_tag": "1234"}
+		}`, testAccountID)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway", handler)
+
+	want := TeamsAccount{ID: testAccountID, ProviderName: "cf", GatewayTag: "1234"}
+
+	actual, err := client.TeamsProvisionAccount(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	// provisioning again returns the existing account
+	actual, err = client.TeamsProvisionAccount(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsAccountIdentityProviders(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+					"name": "Widget Corps OTP",
+					"type": "github",
+					"config": {
+						"client_id": "example_id",
+						"client_secret": "a-secret-key"
+					}
+				}
+			]
+		}
+		`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/identity_providers", handler)
+
+	actual, err := client.TeamsAccountIdentityProviders(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []AccessIdentityProvider{{
+			ID:     "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+			Name:   "Widget Corps OTP",
+			Type:   "github",
+			Config: AccessIdentityProviderConfiguration{ClientID: "example_id"},
+		}}, actual)
+	}
+}
+
 func TestTeamsAccountConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
@@ -62,6 +201,12 @@ func TestTeamsAccountConfiguration(t *testing.T) {
 					"fips": {
 						"tls": true
 					},
+					"protocol_detection": {
+						"enabled": true
+					},
+					"body_scanning": {
+						"inspection_mode": "deep"
+					},
 					"activity_log": {
 						"enabled": true
 					},
@@ -92,6 +237,9 @@ func TestTeamsAccountConfiguration(t *testing.T) {
 			TLSDecrypt:  &TeamsTLSDecrypt{Enabled: true},
 			FIPS:        &TeamsFIPS{TLS: true},
 
+			ProtocolDetection: &TeamsProtocolDetection{Enabled: true},
+			BodyScanning:      &TeamsBodyScanning{InspectionMode: TeamsBodyScanningDeep},
+
 			BlockPage: &TeamsBlockPage{
 				Enabled:         BoolPtr(true),
 				FooterText:      "--footer--",
@@ -106,6 +254,78 @@ func TestTeamsAccountConfiguration(t *testing.T) {
 	}
 }
 
+func TestTeamsAccountConfigurationEmptyTimestamps(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {"activity_log": {"enabled": true}},
+				"created_at": "",
+				"updated_at": null
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountConfiguration(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsConfiguration{
+			Settings: TeamsAccountSettings{ActivityLog: &TeamsActivityLog{Enabled: true}},
+		}, actual)
+	}
+
+	var config TeamsConfiguration
+	err = json.Unmarshal([]byte(`{"settings": {}, "created_at": "2022-06-14T05:20:00.12345Z", "updated_at": "2022-06-15T05:20:00Z"}`), &config)
+	if assert.NoError(t, err) {
+		assert.Equal(t, time.Date(2022, 6, 14, 5, 20, 0, 123450000, time.UTC), config.CreatedAt)
+		assert.Equal(t, time.Date(2022, 6, 15, 5, 20, 0, 0, time.UTC), config.UpdatedAt)
+	}
+
+	err = json.Unmarshal([]byte(`{"settings": {}, "created_at": "yesterday"}`), &config)
+	assert.Error(t, err)
+}
+
+func TestTeamsAccountConfigurationAntivirusFailClosed(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"antivirus": {
+						"enabled_download_phase": true,
+						"fail_closed": true
+					}
+				}
+			}
+		}
+		`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountConfiguration(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsAntivirus{EnabledDownloadPhase: true, FailClosed: true}, actual.Settings.Antivirus)
+	}
+}
+
 func TestTeamsAccountUpdateConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
@@ -152,127 +372,502 @@ func TestTeamsAccountUpdateConfiguration(t *testing.T) {
 	}
 }
 
-func TestTeamsAccountGetLoggingConfiguration(t *testing.T) {
+func TestTeamsAccountUpdateProtocolDetectionConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings": {
+					"protocol_detection": {
+						"enabled": true
+					}
+				},
+				"created_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z"
+			}`, string(b), "JSON payload not equal")
+		}
+
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
 			"errors": [],
 			"messages": [],
-			"result": {"settings_by_rule_type":{"dns":{"log_all":false,"log_blocks":true}},"redact_pii":true}
-		}`)
+			"result": {
+				"settings": {
+					"protocol_detection": {
+						"enabled": true
+					}
+				}
+			}
+		}
+		`)
 	}
 
-	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
 
-	actual, err := client.TeamsAccountLoggingConfiguration(context.Background(), testAccountID)
+	configuration := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			ProtocolDetection: &TeamsProtocolDetection{Enabled: true},
+		},
+	}
+	actual, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, configuration)
 
 	if assert.NoError(t, err) {
-		assert.Equal(t, actual, TeamsLoggingSettings{
-			RedactPii: true,
-			LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
-				TeamsDnsRuleType: {LogAll: false, LogBlocks: true},
-			},
-		})
+		assert.Equal(t, actual, configuration)
 	}
 }
 
-func TestTeamsAccountUpdateLoggingConfiguration(t *testing.T) {
+func TestTeamsAccountUpdateBrowserIsolationConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings": {
+					"browser_isolation": {
+						"url_browser_isolation_enabled": true,
+						"non_identity_enabled": true
+					}
+				},
+				"created_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z"
+			}`, string(b), "JSON payload not equal")
+		}
+
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
 			"errors": [],
 			"messages": [],
-			"result": {"settings_by_rule_type":{"dns":{"log_all":false,"log_blocks":true}, "http":{"log_all":true,"log_blocks":false}, "l4": {"log_all": false, "log_blocks": true}},"redact_pii":true}
-		}`)
+			"result": {
+				"settings": {
+					"browser_isolation": {
+						"url_browser_isolation_enabled": true,
+						"non_identity_enabled": true
+					}
+				}
+			}
+		}
+		`)
 	}
 
-	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
 
-	actual, err := client.TeamsAccountUpdateLoggingConfiguration(context.Background(), testAccountID, TeamsLoggingSettings{
-		RedactPii: true,
-		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
-			TeamsDnsRuleType: {
-				LogAll:    false,
-				LogBlocks: true,
-			},
-			TeamsHttpRuleType: {
-				LogAll: true,
-			},
-			TeamsL4RuleType: {
-				LogBlocks: true,
+	configuration := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			BrowserIsolation: &BrowserIsolation{
+				UrlBrowserIsolationEnabled: true,
+				NonIdentityEnabled:         BoolPtr(true),
 			},
 		},
-	})
+	}
+	actual, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, configuration)
 
 	if assert.NoError(t, err) {
-		assert.Equal(t, actual, TeamsLoggingSettings{
-			RedactPii: true,
-			LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
-				TeamsDnsRuleType:  {LogAll: false, LogBlocks: true},
-				TeamsHttpRuleType: {LogAll: true, LogBlocks: false},
-				TeamsL4RuleType:   {LogAll: false, LogBlocks: true},
-			},
-		})
+		assert.Equal(t, actual, configuration)
 	}
 }
 
-func TestTeamsAccountGetDeviceConfiguration(t *testing.T) {
+func TestTeamsAccountCustomCertificateConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		if r.Method == http.MethodPut {
+			b, err := ioutil.ReadAll(r.Body)
+			defer r.Body.Close()
+
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, `{
+					"settings": {
+						"custom_certificate": {
+							"enabled": true,
+							"id": "d1b364c5-1311-466e-a194-f0e943e0799f",
+							"binding_status": "active",
+							"updated_at": "2022-10-01T12:00:00Z"
+						}
+					},
+					"created_at": "0001-01-01T00:00:00Z",
+					"updated_at": "0001-01-01T00:00:00Z"
+				}`, string(b), "JSON payload not equal")
+			}
+		}
+
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
 			"errors": [],
 			"messages": [],
-			"result": {"gateway_proxy_enabled": true,"gateway_udp_proxy_enabled":false}
-		}`)
+			"result": {
+				"settings": {
+					"custom_certificate": {
+						"enabled": true,
+						"id": "d1b364c5-1311-466e-a194-f0e943e0799f",
+						"binding_status": "active",
+						"updated_at": "2022-10-01T12:00:00Z"
+					}
+				}
+			}
+		}
+		`)
 	}
 
-	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
 
-	actual, err := client.TeamsAccountDeviceConfiguration(context.Background(), testAccountID)
+	updatedAt := time.Date(2022, 10, 1, 12, 0, 0, 0, time.UTC)
+	want := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			CustomCertificate: &TeamsCustomCertificate{
+				Enabled:       true,
+				ID:            "d1b364c5-1311-466e-a194-f0e943e0799f",
+				BindingStatus: "active",
+				UpdatedAt:     &updatedAt,
+			},
+		},
+	}
 
+	configuration, err := client.TeamsAccountConfiguration(context.Background(), testAccountID)
 	if assert.NoError(t, err) {
-		assert.Equal(t, actual, TeamsDeviceSettings{
-			GatewayProxyEnabled:    true,
-			GatewayProxyUDPEnabled: false,
-		})
+		assert.Equal(t, want, configuration)
+	}
+
+	actual, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, configuration)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
 	}
 }
 
-func TestTeamsAccountUpdateDeviceConfiguration(t *testing.T) {
+func TestTeamsAccountUpdateExtendedEmailMatchingConfiguration(t *testing.T) {
 	setup()
 	defer teardown()
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings": {
+					"antivirus": {
+						"enabled_download_phase": true,
+						"enabled_upload_phase": false,
+						"fail_closed": false
+					},
+					"tls_decrypt": {
+						"enabled": true
+					},
+					"extended_email_matching": {
+						"enabled": true
+					}
+				},
+				"created_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z"
+			}`, string(b), "JSON payload not equal")
+		}
+
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
 			"errors": [],
 			"messages": [],
-			"result": {"gateway_proxy_enabled": true,"gateway_udp_proxy_enabled":true}
-		}`)
+			"result": {
+				"settings": {
+					"antivirus": {
+						"enabled_download_phase": true
+					},
+					"tls_decrypt": {
+						"enabled": true
+					},
+					"extended_email_matching": {
+						"enabled": true
+					}
+				}
+			}
+		}
+		`)
 	}
 
-	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
 
-	actual, err := client.TeamsAccountDeviceUpdateConfiguration(context.Background(), testAccountID, TeamsDeviceSettings{
-		GatewayProxyUDPEnabled: true,
-		GatewayProxyEnabled:    true,
+	configuration := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			Antivirus:             &TeamsAntivirus{EnabledDownloadPhase: true},
+			TLSDecrypt:            &TeamsTLSDecrypt{Enabled: true},
+			ExtendedEmailMatching: &TeamsExtendedEmailMatching{Enabled: true},
+		},
+	}
+	actual, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, configuration)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, actual, configuration)
+	}
+}
+
+func TestTeamsAccountUpdateConfigurationInvalidSupportURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("configuration should not be sent with an invalid support URL")
+	})
+
+	_, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			Antivirus: &TeamsAntivirus{
+				EnabledDownloadPhase: true,
+				NotificationSettings: &TeamsNotificationSettings{
+					Enabled:    BoolPtr(true),
+					SupportURL: "helpdesk.example.com/av",
+				},
+			},
+		},
+	})
+	assert.EqualError(t, err, `invalid notification support URL "helpdesk.example.com/av": must be an absolute http or https URL`)
+}
+
+func TestTeamsAccountGetLoggingConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings_by_rule_type":{"dns":{"log_all":false,"log_blocks":true}},"redact_pii":true}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+
+	actual, err := client.TeamsAccountLoggingConfiguration(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, actual, TeamsLoggingSettings{
+			RedactPii: true,
+			LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+				TeamsDnsRuleType: {LogAll: false, LogBlocks: true},
+			},
+		})
+	}
+}
+
+func TestTeamsAccountUpdateLoggingConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings_by_rule_type":{"dns":{"log_all":false,"log_blocks":true}, "http":{"log_all":true,"log_blocks":false}, "l4": {"log_all": false, "log_blocks": true}},"redact_pii":true}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+
+	actual, err := client.TeamsAccountUpdateLoggingConfiguration(context.Background(), testAccountID, TeamsLoggingSettings{
+		RedactPii: true,
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsDnsRuleType: {
+				LogAll:    false,
+				LogBlocks: true,
+			},
+			TeamsHttpRuleType: {
+				LogAll: true,
+			},
+			TeamsL4RuleType: {
+				LogBlocks: true,
+			},
+		},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, actual, TeamsLoggingSettings{
+			RedactPii: true,
+			LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+				TeamsDnsRuleType:  {LogAll: false, LogBlocks: true},
+				TeamsHttpRuleType: {LogAll: true, LogBlocks: false},
+				TeamsL4RuleType:   {LogAll: false, LogBlocks: true},
+			},
+		})
+	}
+}
+
+func TestTeamsAccountUpdateLoggingConfigurationValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.TeamsAccountUpdateLoggingConfiguration(context.Background(), testAccountID, TeamsLoggingSettings{
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			"htpp":           {LogAll: true},
+			TeamsDnsRuleType: {LogAll: true},
+		},
+	})
+	assert.EqualError(t, err, `invalid logging rule type "htpp", must be one of http, dns, l4`)
+}
+
+func TestTeamsAccountUpdateLoggingConfigurationWithAllRuleTypes(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"settings_by_rule_type":{"dns":{"log_all":true,"log_blocks":true},"http":{"log_all":false,"log_blocks":false},"l4":{"log_all":false,"log_blocks":false}}}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s
+		}`, body)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+
+	settings := TeamsLoggingSettings{
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsDnsRuleType: {LogAll: true, LogBlocks: true},
+		},
+	}
+	actual, err := client.TeamsAccountUpdateLoggingConfiguration(context.Background(), testAccountID, settings, WithAllTeamsRuleTypes())
+
+	if assert.NoError(t, err) {
+		assert.Len(t, actual.LoggingSettingsByRuleType, 3)
+		// the caller's settings aren't modified
+		assert.Len(t, settings.LoggingSettingsByRuleType, 1)
+	}
+}
+
+func TestTeamsLoggingSettingsWithRuleTypeLogging(t *testing.T) {
+	current := DefaultTeamsLoggingSettings()
+	current.RedactPii = true
+
+	settings := current.
+		WithRuleTypeLogging(TeamsDnsRuleType, true, true).
+		WithRuleTypeLogging(TeamsL4RuleType, false, false)
+
+	assert.Equal(t, TeamsLoggingSettings{
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsHttpRuleType: {LogBlocks: true},
+			TeamsDnsRuleType:  {LogAll: true, LogBlocks: true},
+			TeamsL4RuleType:   {},
+		},
+		RedactPii: true,
+	}, settings)
+
+	// the settings the builder started from are unchanged
+	assert.Equal(t, TeamsAccountLoggingConfiguration{LogBlocks: true}, current.LoggingSettingsByRuleType[TeamsDnsRuleType])
+
+	settings = TeamsLoggingSettings{}.WithRuleTypeLogging(TeamsHttpRuleType, true, false)
+	assert.Equal(t, map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+		TeamsHttpRuleType: {LogAll: true},
+	}, settings.LoggingSettingsByRuleType)
+}
+
+func TestTeamsAccountResetLoggingConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		defer r.Body.Close()
+
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings_by_rule_type": {
+					"dns": {"log_all": true, "log_blocks": true},
+					"http": {"log_all": true, "log_blocks": true},
+					"l4": {"log_all": true, "log_blocks": true}
+				}
+			}`, string(b), "JSON payload not equal")
+		}
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings_by_rule_type":{"dns":{"log_all":true,"log_blocks":true}, "http":{"log_all":true,"log_blocks":true}, "l4": {"log_all": true, "log_blocks": true}},"redact_pii":false}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+
+	actual, err := client.TeamsAccountResetLoggingConfiguration(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, actual, TeamsAccountLoggingDefaults())
+	}
+}
+
+func TestTeamsAccountGetDeviceConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"gateway_proxy_enabled": true,"gateway_udp_proxy_enabled":false}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+
+	actual, err := client.TeamsAccountDeviceConfiguration(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, actual, TeamsDeviceSettings{
+			GatewayProxyEnabled:    true,
+			GatewayProxyUDPEnabled: false,
+		})
+	}
+}
+
+func TestTeamsAccountUpdateDeviceConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"gateway_proxy_enabled": true,"gateway_udp_proxy_enabled":true}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+
+	actual, err := client.TeamsAccountDeviceUpdateConfiguration(context.Background(), testAccountID, TeamsDeviceSettings{
+		GatewayProxyUDPEnabled: true,
+		GatewayProxyEnabled:    true,
 	})
 
 	if assert.NoError(t, err) {
@@ -282,3 +877,1001 @@ func TestTeamsAccountUpdateDeviceConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestTeamsAccountUpdateDeviceConfigurationWithParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": {"gateway_proxy_enabled": false,"gateway_udp_proxy_enabled":true}
+			}`)
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(r.Body)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, `{"gateway_proxy_enabled":true,"gateway_udp_proxy_enabled":true}`, string(body))
+			}
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": {"gateway_proxy_enabled": true,"gateway_udp_proxy_enabled":true}
+			}`)
+		default:
+			assert.Failf(t, "unexpected method", "Expected method 'GET' or 'PUT', got %s", r.Method)
+		}
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+
+	actual, err := client.TeamsAccountDeviceUpdateConfigurationWithParams(context.Background(), testAccountID, TeamsDeviceSettingsUpdateParams{
+		GatewayProxyEnabled: BoolPtr(true),
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsDeviceSettings{
+			GatewayProxyEnabled:    true,
+			GatewayProxyUDPEnabled: true,
+		}, actual)
+	}
+}
+
+func TestTeamsAccountUpdateDeviceConfigurationRootCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		switch r.Method {
+		case http.MethodGet:
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": {"gateway_proxy_enabled": true, "gateway_udp_proxy_enabled": true, "root_certificate_installation_enabled": false}
+			}`)
+		case http.MethodPut:
+			body, err := ioutil.ReadAll(r.Body)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, `{"gateway_proxy_enabled":true,"gateway_udp_proxy_enabled":true,"root_certificate_installation_enabled":true}`, string(body))
+			}
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, body)
+		default:
+			assert.Failf(t, "unexpected method", "Expected method 'GET' or 'PUT', got %s", r.Method)
+		}
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/settings", handler)
+
+	actual, err := client.TeamsAccountDeviceUpdateConfigurationWithParams(context.Background(), testAccountID, TeamsDeviceSettingsUpdateParams{
+		RootCertificateInstallationEnabled: BoolPtr(true),
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsDeviceSettings{
+			GatewayProxyEnabled:                true,
+			GatewayProxyUDPEnabled:             true,
+			RootCertificateInstallationEnabled: BoolPtr(true),
+		}, actual)
+	}
+}
+
+func TestTeamsAccountPatchConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"settings":{"tls_decrypt":{"enabled":true},"antivirus":{"fail_closed":false}}}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {
+				"tls_decrypt": {"enabled": true},
+				"antivirus": {"enabled_download_phase": true, "enabled_upload_phase": false, "fail_closed": false},
+				"activity_log": {"enabled": true}
+			}}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{
+		TLSDecrypt: &TeamsSettingTogglePatch{Enabled: BoolPtr(true)},
+		Antivirus:  &TeamsAntivirusPatch{FailClosed: BoolPtr(false)},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsAccountSettings{
+			TLSDecrypt:  &TeamsTLSDecrypt{Enabled: true},
+			Antivirus:   &TeamsAntivirus{EnabledDownloadPhase: true},
+			ActivityLog: &TeamsActivityLog{Enabled: true},
+		}, actual.Settings)
+	}
+
+	_, err = client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{})
+	assert.EqualError(t, err, "teams account configuration patch must set at least one setting")
+
+	_, err = client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{
+		Antivirus: &TeamsAntivirusPatch{},
+	})
+	assert.EqualError(t, err, "teams account configuration patch of antivirus must set at least one field")
+}
+
+func TestTeamsAccountSettingsPreservesUnknownFields(t *testing.T) {
+	input := `{"tls_decrypt":{"enabled":true},"new_setting":{"enabled":true,"mode":"strict"}}`
+
+	var settings TeamsAccountSettings
+	err := json.Unmarshal([]byte(input), &settings)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsTLSDecrypt{Enabled: true}, settings.TLSDecrypt)
+		assert.JSONEq(t, `{"enabled":true,"mode":"strict"}`, string(settings.Extra["new_setting"]))
+	}
+
+	settings.Antivirus = &TeamsAntivirus{EnabledDownloadPhase: true}
+	actual, err := json.Marshal(settings)
+
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"tls_decrypt":{"enabled":true},
+			"antivirus":{"enabled_download_phase":true,"enabled_upload_phase":false,"fail_closed":false},
+			"new_setting":{"enabled":true,"mode":"strict"}
+		}`, string(actual))
+	}
+}
+
+func TestTeamsAntivirusNotificationSettingsMarshal(t *testing.T) {
+	actual, err := json.Marshal(TeamsAntivirus{EnabledDownloadPhase: true})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"enabled_download_phase":true,"enabled_upload_phase":false,"fail_closed":false}`, string(actual))
+	}
+
+	actual, err = json.Marshal(TeamsAntivirus{
+		EnabledDownloadPhase: true,
+		NotificationSettings: &TeamsNotificationSettings{
+			Enabled:    BoolPtr(true),
+			Message:    "File blocked, contact IT",
+			SupportURL: "https://helpdesk.example.com",
+		},
+	})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"enabled_download_phase": true,
+			"enabled_upload_phase": false,
+			"fail_closed": false,
+			"notification_settings": {
+				"enabled": true,
+				"msg": "File blocked, contact IT",
+				"support_url": "https://helpdesk.example.com"
+			}
+		}`, string(actual))
+	}
+}
+
+func TestTeamsAccountUpdateConfigurationInvalidBlockPage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			BlockPage: &TeamsBlockPage{BackgroundColor: "red"},
+		},
+	})
+	assert.EqualError(t, err, `invalid block page background color "red": must be a #RRGGBB hex color`)
+
+	_, err = client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			BlockPage: &TeamsBlockPage{BackgroundColor: "#FF00aa", MailtoAddress: "not an email"},
+		},
+	})
+	assert.ErrorContains(t, err, `invalid block page mailto address "not an email"`)
+}
+
+func TestTeamsBlockPageRedirectMode(t *testing.T) {
+	blockPage := TeamsBlockPage{Mode: TeamsBlockPageRedirect, TargetURI: "https://policy.example.com/blocked", BackgroundColor: "red"}
+	assert.NoError(t, blockPage.Validate())
+
+	b, err := json.Marshal(TeamsBlockPage{Mode: TeamsBlockPageRedirect, TargetURI: "https://policy.example.com/blocked"})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"mode":"redirect_uri","target_uri":"https://policy.example.com/blocked"}`, string(b))
+	}
+
+	blockPage = TeamsBlockPage{Mode: TeamsBlockPageRedirect, TargetURI: "/blocked"}
+	assert.EqualError(t, blockPage.Validate(), `invalid block page target URI "/blocked": must be an absolute http or https URL`)
+
+	blockPage = TeamsBlockPage{Mode: TeamsBlockPageCustomized, BackgroundColor: "red"}
+	assert.EqualError(t, blockPage.Validate(), `invalid block page background color "red": must be a #RRGGBB hex color`)
+
+	blockPage = TeamsBlockPage{Mode: "default"}
+	assert.EqualError(t, blockPage.Validate(), `invalid block page mode "default", must be one of customized_block_page, redirect_uri`)
+}
+
+func TestTeamsAccountPatchUntrustedCertConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"settings":{"untrusted_cert":{"action":"error"}}}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"untrusted_cert": {"action": "error"}}}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{
+		UntrustedCertSettings: &TeamsUntrustedCertSettings{Action: TeamsUntrustedCertError},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsAccountSettings{
+			UntrustedCertSettings: &TeamsUntrustedCertSettings{Action: TeamsUntrustedCertError},
+		}, actual.Settings)
+	}
+
+	b, err := json.Marshal(TeamsAccountSettings{TLSDecrypt: &TeamsTLSDecrypt{Enabled: true}})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"tls_decrypt":{"enabled":true}}`, string(b))
+	}
+}
+
+func TestTeamsGatewayDoHURL(t *testing.T) {
+	setup()
+	defer teardown()
+
+	gatewayTag := "1234"
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "%s",
+				"provider_name": "cf",
+				"gateway_tag": "%s"
+			}
+		}
+		`, testAccountID, gatewayTag)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway", handler)
+
+	actual, err := client.TeamsGatewayDoHURL(context.Background(), testAccountID, "oli3n9zkz5")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://oli3n9zkz5.cloudflare-gateway.com/dns-query", actual)
+	}
+
+	_, err = client.TeamsGatewayDoHURL(context.Background(), testAccountID, "bad.subdomain")
+	assert.EqualError(t, err, `invalid location DoH subdomain "bad.subdomain"`)
+
+	gatewayTag = ""
+	_, err = client.TeamsGatewayDoHURL(context.Background(), testAccountID, "oli3n9zkz5")
+	assert.Equal(t, ErrTeamsAccountNotProvisioned, err)
+}
+
+func TestValidateTeamsConfiguration(t *testing.T) {
+	assert.Empty(t, ValidateTeamsConfiguration(TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			BlockPage:             &TeamsBlockPage{BackgroundColor: "#FF8800", MailtoAddress: "admin@example.com", MailtoSubject: "Blocked"},
+			BodyScanning:          &TeamsBodyScanning{InspectionMode: TeamsBodyScanningDeep},
+			UntrustedCertSettings: &TeamsUntrustedCertSettings{Action: TeamsUntrustedCertError},
+			CustomCertificate:     &TeamsCustomCertificate{Enabled: true, ID: "d1b364c5-1311-466e-a194-f0e943e0799f"},
+		},
+	}))
+
+	errs := ValidateTeamsConfiguration(TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			Antivirus:             &TeamsAntivirus{NotificationSettings: &TeamsNotificationSettings{SupportURL: "support"}},
+			BlockPage:             &TeamsBlockPage{BackgroundColor: "orange", MailtoSubject: "Blocked"},
+			BodyScanning:          &TeamsBodyScanning{InspectionMode: "thorough"},
+			UntrustedCertSettings: &TeamsUntrustedCertSettings{Action: "allow"},
+			CustomCertificate:     &TeamsCustomCertificate{Enabled: true},
+		},
+	})
+
+	var messages []string
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	assert.Equal(t, []string{
+		`invalid notification support URL "support": must be an absolute http or https URL`,
+		`invalid block page background color "orange": must be a #RRGGBB hex color`,
+		"block page mailto subject requires a mailto address",
+		`invalid body scanning inspection mode "thorough", must be one of deep, shallow`,
+		`invalid untrusted certificate action "allow", must be one of pass_through, block, error`,
+		"custom certificate must set an ID when enabled",
+	}, messages)
+}
+
+func TestTeamsAccountPatchSandboxConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{"settings":{"sandbox":{"enabled":true,"fallback_action":"block"}}}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"sandbox": {"enabled": true, "fallback_action": "block"}}}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{
+		Sandbox: &TeamsSandboxPatch{Enabled: BoolPtr(true), FallbackAction: TeamsSandboxFallbackBlock},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsAccountSettings{
+			Sandbox: &TeamsSandbox{Enabled: true, FallbackAction: TeamsSandboxFallbackBlock},
+		}, actual.Settings)
+	}
+
+	_, err = client.TeamsAccountPatchConfiguration(context.Background(), testAccountID, TeamsAccountSettingsPatch{
+		Sandbox: &TeamsSandboxPatch{FallbackAction: "quarantine"},
+	})
+	assert.EqualError(t, err, `invalid sandbox fallback action "quarantine", must be one of allow, block`)
+
+	b, err := json.Marshal(TeamsAccountSettings{Antivirus: &TeamsAntivirus{}})
+	if assert.NoError(t, err) {
+		assert.NotContains(t, string(b), "sandbox")
+	}
+}
+
+func TestTeamsAccountHostSelectorConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, err := ioutil.ReadAll(r.Body)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, `{
+					"settings": {"host_selector": {"enabled": true}},
+					"created_at": "0001-01-01T00:00:00Z",
+					"updated_at": "0001-01-01T00:00:00Z"
+				}`, string(body))
+			}
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"host_selector": {"enabled": true}}}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	want := TeamsConfiguration{Settings: TeamsAccountSettings{HostSelector: &TeamsHostSelector{Enabled: true}}}
+
+	configuration, err := client.TeamsAccountConfiguration(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, configuration)
+	}
+
+	actual, err := client.TeamsAccountUpdateConfiguration(context.Background(), testAccountID, configuration)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsAccountsBatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		accountID := strings.Split(r.URL.Path, "/")[2]
+		w.Header().Set("content-type", "application/json")
+		if accountID == "account-3" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintf(w, `{"success": false, "errors": [{"code": 2005, "message": "gateway not provisioned"}], "messages": [], "result": null}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "gateway_tag": "tag-%s", "provider_name": "cf"}
+		}`, accountID, accountID)
+	}
+
+	accountIDs := []string{"account-1", "account-2", "account-3", "account-4"}
+	for _, accountID := range accountIDs {
+		mux.HandleFunc("/accounts/"+accountID+"/gateway", handler)
+	}
+
+	accounts, errs := client.TeamsAccountsBatch(context.Background(), accountIDs, 2)
+
+	assert.LessOrEqual(t, maxSeen, 2)
+	assert.Len(t, accounts, 3)
+	for _, accountID := range []string{"account-1", "account-2", "account-4"} {
+		assert.Equal(t, TeamsAccount{ID: accountID, GatewayTag: "tag-" + accountID, ProviderName: "cf"}, accounts[accountID])
+	}
+	if assert.Len(t, errs, 1) {
+		assert.ErrorIs(t, errs["account-3"], ErrTeamsAccountNotProvisioned)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	accounts, errs = client.TeamsAccountsBatch(ctx, accountIDs, 2)
+	assert.Empty(t, accounts)
+	if assert.Len(t, errs, 4) {
+		for _, err := range errs {
+			assert.ErrorIs(t, err, context.Canceled)
+		}
+	}
+}
+
+func TestTeamsAccountUpdateConfigurationBulk(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var (
+		mu                sync.Mutex
+		inFlight, maxSeen int
+	)
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+
+		mu.Lock()
+		inFlight++
+		if inFlight > maxSeen {
+			maxSeen = inFlight
+		}
+		mu.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		inFlight--
+		mu.Unlock()
+
+		w.Header().Set("content-type", "application/json")
+		if strings.Contains(r.URL.Path, "account-3") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprintf(w, `{"success": false, "errors": [{"code": 10000, "message": "Authentication error"}], "messages": [], "result": null}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"tls_decrypt": {"enabled": true}}}
+		}`)
+	}
+
+	accountIDs := []string{"account-1", "account-2", "account-3", "account-4"}
+	for _, accountID := range accountIDs {
+		mux.HandleFunc("/accounts/"+accountID+"/gateway/configuration", handler)
+	}
+
+	config := TeamsConfiguration{Settings: TeamsAccountSettings{TLSDecrypt: &TeamsTLSDecrypt{Enabled: true}}}
+	results, err := client.TeamsAccountUpdateConfigurationBulk(context.Background(), accountIDs, config, 2)
+
+	if assert.NoError(t, err) {
+		assert.Len(t, results, 4)
+		assert.LessOrEqual(t, maxSeen, 2)
+		for _, accountID := range []string{"account-1", "account-2", "account-4"} {
+			assert.NoError(t, results[accountID].Err)
+			assert.Equal(t, config, results[accountID].Configuration)
+		}
+		assert.Error(t, results["account-3"].Err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err = client.TeamsAccountUpdateConfigurationBulk(ctx, accountIDs, config, 2)
+	if assert.NoError(t, err) {
+		assert.Len(t, results, 4)
+		for _, result := range results {
+			assert.ErrorIs(t, result.Err, context.Canceled)
+		}
+	}
+
+	_, err = client.TeamsAccountUpdateConfigurationBulk(context.Background(), accountIDs, TeamsConfiguration{
+		Settings: TeamsAccountSettings{BlockPage: &TeamsBlockPage{BackgroundColor: "orange"}},
+	}, 2)
+	assert.Error(t, err)
+}
+
+func TestTeamsAccountLoggingConfigurationRoundTrip(t *testing.T) {
+	setup()
+	defer teardown()
+
+	settingsJSON := `{
+		"settings_by_rule_type": {
+			"dns": {"log_all": false, "log_blocks": true},
+			"http": {"log_all": true, "log_blocks": true},
+			"l4": {"log_all": false, "log_blocks": false}
+		},
+		"redact_pii": true
+	}`
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			body, err := ioutil.ReadAll(r.Body)
+			if assert.NoError(t, err) {
+				assert.JSONEq(t, settingsJSON, string(body))
+			}
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s
+		}`, settingsJSON)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", handler)
+
+	want := TeamsLoggingSettings{
+		RedactPii: true,
+		LoggingSettingsByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsDnsRuleType:  {LogAll: false, LogBlocks: true},
+			TeamsHttpRuleType: {LogAll: true, LogBlocks: true},
+			TeamsL4RuleType:   {LogAll: false, LogBlocks: false},
+		},
+	}
+
+	settings, err := client.TeamsAccountLoggingConfiguration(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, settings)
+	}
+
+	actual, err := client.TeamsAccountUpdateLoggingConfiguration(context.Background(), testAccountID, settings)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsAccountUpdateConfigurationIfUnmodified(t *testing.T) {
+	setup()
+	defer teardown()
+
+	updates := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			updates++
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {"tls_decrypt": {"enabled": true}},
+				"created_at": "2022-10-01T12:00:00Z",
+				"updated_at": "2022-10-02T12:00:00Z"
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	config, err := client.TeamsAccountConfiguration(context.Background(), testAccountID)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = client.TeamsAccountUpdateConfigurationIfUnmodified(context.Background(), testAccountID, config)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updates)
+
+	config.UpdatedAt = config.UpdatedAt.Add(-time.Hour)
+	_, err = client.TeamsAccountUpdateConfigurationIfUnmodified(context.Background(), testAccountID, config)
+	assert.Equal(t, ErrConfigConflict, err)
+	assert.Equal(t, 1, updates)
+
+	_, err = client.TeamsAccountUpdateConfigurationIfUnmodified(context.Background(), testAccountID, TeamsConfiguration{})
+	assert.Error(t, err)
+}
+
+func TestTeamsBlockPageEnabledMarshal(t *testing.T) {
+	b, err := json.Marshal(TeamsBlockPage{Enabled: BoolPtr(false)})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"enabled":false}`, string(b))
+	}
+
+	b, err = json.Marshal(TeamsBlockPage{FooterText: "footer"})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"footer_text":"footer"}`, string(b))
+	}
+
+	b, err = json.Marshal(TeamsAccountSettings{BlockPage: &TeamsBlockPage{Enabled: BoolPtr(false)}})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"block_page":{"enabled":false}}`, string(b))
+	}
+}
+
+func TestTeamsBlockPageContextAndFooter(t *testing.T) {
+	b, err := json.Marshal(TeamsBlockPage{IncludeContext: BoolPtr(true), SuppressFooter: BoolPtr(false)})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"include_context":true,"suppress_footer":false}`, string(b))
+	}
+
+	b, err = json.Marshal(TeamsBlockPage{})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{}`, string(b))
+	}
+
+	var blockPage TeamsBlockPage
+	err = json.Unmarshal([]byte(`{"name":"external","include_context":false,"suppress_footer":true}`), &blockPage)
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsBlockPage{Name: "external", IncludeContext: BoolPtr(false), SuppressFooter: BoolPtr(true)}, blockPage)
+	}
+}
+
+func TestTeamsAccountSetting(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"antivirus": {"enabled_download_phase": true},
+					"email_link_isolation": {"enabled": true},
+					"new_setting": {"enabled": true}
+				}
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountSetting(context.Background(), testAccountID, "antivirus")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsAntivirus{EnabledDownloadPhase: true}, actual)
+	}
+
+	actual, err = client.TeamsAccountSetting(context.Background(), testAccountID, "email_link_isolation")
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsEmailLinkIsolation{Enabled: true}, actual)
+	}
+
+	actual, err = client.TeamsAccountSetting(context.Background(), testAccountID, "new_setting")
+	if assert.NoError(t, err) {
+		assert.Equal(t, json.RawMessage(`{"enabled": true}`), actual)
+	}
+
+	actual, err = client.TeamsAccountSetting(context.Background(), testAccountID, "tls_decrypt")
+	if assert.NoError(t, err) {
+		assert.Nil(t, actual)
+	}
+
+	_, err = client.TeamsAccountSetting(context.Background(), testAccountID, "")
+	assert.EqualError(t, err, "setting name cannot be empty")
+}
+
+func TestTeamsConfigurationDiff(t *testing.T) {
+	from := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			Antivirus:  &TeamsAntivirus{EnabledDownloadPhase: true},
+			TLSDecrypt: &TeamsTLSDecrypt{Enabled: true},
+			BlockPage:  &TeamsBlockPage{Enabled: BoolPtr(true), FooterText: "old footer"},
+			Extra:      map[string]json.RawMessage{"new_setting": json.RawMessage(`{"enabled":true}`)},
+		},
+	}
+	to := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			Antivirus:   &TeamsAntivirus{EnabledDownloadPhase: true, FailClosed: true},
+			BlockPage:   &TeamsBlockPage{Enabled: BoolPtr(false), FooterText: "old footer"},
+			ActivityLog: &TeamsActivityLog{},
+		},
+	}
+
+	assert.Empty(t, from.Diff(from))
+	assert.Equal(t, []TeamsSettingChange{
+		{Path: "settings.antivirus.fail_closed", Old: false, New: true},
+		{Path: "settings.tls_decrypt", Old: TeamsTLSDecrypt{Enabled: true}, New: nil},
+		{Path: "settings.activity_log", Old: nil, New: TeamsActivityLog{}},
+		{Path: "settings.block_page.enabled", Old: true, New: false},
+		{Path: "settings.new_setting", Old: json.RawMessage(`{"enabled":true}`), New: nil},
+	}, from.Diff(to))
+}
+
+func TestTeamsAccountConfigurationDrift(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"tls_decrypt": {"enabled": true},
+					"activity_log": {"enabled": true},
+					"custom_certificate": {
+						"enabled": true,
+						"id": "d1b364c5-1311-466e-a194-f0e943e0799f",
+						"binding_status": "active",
+						"updated_at": "2022-10-02T12:00:00Z"
+					},
+					"new_setting": {"enabled": true, "mode": "strict"}
+				},
+				"created_at": "2022-10-01T12:00:00Z",
+				"updated_at": "2022-10-02T12:00:00Z"
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	desired := TeamsConfiguration{
+		Settings: TeamsAccountSettings{
+			TLSDecrypt:        &TeamsTLSDecrypt{Enabled: true},
+			ActivityLog:       &TeamsActivityLog{Enabled: true},
+			CustomCertificate: &TeamsCustomCertificate{Enabled: true, ID: "d1b364c5-1311-466e-a194-f0e943e0799f"},
+			Extra:             map[string]json.RawMessage{"new_setting": json.RawMessage(`{"mode":"strict","enabled":true}`)},
+		},
+	}
+
+	inSync, changes, err := client.TeamsAccountConfigurationDrift(context.Background(), testAccountID, desired)
+	if assert.NoError(t, err) {
+		assert.True(t, inSync)
+		assert.Empty(t, changes)
+	}
+
+	desired.Settings.TLSDecrypt.Enabled = false
+	desired.Settings.ActivityLog = nil
+
+	inSync, changes, err = client.TeamsAccountConfigurationDrift(context.Background(), testAccountID, desired)
+	if assert.NoError(t, err) {
+		assert.False(t, inSync)
+		assert.Equal(t, []TeamsSettingChange{
+			{Path: "settings.tls_decrypt.enabled", Old: true, New: false},
+			{Path: "settings.activity_log", Old: TeamsActivityLog{Enabled: true}, New: nil},
+		}, changes)
+	}
+}
+
+func TestGatewayLogpushJobs(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": 1, "dataset": "gateway_dns", "enabled": true, "name": "dns", "destination_conf": "s3://logs/dns"},
+				{"id": 2, "dataset": "audit_logs", "enabled": true, "name": "audit", "destination_conf": "s3://logs/audit"},
+				{"id": 3, "dataset": "gateway_http", "enabled": false, "name": "http", "destination_conf": "s3://logs/http"}
+			]
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/logpush/jobs", handler)
+
+	actual, err := client.GatewayLogpushJobs(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) && assert.Len(t, actual, 2) {
+		assert.Equal(t, GatewayDNSLogpushDataset, actual[0].Dataset)
+		assert.Equal(t, "s3://logs/dns", actual[0].DestinationConf)
+		assert.Equal(t, GatewayHTTPLogpushDataset, actual[1].Dataset)
+	}
+}
+
+func TestCopyTeamsConfiguration(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const dstAccountID = "f3b12456e17b4d4a8d6bbd5ad2ffd6c3"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"activity_log": {"enabled": true},
+					"custom_certificate": {"enabled": true, "id": "d1b364c5-1311-466e-a194-f0e943e0799f"},
+					"email_link_isolation": {"enabled": true}
+				},
+				"created_at": "2022-06-14T05:20:00Z",
+				"updated_at": "2022-06-15T05:20:00Z"
+			}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+dstAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, `{
+				"settings": {
+					"activity_log": {"enabled": true},
+					"email_link_isolation": {"enabled": true}
+				},
+				"created_at": "0001-01-01T00:00:00Z",
+				"updated_at": "0001-01-01T00:00:00Z"
+			}`, string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"activity_log": {"enabled": true}}}
+		}`)
+	})
+
+	actual, err := client.CopyTeamsConfiguration(context.Background(), testAccountID, dstAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsConfiguration{Settings: TeamsAccountSettings{ActivityLog: &TeamsActivityLog{Enabled: true}}}, actual)
+	}
+}
+
+func TestTeamsAccountSettingsInspectionMode(t *testing.T) {
+	mode := TeamsInspectionStandard
+	b, err := json.Marshal(TeamsAccountSettings{InspectionMode: &mode})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"inspection_mode":"standard"}`, string(b))
+	}
+
+	b, err = json.Marshal(TeamsAccountSettings{})
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{}`, string(b))
+	}
+
+	var settings TeamsAccountSettings
+	if assert.NoError(t, json.Unmarshal([]byte(`{"inspection_mode":"deep"}`), &settings)) {
+		assert.Equal(t, TeamsInspectionDeep, *settings.InspectionMode)
+		assert.Nil(t, settings.Extra)
+	}
+
+	invalid := "full"
+	errs := ValidateTeamsConfiguration(TeamsConfiguration{Settings: TeamsAccountSettings{InspectionMode: &invalid}})
+	if assert.Len(t, errs, 1) {
+		assert.EqualError(t, errs[0], `invalid inspection mode "full", must be one of none, standard, deep`)
+	}
+}
+
+func TestTeamsFIPSRoundTrip(t *testing.T) {
+	var settings TeamsAccountSettings
+	err := json.Unmarshal([]byte(`{"fips": {"tls": true, "required": true, "mode": "strict"}}`), &settings)
+
+	if assert.NoError(t, err) && assert.NotNil(t, settings.FIPS) {
+		assert.True(t, settings.FIPS.TLS)
+		assert.Equal(t, BoolPtr(true), settings.FIPS.Required)
+		assert.Equal(t, map[string]json.RawMessage{"mode": json.RawMessage(`"strict"`)}, settings.FIPS.Extra)
+
+		data, err := json.Marshal(settings)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"fips": {"tls": true, "required": true, "mode": "strict"}}`, string(data))
+	}
+
+	data, err := json.Marshal(TeamsFIPS{})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"tls": false}`, string(data))
+}
+
+func TestTeamsTLSDecryptRoundTrip(t *testing.T) {
+	var config TeamsConfiguration
+	err := json.Unmarshal([]byte(`{"settings": {"tls_decrypt": {"enabled": true}}}`), &config)
+
+	if assert.NoError(t, err) && assert.NotNil(t, config.Settings.TLSDecrypt) {
+		assert.Equal(t, &TeamsTLSDecrypt{Enabled: true}, config.Settings.TLSDecrypt)
+
+		data, err := json.Marshal(config.Settings)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"tls_decrypt": {"enabled": true}}`, string(data))
+	}
+
+	// fields the API may add are kept
+	var decrypt TeamsTLSDecrypt
+	err = json.Unmarshal([]byte(`{"enabled": false, "protocols": {"https": true}}`), &decrypt)
+	if assert.NoError(t, err) {
+		assert.False(t, decrypt.Enabled)
+
+		data, err := json.Marshal(decrypt)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"enabled": false, "protocols": {"https": true}}`, string(data))
+	}
+}
+
+func TestTeamsAccountConfigurationWithParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"antivirus": {"enabled_download_phase": true},
+					"fips": {"tls": true},
+					"tls_decrypt": {"enabled": true},
+					"email_link_isolation": {"enabled": true}
+				}
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", handler)
+
+	actual, err := client.TeamsAccountConfigurationWithParams(context.Background(), testAccountID, TeamsAccountConfigurationParams{
+		Settings: []string{"fips", "email_link_isolation"},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsFIPS{TLS: true}, actual.Settings.FIPS)
+		assert.Nil(t, actual.Settings.Antivirus)
+		assert.Nil(t, actual.Settings.TLSDecrypt)
+		assert.Equal(t, &TeamsEmailLinkIsolation{Enabled: true}, actual.Settings.EmailLinkIsolation)
+	}
+
+	actual, err = client.TeamsAccountConfigurationWithParams(context.Background(), testAccountID, TeamsAccountConfigurationParams{})
+
+	if assert.NoError(t, err) {
+		assert.NotNil(t, actual.Settings.Antivirus)
+		assert.NotNil(t, actual.Settings.TLSDecrypt)
+	}
+}
@@ -0,0 +1,49 @@
+package cloudflare
+
+import (
+	"context"
+)
+
+// TeamsAPI is the Teams account and configuration API. It is implemented by
+// *API and lets code depending on these methods be tested with a fake
+// instead of a client.
+type TeamsAPI interface {
+	TeamsAccount(ctx context.Context, accountID string) (TeamsAccount, error)
+	TeamsAccountsBatch(ctx context.Context, accountIDs []string, concurrency int) (map[string]TeamsAccount, map[string]error)
+	TeamsProvisionAccount(ctx context.Context, accountID string) (TeamsAccount, error)
+	TeamsAccountIdentityProviders(ctx context.Context, accountID string) ([]AccessIdentityProvider, error)
+	TeamsGatewayDoHURL(ctx context.Context, accountID, locationSubdomain string) (string, error)
+	GatewayLogpushJobs(ctx context.Context, accountID string) ([]LogpushJob, error)
+	TeamsAccountUsage(ctx context.Context, accountID string) (TeamsUsage, error)
+
+	TeamsAccountConfiguration(ctx context.Context, accountID string) (TeamsConfiguration, error)
+	TeamsAccountConfigurationWithParams(ctx context.Context, accountID string, params TeamsAccountConfigurationParams) (TeamsConfiguration, error)
+	TeamsAccountSetting(ctx context.Context, accountID, name string) (interface{}, error)
+	TeamsAccountConfigurationDrift(ctx context.Context, accountID string, desired TeamsConfiguration) (bool, []TeamsSettingChange, error)
+	TeamsAccountUpdateConfiguration(ctx context.Context, accountID string, config TeamsConfiguration) (TeamsConfiguration, error)
+	TeamsAccountUpdateConfigurationIfUnmodified(ctx context.Context, accountID string, config TeamsConfiguration) (TeamsConfiguration, error)
+	TeamsAccountUpdateConfigurationBulk(ctx context.Context, accountIDs []string, config TeamsConfiguration, concurrency int) (map[string]TeamsConfigurationResult, error)
+	TeamsAccountPatchConfiguration(ctx context.Context, accountID string, patch TeamsAccountSettingsPatch) (TeamsConfiguration, error)
+	CopyTeamsConfiguration(ctx context.Context, srcAccountID, dstAccountID string) (TeamsConfiguration, error)
+
+	TeamsAccountAntivirus(ctx context.Context, accountID string) (TeamsAntivirus, error)
+	UpdateTeamsAccountAntivirus(ctx context.Context, accountID string, antivirus TeamsAntivirus) (TeamsAntivirus, error)
+	TeamsAccountTLSDecrypt(ctx context.Context, accountID string) (TeamsTLSDecrypt, error)
+	UpdateTeamsAccountTLSDecrypt(ctx context.Context, accountID string, tlsDecrypt TeamsTLSDecrypt) (TeamsTLSDecrypt, error)
+	TeamsAccountActivityLog(ctx context.Context, accountID string) (TeamsActivityLog, error)
+	UpdateTeamsAccountActivityLog(ctx context.Context, accountID string, activityLog TeamsActivityLog) (TeamsActivityLog, error)
+	TeamsAccountFIPS(ctx context.Context, accountID string) (TeamsFIPS, error)
+	UpdateTeamsAccountFIPS(ctx context.Context, accountID string, fips TeamsFIPS) (TeamsFIPS, error)
+	TeamsAccountBrowserIsolation(ctx context.Context, accountID string) (BrowserIsolation, error)
+	UpdateTeamsAccountBrowserIsolation(ctx context.Context, accountID string, settings BrowserIsolation) (BrowserIsolation, error)
+
+	TeamsAccountLoggingConfiguration(ctx context.Context, accountID string) (TeamsLoggingSettings, error)
+	TeamsAccountUpdateLoggingConfiguration(ctx context.Context, accountID string, config TeamsLoggingSettings, opts ...TeamsLoggingOption) (TeamsLoggingSettings, error)
+	TeamsAccountResetLoggingConfiguration(ctx context.Context, accountID string) (TeamsLoggingSettings, error)
+
+	TeamsAccountDeviceConfiguration(ctx context.Context, accountID string) (TeamsDeviceSettings, error)
+	TeamsAccountDeviceUpdateConfiguration(ctx context.Context, accountID string, settings TeamsDeviceSettings) (TeamsDeviceSettings, error)
+	TeamsAccountDeviceUpdateConfigurationWithParams(ctx context.Context, accountID string, params TeamsDeviceSettingsUpdateParams) (TeamsDeviceSettings, error)
+}
+
+var _ TeamsAPI = (*API)(nil)
@@ -0,0 +1,149 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// TeamsApplicationKind distinguishes applications from the application types
+// they are grouped under.
+type TeamsApplicationKind = string
+
+const (
+	TeamsApplicationKindApplication     TeamsApplicationKind = "application"
+	TeamsApplicationKindApplicationType TeamsApplicationKind = "application_type"
+)
+
+// TeamsApplication represents an entry of the Gateway application catalog.
+// The catalog contains both applications and application types, entries
+// without an ApplicationTypeID are application types.
+//
+// The catalog is read-only, the API has no endpoint for custom
+// applications. To group hostnames, use a DOMAIN or HOST list (see
+// CreateTeamsList) and match it in the rule traffic expression instead.
+type TeamsApplication struct {
+	ID                int                  `json:"id"`
+	Name              string               `json:"name"`
+	Type              TeamsApplicationKind `json:"-"`
+	Description       string               `json:"description,omitempty"`
+	ApplicationTypeID int                  `json:"application_type_id,omitempty"`
+}
+
+// TeamsApplicationsResponse is the API response, containing an array of
+// applications and application types.
+type TeamsApplicationsResponse struct {
+	Response
+	ResultInfo `json:"result_info"`
+	Result     []TeamsApplication `json:"result"`
+}
+
+// TeamsApplicationsListParams contains the pagination parameters of the
+// application catalog.
+type TeamsApplicationsListParams struct {
+	ResultInfo
+}
+
+// TeamsApplications returns the Gateway application and application type
+// catalog, fetching every page. If a page after the first one fails, the
+// entries fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-gateway-application-and-application-type-mappings-list-application-and-application-type-mappings
+func (api *API) TeamsApplications(ctx context.Context, accountID string) ([]TeamsApplication, error) {
+	var applications []TeamsApplication
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		uri := buildURI(fmt.Sprintf("/%s/%s/gateway/app_types", AccountRouteRoot, accountID), TeamsApplicationsListParams{params})
+
+		res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var teamsApplicationsResponse TeamsApplicationsResponse
+		err = json.Unmarshal(res, &teamsApplicationsResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		applications = append(applications, teamsApplicationsResponse.Result...)
+		return len(teamsApplicationsResponse.Result), teamsApplicationsResponse.ResultInfo, nil
+	})
+
+	if err != nil && !isPaginationError(err) {
+		return []TeamsApplication{}, err
+	}
+
+	for i := range applications {
+		applications[i].Type = TeamsApplicationKindApplication
+		if applications[i].ApplicationTypeID == 0 {
+			applications[i].Type = TeamsApplicationKindApplicationType
+		}
+	}
+
+	return applications, err
+}
+
+// NewTeamsAppIsolateRule returns an enabled HTTP rule isolating the
+// applications and application types named appNames. Names are resolved
+// with the Gateway catalog, through the catalog cache when it is enabled,
+// and compared case insensitively. Isolate rules have no effect while
+// browser isolation is turned off for the account, which is logged as a
+// warning with the client's logger.
+func (api *API) NewTeamsAppIsolateRule(ctx context.Context, accountID, name string, appNames []string) (TeamsRule, error) {
+	if len(appNames) == 0 {
+		return TeamsRule{}, fmt.Errorf("at least one application name is required")
+	}
+
+	applications, err := api.teamsApplications(ctx, accountID)
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
+	var appIDs, appTypeIDs []int
+	for _, appName := range appNames {
+		found := false
+		for _, application := range applications {
+			if !strings.EqualFold(application.Name, appName) {
+				continue
+			}
+			if application.Type == TeamsApplicationKindApplicationType {
+				appTypeIDs = append(appTypeIDs, application.ID)
+			} else {
+				appIDs = append(appIDs, application.ID)
+			}
+			found = true
+			break
+		}
+		if !found {
+			return TeamsRule{}, fmt.Errorf("unknown application %q", appName)
+		}
+	}
+
+	var expr TeamsExpr
+	switch {
+	case len(appIDs) > 0 && len(appTypeIDs) > 0:
+		expr = TeamsExprHTTPApp.InIDs(appIDs...).Or(TeamsExprHTTPAppType.InIDs(appTypeIDs...))
+	case len(appIDs) > 0:
+		expr = TeamsExprHTTPApp.InIDs(appIDs...)
+	default:
+		expr = TeamsExprHTTPAppType.InIDs(appTypeIDs...)
+	}
+
+	isolation, err := api.TeamsAccountBrowserIsolation(ctx, accountID)
+	if err != nil {
+		return TeamsRule{}, err
+	}
+	if !isolation.UrlBrowserIsolationEnabled {
+		api.logger.Printf("browser isolation is disabled for account %s, isolate rule %q will have no effect until it is enabled", accountID, name)
+	}
+
+	return TeamsRule{
+		Name:    name,
+		Enabled: true,
+		Action:  Isolate,
+		Filters: []TeamsFilterType{HttpFilter},
+		Traffic: expr.String(),
+	}, nil
+}
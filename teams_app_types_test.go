@@ -0,0 +1,103 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsApplications(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": 16, "name": "Social Networking", "description": "Social networks"}
+				],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+		case "2":
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": 622, "name": "Facebook", "application_type_id": 16}
+				],
+				"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+		default:
+			assert.Failf(t, "unexpected page", "page %s", r.URL.Query().Get("page"))
+		}
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/app_types", handler)
+
+	want := []TeamsApplication{
+		{ID: 16, Name: "Social Networking", Type: TeamsApplicationKindApplicationType, Description: "Social networks"},
+		{ID: 622, Name: "Facebook", Type: TeamsApplicationKindApplication, ApplicationTypeID: 16},
+	}
+
+	actual, err := client.TeamsApplications(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestNewTeamsAppIsolateRule(t *testing.T) {
+	var logs bytes.Buffer
+	setup(UsingLogger(log.New(&logs, "", 0)))
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/app_types", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": 16, "name": "File Sharing"},
+				{"id": 519, "name": "Dropbox", "application_type_id": 16},
+				{"id": 530, "name": "Box", "application_type_id": 16}
+			],
+			"result_info": {"page": 1, "per_page": 50, "count": 3, "total_count": 3, "total_pages": 1}
+		}`)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"settings": {}}}`)
+	})
+
+	actual, err := client.NewTeamsAppIsolateRule(context.Background(), testAccountID, "isolate file sharing", []string{"dropbox", "File Sharing"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{
+			Name:    "isolate file sharing",
+			Enabled: true,
+			Action:  Isolate,
+			Filters: []TeamsFilterType{HttpFilter},
+			Traffic: "any(app.ids[*] in {519}) or any(app.type.ids[*] in {16})",
+		}, actual)
+		assert.Contains(t, logs.String(), `browser isolation is disabled for account `+testAccountID)
+	}
+
+	_, err = client.NewTeamsAppIsolateRule(context.Background(), testAccountID, "isolate", []string{"Slack"})
+	assert.EqualError(t, err, `unknown application "Slack"`)
+}
@@ -0,0 +1,72 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// AuditSSHSettings holds the public key used to encrypt the SSH session
+// logs recorded by the Gateway SSH proxy.
+type AuditSSHSettings struct {
+	PublicKey string     `json:"public_key"`
+	SeedID    string     `json:"seed_id,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// AuditSSHSettingsResponse is the API response, containing the SSH audit
+// settings.
+type AuditSSHSettingsResponse struct {
+	Response
+	Result AuditSSHSettings `json:"result"`
+}
+
+// GetAuditSSHSettings returns the SSH audit settings of an account.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-get-audit-ssh-settings
+func (api *API) GetAuditSSHSettings(ctx context.Context, accountID string) (AuditSSHSettings, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/audit_ssh_settings", AccountRouteRoot, accountID)
+
+	return api.auditSSHSettingsRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// UpdateAuditSSHSettings sets the public key used to encrypt SSH session
+// logs.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-update-ssh-settings
+func (api *API) UpdateAuditSSHSettings(ctx context.Context, accountID string, settings AuditSSHSettings) (AuditSSHSettings, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/audit_ssh_settings", AccountRouteRoot, accountID)
+
+	return api.auditSSHSettingsRequest(ctx, http.MethodPut, uri, AuditSSHSettings{
+		PublicKey: settings.PublicKey,
+		SeedID:    settings.SeedID,
+	})
+}
+
+// RotateAuditSSHSettings generates a new seed used to derive the SSH
+// session encryption keys.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-rotate-ssh-account-seed
+func (api *API) RotateAuditSSHSettings(ctx context.Context, accountID string) (AuditSSHSettings, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/audit_ssh_settings/rotate_seed", AccountRouteRoot, accountID)
+
+	return api.auditSSHSettingsRequest(ctx, http.MethodPost, uri, nil)
+}
+
+func (api *API) auditSSHSettingsRequest(ctx context.Context, method, uri string, params interface{}) (AuditSSHSettings, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return AuditSSHSettings{}, err
+	}
+
+	var auditSSHSettingsResponse AuditSSHSettingsResponse
+	err = json.Unmarshal(res, &auditSSHSettingsResponse)
+	if err != nil {
+		return AuditSSHSettings{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return auditSSHSettingsResponse.Result, nil
+}
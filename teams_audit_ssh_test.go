@@ -0,0 +1,113 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testAuditSSHPublicKey = "1pyl6I1tL7xfJuFYVzXlUW8uXXlpxegHXBzGCBKaSFA="
+	testAuditSSHSeedID    = "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"
+)
+
+func TestGetAuditSSHSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"public_key": "%s",
+				"seed_id": "%s",
+				"created_at": "2014-01-01T05:20:00Z",
+				"updated_at": "2014-01-01T05:20:00Z"
+			}
+		}`, testAuditSSHPublicKey, testAuditSSHSeedID)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/audit_ssh_settings", handler)
+
+	timestamp := time.Date(2014, 1, 1, 5, 20, 0, 0, time.UTC)
+	want := AuditSSHSettings{
+		PublicKey: testAuditSSHPublicKey,
+		SeedID:    testAuditSSHSeedID,
+		CreatedAt: &timestamp,
+		UpdatedAt: &timestamp,
+	}
+
+	actual, err := client.GetAuditSSHSettings(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestUpdateAuditSSHSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		if assert.NoError(t, err) {
+			assert.JSONEq(t, fmt.Sprintf(`{"public_key":"%s","seed_id":"%s"}`, testAuditSSHPublicKey, testAuditSSHSeedID), string(body))
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"public_key": "%s",
+				"seed_id": "%s"
+			}
+		}`, testAuditSSHPublicKey, testAuditSSHSeedID)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/audit_ssh_settings", handler)
+
+	settings := AuditSSHSettings{PublicKey: testAuditSSHPublicKey, SeedID: testAuditSSHSeedID}
+	actual, err := client.UpdateAuditSSHSettings(context.Background(), testAccountID, settings)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, settings, actual)
+	}
+}
+
+func TestRotateAuditSSHSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"public_key": "%s",
+				"seed_id": "a174e90a-fafe-4643-bbbc-4a0ed4fc8415"
+			}
+		}`, testAuditSSHPublicKey)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/audit_ssh_settings/rotate_seed", handler)
+
+	actual, err := client.RotateAuditSSHSettings(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "a174e90a-fafe-4643-bbbc-4a0ed4fc8415", actual.SeedID)
+	}
+}
@@ -0,0 +1,73 @@
+package cloudflare
+
+import (
+	"strings"
+)
+
+// TeamsBlockPageText holds the texts of a block page in one language. Empty
+// fields fall back to the texts of the block page they localize.
+type TeamsBlockPageText struct {
+	Name          string `json:"name,omitempty"`
+	HeaderText    string `json:"header_text,omitempty"`
+	FooterText    string `json:"footer_text,omitempty"`
+	MailtoSubject string `json:"mailto_subject,omitempty"`
+}
+
+// TeamsLocalizedBlockPage is a block page along with translations of its
+// texts keyed by locale, such as "fr" or "pt-BR".
+//
+// The API stores a single set of texts per account and has no localized
+// variants, so the translations are never sent. Instead ForLocale picks the
+// block page of an account's locale before the configuration is updated,
+// which lets accounts of different regions be managed from one definition.
+type TeamsLocalizedBlockPage struct {
+	TeamsBlockPage
+	Localizations map[string]TeamsBlockPageText `json:"localizations,omitempty"`
+}
+
+// ForLocale returns the block page with the texts of locale. A locale
+// without a translation uses the translation of its language, "fr" for
+// "fr-CA", and otherwise the texts of the block page are kept. Locales are
+// compared case insensitively and may use "_" as separator.
+func (p TeamsLocalizedBlockPage) ForLocale(locale string) TeamsBlockPage {
+	page := p.TeamsBlockPage
+
+	text, ok := p.localization(locale)
+	if !ok {
+		if i := strings.IndexAny(locale, "-_"); i > 0 {
+			text, ok = p.localization(locale[:i])
+		}
+	}
+	if !ok {
+		return page
+	}
+
+	if text.Name != "" {
+		page.Name = text.Name
+	}
+	if text.HeaderText != "" {
+		page.HeaderText = text.HeaderText
+	}
+	if text.FooterText != "" {
+		page.FooterText = text.FooterText
+	}
+	if text.MailtoSubject != "" {
+		page.MailtoSubject = text.MailtoSubject
+	}
+
+	return page
+}
+
+func (p TeamsLocalizedBlockPage) localization(locale string) (TeamsBlockPageText, bool) {
+	normalize := func(s string) string {
+		return strings.ToLower(strings.ReplaceAll(s, "_", "-"))
+	}
+
+	for key, text := range p.Localizations {
+		if normalize(key) == normalize(locale) {
+			return text, true
+		}
+	}
+
+	return TeamsBlockPageText{}, false
+}
@@ -0,0 +1,42 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsLocalizedBlockPageForLocale(t *testing.T) {
+	page := TeamsLocalizedBlockPage{
+		TeamsBlockPage: TeamsBlockPage{
+			Name:            "Example Corp",
+			HeaderText:      "This website is blocked",
+			FooterText:      "Contact IT",
+			BackgroundColor: "#1a2b3c",
+		},
+		Localizations: map[string]TeamsBlockPageText{
+			"fr":    {HeaderText: "Ce site est bloqué", FooterText: "Contactez l'informatique"},
+			"fr-CA": {HeaderText: "Ce site Web est bloqué"},
+			"de":    {Name: "Example GmbH", HeaderText: "Diese Website ist gesperrt"},
+		},
+	}
+
+	assert.Equal(t, TeamsBlockPage{
+		Name:            "Example Corp",
+		HeaderText:      "Ce site Web est bloqué",
+		FooterText:      "Contact IT",
+		BackgroundColor: "#1a2b3c",
+	}, page.ForLocale("fr_ca"))
+
+	assert.Equal(t, TeamsBlockPage{
+		Name:            "Example Corp",
+		HeaderText:      "Ce site est bloqué",
+		FooterText:      "Contactez l'informatique",
+		BackgroundColor: "#1a2b3c",
+	}, page.ForLocale("fr-BE"))
+
+	assert.Equal(t, "Example GmbH", page.ForLocale("DE").Name)
+
+	assert.Equal(t, page.TeamsBlockPage, page.ForLocale("ja-JP"))
+	assert.Equal(t, page.TeamsBlockPage, page.ForLocale(""))
+}
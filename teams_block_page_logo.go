@@ -0,0 +1,86 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+)
+
+// gatewayBlockPageLogoMaxSize is the largest logo accepted for upload.
+const gatewayBlockPageLogoMaxSize = 1 << 20
+
+// GatewayBlockPageLogoResponse is the API response, containing the location
+// of an uploaded block page logo.
+type GatewayBlockPageLogoResponse struct {
+	Response
+	Result struct {
+		LogoPath string `json:"logo_path"`
+	} `json:"result"`
+}
+
+// UploadGatewayBlockPageLogo uploads a PNG or JPEG image of at most 1 MiB and
+// returns the URL to use as TeamsBlockPage.LogoPath.
+//
+// API reference: TBA.
+func (api *API) UploadGatewayBlockPageLogo(ctx context.Context, accountID string, r io.Reader, filename string) (string, error) {
+	logo, err := ioutil.ReadAll(io.LimitReader(r, gatewayBlockPageLogoMaxSize+1))
+	if err != nil {
+		return "", fmt.Errorf("error reading block page logo: %w", err)
+	}
+
+	if len(logo) > gatewayBlockPageLogoMaxSize {
+		return "", fmt.Errorf("block page logo must be at most %d bytes", gatewayBlockPageLogoMaxSize)
+	}
+
+	contentType := http.DetectContentType(logo)
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return "", fmt.Errorf("invalid block page logo content type %q: must be image/png or image/jpeg", contentType)
+	}
+
+	body := &bytes.Buffer{}
+	w := multipart.NewWriter(body)
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename=%q`, filename))
+	header.Set("Content-Type", contentType)
+	part, err := w.CreatePart(header)
+	if err != nil {
+		_ = w.Close()
+		return "", fmt.Errorf("error writing multipart body: %w", err)
+	}
+	if _, err := part.Write(logo); err != nil {
+		_ = w.Close()
+		return "", fmt.Errorf("error writing multipart body: %w", err)
+	}
+	_ = w.Close()
+
+	uri := fmt.Sprintf("/%s/%s/gateway/block_page/logo", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestWithAuthTypeAndHeaders(
+		ctx,
+		http.MethodPost,
+		uri,
+		body,
+		api.authType,
+		http.Header{
+			"Accept":       []string{"application/json"},
+			"Content-Type": []string{w.FormDataContentType()},
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+
+	var logoResponse GatewayBlockPageLogoResponse
+	err = json.Unmarshal(res, &logoResponse)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return logoResponse.Result.LogoPath, nil
+}
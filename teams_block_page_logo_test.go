@@ -0,0 +1,63 @@
+package cloudflare
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testPNGHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestUploadGatewayBlockPageLogo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+
+		file, header, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			defer file.Close()
+			assert.Equal(t, "logo.png", header.Filename)
+			assert.Equal(t, "image/png", header.Header.Get("Content-Type"))
+
+			content, err := ioutil.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, testPNGHeader, content)
+		}
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"logo_path": "https://assets.example.com/logo.png"}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/block_page/logo", handler)
+
+	actual, err := client.UploadGatewayBlockPageLogo(context.Background(), testAccountID, bytes.NewReader(testPNGHeader), "logo.png")
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "https://assets.example.com/logo.png", actual)
+	}
+}
+
+func TestUploadGatewayBlockPageLogoInvalid(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.UploadGatewayBlockPageLogo(context.Background(), testAccountID, strings.NewReader("GIF89a"), "logo.gif")
+	assert.EqualError(t, err, `invalid block page logo content type "image/gif": must be image/png or image/jpeg`)
+
+	tooLarge := append(append([]byte{}, testPNGHeader...), make([]byte, gatewayBlockPageLogoMaxSize)...)
+	_, err = client.UploadGatewayBlockPageLogo(context.Background(), testAccountID, bytes.NewReader(tooLarge), "logo.png")
+	assert.EqualError(t, err, "block page logo must be at most 1048576 bytes")
+}
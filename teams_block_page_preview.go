@@ -0,0 +1,103 @@
+package cloudflare
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"net/url"
+)
+
+// teamsBlockPagePreviewTemplate approximates the layout of the Gateway block
+// page. It only uses the customizable settings so it's suited to checking
+// colors, texts and logo rather than to a pixel exact comparison.
+var teamsBlockPagePreviewTemplate = template.Must(template.New("block_page").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { margin: 0; font-family: sans-serif; background-color: {{.BackgroundColor}}; }
+main { max-width: 40em; margin: 4em auto; text-align: center; }
+footer { text-align: center; font-size: small; }
+</style>
+</head>
+<body>
+<main>
+{{- if .LogoPath}}
+<img src="{{.LogoPath}}" alt="{{.Name}}">
+{{- end}}
+<h1>{{.HeaderText}}</h1>
+{{- if .Mailto}}
+<p><a href="{{.Mailto}}">Contact your administrator</a></p>
+{{- end}}
+</main>
+{{- if .FooterText}}
+<footer>{{.FooterText}}</footer>
+{{- end}}
+</body>
+</html>
+`))
+
+// Defaults used by the preview for unset block page settings.
+const (
+	teamsBlockPagePreviewBackgroundColor = "#ffffff"
+	teamsBlockPagePreviewHeaderText      = "This website is blocked"
+)
+
+// PreviewHTML renders the block page as HTML so that changes can be
+// reviewed before they are applied to the account. The API has no preview
+// endpoint so the page is rendered locally from a template approximating
+// the Gateway block page. Block pages in redirect mode have nothing to
+// preview and return an error.
+func (b TeamsBlockPage) PreviewHTML() (string, error) {
+	if err := b.Validate(); err != nil {
+		return "", err
+	}
+
+	if b.Mode == TeamsBlockPageRedirect {
+		return "", fmt.Errorf("block page redirects to %s and has no page to preview", b.TargetURI)
+	}
+
+	data := struct {
+		Name            string
+		HeaderText      string
+		FooterText      string
+		LogoPath        string
+		BackgroundColor template.CSS
+		Mailto          template.URL
+	}{
+		Name:            b.Name,
+		HeaderText:      b.HeaderText,
+		LogoPath:        b.LogoPath,
+		BackgroundColor: teamsBlockPagePreviewBackgroundColor,
+	}
+
+	if b.HeaderText == "" {
+		data.HeaderText = teamsBlockPagePreviewHeaderText
+	}
+
+	if b.SuppressFooter == nil || !*b.SuppressFooter {
+		data.FooterText = b.FooterText
+	}
+
+	// BackgroundColor is checked to be a #RRGGBB color by Validate
+	if b.BackgroundColor != "" {
+		data.BackgroundColor = template.CSS(b.BackgroundColor)
+	}
+
+	// MailtoAddress is checked to be an email address by Validate
+	if b.MailtoAddress != "" {
+		mailto := url.URL{Scheme: "mailto", Opaque: b.MailtoAddress}
+		if b.MailtoSubject != "" {
+			mailto.RawQuery = url.Values{"subject": {b.MailtoSubject}}.Encode()
+		}
+		data.Mailto = template.URL(mailto.String())
+	}
+
+	var buf bytes.Buffer
+	if err := teamsBlockPagePreviewTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("error rendering block page preview: %w", err)
+	}
+
+	return buf.String(), nil
+}
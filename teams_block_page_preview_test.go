@@ -0,0 +1,47 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsBlockPagePreviewHTML(t *testing.T) {
+	page := TeamsBlockPage{
+		Name:            "Example <Corp>",
+		HeaderText:      "Blocked by IT",
+		FooterText:      "Questions? Ask IT.",
+		LogoPath:        "https://logos.example.com/logo.png",
+		BackgroundColor: "#1a2b3c",
+		MailtoAddress:   "it@example.com",
+		MailtoSubject:   "Blocked page",
+	}
+
+	html, err := page.PreviewHTML()
+
+	if assert.NoError(t, err) {
+		assert.Contains(t, html, "<title>Example &lt;Corp&gt;</title>")
+		assert.Contains(t, html, "background-color: #1a2b3c;")
+		assert.Contains(t, html, `<img src="https://logos.example.com/logo.png"`)
+		assert.Contains(t, html, "<h1>Blocked by IT</h1>")
+		assert.Contains(t, html, `<a href="mailto:it@example.com?subject=Blocked&#43;page">`)
+		assert.Contains(t, html, "<footer>Questions? Ask IT.</footer>")
+	}
+
+	page.SuppressFooter = BoolPtr(true)
+	page.HeaderText = ""
+	html, err = page.PreviewHTML()
+
+	if assert.NoError(t, err) {
+		assert.Contains(t, html, "<h1>This website is blocked</h1>")
+		assert.NotContains(t, html, "<footer>")
+	}
+}
+
+func TestTeamsBlockPagePreviewHTMLInvalid(t *testing.T) {
+	_, err := TeamsBlockPage{BackgroundColor: "red"}.PreviewHTML()
+	assert.Error(t, err)
+
+	_, err = TeamsBlockPage{Mode: TeamsBlockPageRedirect, TargetURI: "https://example.com/blocked"}.PreviewHTML()
+	assert.EqualError(t, err, "block page redirects to https://example.com/blocked and has no page to preview")
+}
@@ -0,0 +1,74 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// teamsSeatUser is the part of a Zero Trust user needed to count seats.
+type teamsSeatUser struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	GatewaySeat bool   `json:"gateway_seat"`
+	AccessSeat  bool   `json:"access_seat"`
+}
+
+type teamsSeatUsersResponse struct {
+	Response
+	ResultInfo `json:"result_info"`
+	Result     []teamsSeatUser `json:"result"`
+}
+
+// TeamsBrowserIsolationSeats returns the number of browser isolation seats
+// in use. Browser isolation doesn't have seats of its own, it is licensed
+// per Gateway seat, so this is the number of Zero Trust users of the account
+// holding a Gateway seat.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-users-get-users
+func (api *API) TeamsBrowserIsolationSeats(ctx context.Context, accountID string) (int, error) {
+	users, err := api.teamsSeatUsers(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	seats := 0
+	for _, user := range users {
+		if user.GatewaySeat {
+			seats++
+		}
+	}
+
+	return seats, nil
+}
+
+// teamsSeatUsers returns every Zero Trust user of the account. If a page
+// after the first one fails, the users fetched so far are returned along
+// with a *PaginationError.
+func (api *API) teamsSeatUsers(ctx context.Context, accountID string) ([]teamsSeatUser, error) {
+	var users []teamsSeatUser
+	err := fetchAllPages(ctx, 100, func(params ResultInfo) (int, ResultInfo, error) {
+		pageOpts := PaginationOptions{Page: params.Page, PerPage: params.PerPage}
+		uri := buildURI(fmt.Sprintf("/%s/%s/access/users", AccountRouteRoot, accountID), pageOpts)
+
+		res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var usersResponse teamsSeatUsersResponse
+		err = json.Unmarshal(res, &usersResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		users = append(users, usersResponse.Result...)
+		return len(usersResponse.Result), usersResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return nil, err
+	}
+
+	return users, err
+}
@@ -0,0 +1,50 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsBrowserIsolationSeats(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": "3", "email": "c@example.com", "gateway_seat": true, "access_seat": false}
+				],
+				"result_info": {"page": 2, "per_page": 2, "total_pages": 2, "count": 1, "total_count": 3}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "1", "email": "a@example.com", "gateway_seat": true, "access_seat": true},
+				{"id": "2", "email": "b@example.com", "gateway_seat": false, "access_seat": true}
+			],
+			"result_info": {"page": 1, "per_page": 2, "total_pages": 2, "count": 2, "total_count": 3}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/users", handler)
+
+	seats, err := client.TeamsBrowserIsolationSeats(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, seats)
+	}
+}
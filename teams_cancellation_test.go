@@ -0,0 +1,82 @@
+package cloudflare
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// TestTeamsContextCancellation checks that cancelling the context aborts a
+// Teams request in flight, without waiting for the server or retrying.
+func TestTeamsContextCancellation(t *testing.T) {
+	calls := map[string]func(ctx context.Context) error{
+		"TeamsRules": func(ctx context.Context) error {
+			_, err := client.TeamsRules(ctx, testAccountID)
+			return err
+		},
+		"TeamsCreateRule": func(ctx context.Context) error {
+			_, err := client.TeamsCreateRule(ctx, testAccountID, TeamsRule{Name: "rule"})
+			return err
+		},
+		"TeamsDeleteRule": func(ctx context.Context) error {
+			return client.TeamsDeleteRule(ctx, testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36")
+		},
+		"TeamsAccountConfiguration": func(ctx context.Context) error {
+			_, err := client.TeamsAccountConfiguration(ctx, testAccountID)
+			return err
+		},
+		"TeamsAccountUpdateConfiguration": func(ctx context.Context) error {
+			_, err := client.TeamsAccountUpdateConfiguration(ctx, testAccountID, TeamsConfiguration{})
+			return err
+		},
+		"CreateTeamsList": func(ctx context.Context) error {
+			_, err := client.CreateTeamsList(ctx, testAccountID, TeamsList{Name: "list", Type: TeamsListTypeIP})
+			return err
+		},
+		"TeamsLocations": func(ctx context.Context) error {
+			_, _, err := client.TeamsLocations(ctx, testAccountID)
+			return err
+		},
+		"CreateTeamsProxyEndpoint": func(ctx context.Context) error {
+			_, err := client.CreateTeamsProxyEndpoint(ctx, testAccountID, TeamsProxyEndpoint{Name: "endpoint"})
+			return err
+		},
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			// retries with a long backoff would delay the error if a
+			// cancelled request was retried
+			setup(UsingRetryPolicy(3, 10, 10))
+			defer teardown()
+
+			var requests int32
+			mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&requests, 1)
+				// the server only notices the client going away once
+				// the request body has been read
+				_, _ = ioutil.ReadAll(r.Body)
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			time.AfterFunc(50*time.Millisecond, cancel)
+
+			start := time.Now()
+			err := call(ctx)
+
+			assert.True(t, errors.Is(err, context.Canceled), "expected context.Canceled, got %v", err)
+			assert.Less(t, int64(time.Since(start)), int64(time.Second))
+			assert.Equal(t, int32(1), atomic.LoadInt32(&requests))
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTeamsCatalogCacheTTL is a suitable lifetime for cached Gateway
+// catalogs, which rarely change.
+const DefaultTeamsCatalogCacheTTL = time.Hour
+
+// teamsCatalogCache holds the Gateway application and category catalogs per
+// account so that building many rules doesn't fetch them every time.
+type teamsCatalogCache struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	accounts map[string]*teamsCatalogCacheEntry
+}
+
+type teamsCatalogCacheEntry struct {
+	applications          []TeamsApplication
+	applicationsFetchedAt time.Time
+	categories            []TeamsCategory
+	categoriesFetchedAt   time.Time
+}
+
+func newTeamsCatalogCache(ttl time.Duration) *teamsCatalogCache {
+	return &teamsCatalogCache{
+		ttl:      ttl,
+		accounts: make(map[string]*teamsCatalogCacheEntry),
+	}
+}
+
+func (c *teamsCatalogCache) entry(accountID string) *teamsCatalogCacheEntry {
+	e, ok := c.accounts[accountID]
+	if !ok {
+		e = &teamsCatalogCacheEntry{}
+		c.accounts[accountID] = e
+	}
+	return e
+}
+
+func (c *teamsCatalogCache) fresh(fetchedAt time.Time) bool {
+	return !fetchedAt.IsZero() && time.Since(fetchedAt) < c.ttl
+}
+
+// teamsApplications returns the application catalog of the account, from the
+// catalog cache when it is enabled and fresh.
+func (api *API) teamsApplications(ctx context.Context, accountID string) ([]TeamsApplication, error) {
+	c := api.teamsCatalogCache
+	if c == nil {
+		return api.TeamsApplications(ctx, accountID)
+	}
+
+	c.mu.Lock()
+	e := c.entry(accountID)
+	if c.fresh(e.applicationsFetchedAt) {
+		applications := e.applications
+		c.mu.Unlock()
+		return applications, nil
+	}
+	c.mu.Unlock()
+
+	// the catalog is fetched without holding the lock so that a slow
+	// request doesn't block lookups for other accounts
+	applications, err := api.TeamsApplications(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	e = c.entry(accountID)
+	e.applications = applications
+	e.applicationsFetchedAt = time.Now()
+	c.mu.Unlock()
+
+	return applications, nil
+}
+
+// teamsCategories returns the content categories of the account, from the
+// catalog cache when it is enabled and fresh.
+func (api *API) teamsCategories(ctx context.Context, accountID string) ([]TeamsCategory, error) {
+	c := api.teamsCatalogCache
+	if c == nil {
+		return api.TeamsCategories(ctx, accountID)
+	}
+
+	c.mu.Lock()
+	e := c.entry(accountID)
+	if c.fresh(e.categoriesFetchedAt) {
+		categories := e.categories
+		c.mu.Unlock()
+		return categories, nil
+	}
+	c.mu.Unlock()
+
+	categories, err := api.TeamsCategories(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	e = c.entry(accountID)
+	e.categories = categories
+	e.categoriesFetchedAt = time.Now()
+	c.mu.Unlock()
+
+	return categories, nil
+}
+
+// TeamsApplicationByName returns the application or application type of the
+// Gateway catalog named name, compared case insensitively. The catalog is
+// cached when the client is created with UsingTeamsCatalogCache, otherwise
+// it is fetched on every call.
+func (api *API) TeamsApplicationByName(ctx context.Context, accountID, name string) (TeamsApplication, error) {
+	applications, err := api.teamsApplications(ctx, accountID)
+	if err != nil {
+		return TeamsApplication{}, err
+	}
+
+	for _, application := range applications {
+		if strings.EqualFold(application.Name, name) {
+			return application, nil
+		}
+	}
+
+	return TeamsApplication{}, fmt.Errorf("unknown application %q", name)
+}
+
+// InvalidateTeamsCatalogCache drops the cached Gateway catalogs of
+// accountID, or of every account when accountID is empty, so that they are
+// fetched again on next use. It does nothing when the cache isn't enabled.
+func (api *API) InvalidateTeamsCatalogCache(accountID string) {
+	c := api.teamsCatalogCache
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if accountID == "" {
+		c.accounts = make(map[string]*teamsCatalogCacheEntry)
+		return
+	}
+	delete(c.accounts, accountID)
+}
@@ -0,0 +1,124 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const teamsCatalogCacheApplications = `{
+	"success": true,
+	"errors": [],
+	"messages": [],
+	"result": [
+		{"id": 16, "name": "File Sharing", "description": "File sharing applications"},
+		{"id": 519, "name": "Google Drive", "application_type_id": 16}
+	],
+	"result_info": {"page": 1, "per_page": 50, "total_pages": 1, "count": 2, "total_count": 2}
+}`
+
+func TestTeamsApplicationByName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/app_types", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, teamsCatalogCacheApplications)
+	})
+
+	application, err := client.TeamsApplicationByName(context.Background(), testAccountID, "google drive")
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsApplication{ID: 519, Name: "Google Drive", Type: TeamsApplicationKindApplication, ApplicationTypeID: 16}, application)
+	}
+
+	_, err = client.TeamsApplicationByName(context.Background(), testAccountID, "Dropbox")
+	assert.EqualError(t, err, `unknown application "Dropbox"`)
+
+	// without the cache every lookup fetches the catalog
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
+
+func TestTeamsCatalogCache(t *testing.T) {
+	setup(UsingTeamsCatalogCache(time.Hour))
+	defer teardown()
+
+	var applicationRequests, categoryRequests int32
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/app_types", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&applicationRequests, 1)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, teamsCatalogCacheApplications)
+	})
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/categories", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&categoryRequests, 1)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": 1, "name": "Ads", "class": "free"}]
+		}`)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := client.TeamsApplicationByName(context.Background(), testAccountID, "File Sharing")
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	// concurrent lookups racing on an empty cache may each fetch once, later
+	// lookups are served from the cache
+	fetched := atomic.LoadInt32(&applicationRequests)
+	_, err := client.TeamsApplicationByName(context.Background(), testAccountID, "Google Drive")
+	assert.NoError(t, err)
+	assert.Equal(t, fetched, atomic.LoadInt32(&applicationRequests))
+
+	for i := 0; i < 2; i++ {
+		_, err = client.NewTeamsCategoryBlockRule(context.Background(), testAccountID, "block ads", []string{"Ads"})
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&categoryRequests))
+
+	client.InvalidateTeamsCatalogCache(testAccountID)
+
+	_, err = client.TeamsApplicationByName(context.Background(), testAccountID, "Google Drive")
+	assert.NoError(t, err)
+	assert.Equal(t, fetched+1, atomic.LoadInt32(&applicationRequests))
+
+	_, err = client.NewTeamsCategoryBlockRule(context.Background(), testAccountID, "block ads", []string{"Ads"})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&categoryRequests))
+}
+
+func TestTeamsCatalogCacheExpiry(t *testing.T) {
+	setup(UsingTeamsCatalogCache(time.Millisecond))
+	defer teardown()
+
+	var requests int32
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/app_types", func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, teamsCatalogCacheApplications)
+	})
+
+	_, err := client.TeamsApplicationByName(context.Background(), testAccountID, "Google Drive")
+	assert.NoError(t, err)
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = client.TeamsApplicationByName(context.Background(), testAccountID, "Google Drive")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+}
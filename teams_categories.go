@@ -0,0 +1,104 @@
+package cloudflare
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// TeamsCategoryClass is the license class required to filter on a category.
+type TeamsCategoryClass = string
+
+const (
+	TeamsCategoryClassFree    TeamsCategoryClass = "free"
+	TeamsCategoryClassPremium TeamsCategoryClass = "premium"
+)
+
+// TeamsCategory represents a Gateway content category. Top level categories
+// contain their subcategories.
+type TeamsCategory struct {
+	ID            int                `json:"id"`
+	Name          string             `json:"name"`
+	Description   string             `json:"description,omitempty"`
+	Class         TeamsCategoryClass `json:"class,omitempty"`
+	Subcategories []TeamsCategory    `json:"subcategories,omitempty"`
+}
+
+// TeamsCategoriesResponse is the API response, containing an array of
+// categories.
+type TeamsCategoriesResponse struct {
+	Response
+	ResultInfo `json:"result_info"`
+	Result     []TeamsCategory `json:"result"`
+}
+
+// TeamsCategories returns the Gateway content categories.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-gateway-categories-list-categories
+func (api *API) TeamsCategories(ctx context.Context, accountID string) ([]TeamsCategory, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/categories", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []TeamsCategory{}, err
+	}
+
+	var teamsCategoriesResponse TeamsCategoriesResponse
+	err = json.Unmarshal(res, &teamsCategoriesResponse)
+	if err != nil {
+		return []TeamsCategory{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsCategoriesResponse.Result, nil
+}
+
+// NewTeamsCategoryBlockRule returns an enabled DNS rule blocking the content
+// categories named categoryNames. Names are resolved to category IDs with
+// TeamsCategories, through the catalog cache when it is enabled, and may
+// name top level categories or subcategories.
+func (api *API) NewTeamsCategoryBlockRule(ctx context.Context, accountID, name string, categoryNames []string) (TeamsRule, error) {
+	if len(categoryNames) == 0 {
+		return TeamsRule{}, fmt.Errorf("at least one category name is required")
+	}
+
+	categories, err := api.teamsCategories(ctx, accountID)
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
+	ids := make(map[string]int)
+	var walk func([]TeamsCategory)
+	walk = func(categories []TeamsCategory) {
+		for _, category := range categories {
+			ids[category.Name] = category.ID
+			walk(category.Subcategories)
+		}
+	}
+	walk(categories)
+
+	values := make([]string, 0, len(categoryNames))
+	for _, categoryName := range categoryNames {
+		id, ok := ids[categoryName]
+		if !ok {
+			valid := make([]string, 0, len(ids))
+			for n := range ids {
+				valid = append(valid, n)
+			}
+			sort.Strings(valid)
+			return TeamsRule{}, fmt.Errorf("unknown category %q, must be one of %s", categoryName, strings.Join(valid, ", "))
+		}
+		values = append(values, strconv.Itoa(id))
+	}
+
+	return TeamsRule{
+		Name:    name,
+		Enabled: true,
+		Action:  Block,
+		Filters: []TeamsFilterType{DnsFilter},
+		Traffic: fmt.Sprintf("any(dns.content_category[*] in {%s})", strings.Join(values, " ")),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsCategories(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": 21,
+					"name": "Security Threats",
+					"description": "Sites that pose a security threat.",
+					"class": "free",
+					"subcategories": [
+						{"id": 68, "name": "Anonymizer", "class": "free"},
+						{"id": 80, "name": "Malware", "class": "free"},
+						{"id": 83, "name": "Phishing", "class": "premium"}
+					]
+				},
+				{"id": 7, "name": "Entertainment", "class": "free"}
+			]
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/categories", handler)
+
+	want := []TeamsCategory{
+		{
+			ID:          21,
+			Name:        "Security Threats",
+			Description: "Sites that pose a security threat.",
+			Class:       TeamsCategoryClassFree,
+			Subcategories: []TeamsCategory{
+				{ID: 68, Name: "Anonymizer", Class: TeamsCategoryClassFree},
+				{ID: 80, Name: "Malware", Class: TeamsCategoryClassFree},
+				{ID: 83, Name: "Phishing", Class: TeamsCategoryClassPremium},
+			},
+		},
+		{ID: 7, Name: "Entertainment", Class: TeamsCategoryClassFree},
+	}
+
+	actual, err := client.TeamsCategories(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestNewTeamsCategoryBlockRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": 21,
+					"name": "Security Threats",
+					"subcategories": [
+						{"id": 80, "name": "Malware"},
+						{"id": 83, "name": "Phishing"}
+					]
+				},
+				{"id": 7, "name": "Entertainment"}
+			]
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/categories", handler)
+
+	actual, err := client.NewTeamsCategoryBlockRule(context.Background(), testAccountID, "block threats", []string{"Malware", "Phishing", "Entertainment"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{
+			Name:    "block threats",
+			Enabled: true,
+			Action:  Block,
+			Filters: []TeamsFilterType{DnsFilter},
+			Traffic: "any(dns.content_category[*] in {80 83 7})",
+		}, actual)
+	}
+
+	_, err = client.NewTeamsCategoryBlockRule(context.Background(), testAccountID, "block", []string{"Gambling"})
+	assert.EqualError(t, err, `unknown category "Gambling", must be one of Entertainment, Malware, Phishing, Security Threats`)
+}
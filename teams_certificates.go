@@ -0,0 +1,333 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrMissingCertificateID = errors.New("required missing certificate ID")
+
+	// ErrTeamsCertificateExpired is returned by ValidateTeamsCACertificate
+	// for a CA certificate that is otherwise valid but already expired.
+	ErrTeamsCertificateExpired = errors.New("certificate has expired")
+
+	// ErrTeamsCertificateInUse is returned by TeamsDeleteCertificate for a
+	// certificate that is still in use.
+	ErrTeamsCertificateInUse = errors.New("certificate is in use")
+)
+
+// Binding statuses of a certificate that is or is being bound to the edge.
+const (
+	teamsCertificateBindingActive            = "active"
+	teamsCertificateBindingPendingDeployment = "pending_deployment"
+)
+
+// TeamsCertificate represents a root certificate that Gateway can use for
+// TLS inspection.
+type TeamsCertificate struct {
+	ID            string     `json:"id,omitempty"`
+	Certificate   string     `json:"certificate,omitempty"`
+	Fingerprint   string     `json:"fingerprint,omitempty"`
+	InUse         bool       `json:"in_use,omitempty"`
+	Type          string     `json:"type,omitempty"`
+	BindingStatus string     `json:"binding_status,omitempty"`
+	ExpiresOn     *time.Time `json:"expires_on,omitempty"`
+	UploadedOn    *time.Time `json:"uploaded_on,omitempty"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	UpdatedAt     *time.Time `json:"updated_at,omitempty"`
+}
+
+// TeamsCertificateCreateRequest is used to generate a new Gateway managed
+// certificate.
+type TeamsCertificateCreateRequest struct {
+	ValidityPeriodDays int `json:"validity_period_days,omitempty"`
+}
+
+// TeamsCertificatesResponse is the API response, containing an array of
+// certificates.
+type TeamsCertificatesResponse struct {
+	Response
+	ResultInfo `json:"result_info"`
+	Result     []TeamsCertificate `json:"result"`
+}
+
+// TeamsCertificateResponse is the API response, containing a single
+// certificate.
+type TeamsCertificateResponse struct {
+	Response
+	Result TeamsCertificate `json:"result"`
+}
+
+// TeamsAccountCertificates returns all Gateway certificates within an account.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-list-zero-trust-certificates
+func (api *API) TeamsAccountCertificates(ctx context.Context, accountID string) ([]TeamsCertificate, error) {
+	certificates, _, err := api.TeamsAccountCertificatesWithInfo(ctx, accountID)
+	return certificates, err
+}
+
+// TeamsAccountCertificatesWithInfo returns all Gateway certificates within an
+// account along with the result info.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-list-zero-trust-certificates
+func (api *API) TeamsAccountCertificatesWithInfo(ctx context.Context, accountID string) ([]TeamsCertificate, ResultInfo, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []TeamsCertificate{}, ResultInfo{}, err
+	}
+
+	var teamsCertificatesResponse TeamsCertificatesResponse
+	err = json.Unmarshal(res, &teamsCertificatesResponse)
+	if err != nil {
+		return []TeamsCertificate{}, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsCertificatesResponse.Result, teamsCertificatesResponse.ResultInfo, nil
+}
+
+// TeamsAccountCertificatesAll returns all Gateway certificates within an
+// account, following the pagination until every page has been fetched. If a
+// page after the first one fails, the certificates fetched so far are
+// returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-list-zero-trust-certificates
+func (api *API) TeamsAccountCertificatesAll(ctx context.Context, accountID string) ([]TeamsCertificate, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates", AccountRouteRoot, accountID)
+
+	var certificates []TeamsCertificate
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, PaginationOptions{Page: params.Page, PerPage: params.PerPage}), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var teamsCertificatesResponse TeamsCertificatesResponse
+		err = json.Unmarshal(res, &teamsCertificatesResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		certificates = append(certificates, teamsCertificatesResponse.Result...)
+		return len(teamsCertificatesResponse.Result), teamsCertificatesResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []TeamsCertificate{}, err
+	}
+
+	return certificates, err
+}
+
+// TeamsAccountCertificate returns a single Gateway certificate.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-zero-trust-certificate-details
+func (api *API) TeamsAccountCertificate(ctx context.Context, accountID, certificateID string) (TeamsCertificate, error) {
+	if certificateID == "" {
+		return TeamsCertificate{}, ErrMissingCertificateID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates/%s", AccountRouteRoot, accountID, certificateID)
+
+	return api.teamsCertificateRequest(ctx, http.MethodGet, uri, nil)
+}
+
+// TeamsCreateCertificate generates a new Gateway managed certificate.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-create-zero-trust-certificate
+func (api *API) TeamsCreateCertificate(ctx context.Context, accountID string, certificate TeamsCertificateCreateRequest) (TeamsCertificate, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates", AccountRouteRoot, accountID)
+
+	return api.teamsCertificateRequest(ctx, http.MethodPost, uri, certificate)
+}
+
+// ValidateTeamsCACertificate checks that pemData holds a CA certificate
+// usable as a custom root certificate for TLS inspection, see
+// TeamsCustomCertificate, and returns when it expires. Gateway managed
+// certificates are generated by TeamsCreateCertificate, which takes no
+// certificate, so custom certificates are to be checked before they are
+// uploaded. A certificate that is already expired is returned with its
+// expiry and ErrTeamsCertificateExpired so that callers can decide whether
+// to go ahead.
+func ValidateTeamsCACertificate(pemData []byte) (time.Time, error) {
+	block, _ := pem.Decode(pemData)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return time.Time{}, errors.New("no PEM encoded certificate found")
+	}
+
+	certificate, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid certificate: %w", err)
+	}
+
+	if !certificate.BasicConstraintsValid || !certificate.IsCA {
+		return time.Time{}, fmt.Errorf("certificate %q is not a CA certificate", certificate.Subject.CommonName)
+	}
+
+	if time.Now().After(certificate.NotAfter) {
+		return certificate.NotAfter, fmt.Errorf("%w on %s", ErrTeamsCertificateExpired, certificate.NotAfter.Format(time.RFC3339))
+	}
+
+	return certificate.NotAfter, nil
+}
+
+// TeamsGenerateCertificate generates a new Gateway managed root certificate
+// with the default validity period. Use TeamsCreateCertificate to choose
+// the validity period.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-create-zero-trust-certificate
+func (api *API) TeamsGenerateCertificate(ctx context.Context, accountID string) (TeamsCertificate, error) {
+	return api.TeamsCreateCertificate(ctx, accountID, TeamsCertificateCreateRequest{})
+}
+
+// TeamsActivateCertificate binds a certificate to the edge so that it can be
+// used for TLS inspection.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-activate-zero-trust-certificate
+func (api *API) TeamsActivateCertificate(ctx context.Context, accountID, certificateID string) (TeamsCertificate, error) {
+	if certificateID == "" {
+		return TeamsCertificate{}, ErrMissingCertificateID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates/%s/activate", AccountRouteRoot, accountID, certificateID)
+
+	return api.teamsCertificateRequest(ctx, http.MethodPost, uri, nil)
+}
+
+// TeamsDeactivateCertificate unbinds a certificate from the edge.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-deactivate-zero-trust-certificate
+func (api *API) TeamsDeactivateCertificate(ctx context.Context, accountID, certificateID string) (TeamsCertificate, error) {
+	if certificateID == "" {
+		return TeamsCertificate{}, ErrMissingCertificateID
+	}
+
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates/%s/deactivate", AccountRouteRoot, accountID, certificateID)
+
+	return api.teamsCertificateRequest(ctx, http.MethodPost, uri, nil)
+}
+
+// TeamsDeleteCertificate deletes a Gateway certificate. Deleting the
+// certificate used for TLS inspection breaks inspection for the whole
+// account, so unless force is set the certificate is fetched first and a
+// certificate that is in use or bound to the edge isn't deleted;
+// ErrTeamsCertificateInUse is returned instead. Such a certificate is to be
+// deactivated with TeamsDeactivateCertificate before it's deleted.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-certificates-delete-zero-trust-certificate
+func (api *API) TeamsDeleteCertificate(ctx context.Context, accountID, certificateID string, force bool) error {
+	if certificateID == "" {
+		return ErrMissingCertificateID
+	}
+
+	if !force {
+		certificate, err := api.TeamsAccountCertificate(ctx, accountID, certificateID)
+		if err != nil {
+			return err
+		}
+
+		if certificate.InUse || certificate.BindingStatus == teamsCertificateBindingActive || certificate.BindingStatus == teamsCertificateBindingPendingDeployment {
+			return fmt.Errorf("%w: certificate %s is used for TLS inspection (binding status %q), deactivate it with TeamsDeactivateCertificate before deleting it or delete it with force", ErrTeamsCertificateInUse, certificateID, certificate.BindingStatus)
+		}
+	}
+
+	uri := fmt.Sprintf("/%s/%s/gateway/certificates/%s", AccountRouteRoot, accountID, certificateID)
+
+	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TeamsEnableInspection bootstraps TLS inspection on an account: it
+// generates a Gateway managed certificate, activates it and turns on TLS
+// decryption in the account configuration. The activated certificate is
+// returned. If a later step fails the certificate generated so far is
+// returned along with the error so that it can be cleaned up or retried.
+func (api *API) TeamsEnableInspection(ctx context.Context, accountID string) (TeamsCertificate, error) {
+	certificate, err := api.TeamsGenerateCertificate(ctx, accountID)
+	if err != nil {
+		return TeamsCertificate{}, err
+	}
+
+	activated, err := api.TeamsActivateCertificate(ctx, accountID, certificate.ID)
+	if err != nil {
+		return certificate, fmt.Errorf("activating certificate %s: %w", certificate.ID, err)
+	}
+
+	_, err = api.TeamsAccountPatchConfiguration(ctx, accountID, TeamsAccountSettingsPatch{
+		TLSDecrypt: &TeamsSettingTogglePatch{Enabled: BoolPtr(true)},
+	})
+	if err != nil {
+		return activated, fmt.Errorf("enabling TLS decryption: %w", err)
+	}
+
+	return activated, nil
+}
+
+func (api *API) teamsCertificateRequest(ctx context.Context, method, uri string, params interface{}) (TeamsCertificate, error) {
+	res, err := api.makeRequestContext(ctx, method, uri, params)
+	if err != nil {
+		return TeamsCertificate{}, err
+	}
+
+	var teamsCertificateResponse TeamsCertificateResponse
+	err = json.Unmarshal(res, &teamsCertificateResponse)
+	if err != nil {
+		return TeamsCertificate{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsCertificateResponse.Result, nil
+}
+
+// TeamsCertificatesOption is a functional option for filtering certificates.
+type TeamsCertificatesOption func(opt *teamsCertificatesOption)
+
+type teamsCertificatesOption struct {
+	includeUnused bool
+}
+
+// WithUnusedTeamsCertificates includes certificates that aren't in use by
+// Gateway.
+func WithUnusedTeamsCertificates() TeamsCertificatesOption {
+	return func(opt *teamsCertificatesOption) {
+		opt.includeUnused = true
+	}
+}
+
+// TeamsExpiringCertificates returns the Gateway certificates in use that
+// expire within the given duration from now, including those that already
+// expired. Certificates without an expiry are skipped.
+func (api *API) TeamsExpiringCertificates(ctx context.Context, accountID string, within time.Duration, opts ...TeamsCertificatesOption) ([]TeamsCertificate, error) {
+	opt := teamsCertificatesOption{}
+	for _, of := range opts {
+		of(&opt)
+	}
+
+	certificates, err := api.TeamsAccountCertificatesAll(ctx, accountID)
+	if err != nil {
+		return []TeamsCertificate{}, err
+	}
+
+	cutoff := time.Now().Add(within)
+	expiring := []TeamsCertificate{}
+	for _, certificate := range certificates {
+		if !certificate.InUse && !opt.includeUnused {
+			continue
+		}
+		if certificate.ExpiresOn != nil && certificate.ExpiresOn.Before(cutoff) {
+			expiring = append(expiring, certificate)
+		}
+	}
+
+	return expiring, nil
+}
@@ -0,0 +1,563 @@
+package cloudflare
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testTeamsCertificateID = "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"
+
+func TestTeamsAccountCertificates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}]
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", handler)
+
+	actual, err := client.TeamsAccountCertificates(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsCertificate{want}, actual)
+	}
+}
+
+func TestTeamsAccountCertificatesWithInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}],
+			"result_info": {"page": 1, "per_page": 20, "count": 1, "total_count": 1}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", handler)
+
+	actual, resultInfo, err := client.TeamsAccountCertificatesWithInfo(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsCertificate{want}, actual)
+		assert.Equal(t, 1, resultInfo.Total)
+	}
+}
+
+func TestTeamsAccountCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates/"+testTeamsCertificateID, handler)
+
+	actual, err := client.TeamsAccountCertificate(context.Background(), testAccountID, testTeamsCertificateID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsCreateCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", handler)
+
+	actual, err := client.TeamsCreateCertificate(context.Background(), testAccountID, TeamsCertificateCreateRequest{ValidityPeriodDays: 1826})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsGenerateCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", handler)
+
+	actual, err := client.TeamsGenerateCertificate(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsEnableInspection(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var steps []string
+	certificateHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		steps = append(steps, r.URL.Path)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", certificateHandler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates/"+testTeamsCertificateID+"/activate", certificateHandler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		steps = append(steps, r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"settings":{"tls_decrypt":{"enabled":true}}}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings": {"tls_decrypt": {"enabled": true}}}
+		}`)
+	})
+
+	actual, err := client.TeamsEnableInspection(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+		assert.Equal(t, []string{
+			"/accounts/" + testAccountID + "/gateway/certificates",
+			"/accounts/" + testAccountID + "/gateway/certificates/" + testTeamsCertificateID + "/activate",
+			"/accounts/" + testAccountID + "/gateway/configuration",
+		}, steps)
+	}
+}
+
+func TestTeamsActivateCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates/"+testTeamsCertificateID+"/activate", handler)
+
+	actual, err := client.TeamsActivateCertificate(context.Background(), testAccountID, testTeamsCertificateID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsDeactivateCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+				"certificate": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+				"fingerprint": "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+				"in_use": true,
+				"type": "gateway_managed",
+				"binding_status": "active",
+				"expires_on": "2027-06-14T00:00:00Z",
+				"uploaded_on": "2022-06-14T00:00:00Z"
+			}
+		}`)
+	}
+
+	expiresOn, _ := time.Parse(time.RFC3339, "2027-06-14T00:00:00Z")
+	uploadedOn, _ := time.Parse(time.RFC3339, "2022-06-14T00:00:00Z")
+
+	want := TeamsCertificate{
+		ID:            testTeamsCertificateID,
+		Certificate:   "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+		Fingerprint:   "E6:A7:0B:2A:80:0A:93:A4:0E:6C:B5:2B:1F:D3:4E:64:5C:41:0E:55",
+		InUse:         true,
+		Type:          "gateway_managed",
+		BindingStatus: "active",
+		ExpiresOn:     &expiresOn,
+		UploadedOn:    &uploadedOn,
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates/"+testTeamsCertificateID+"/deactivate", handler)
+
+	actual, err := client.TeamsDeactivateCertificate(context.Background(), testAccountID, testTeamsCertificateID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsDeleteCertificate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	inUse, bindingStatus := true, "active"
+	deleted := false
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": {"id": "%s", "in_use": %t, "binding_status": "%s"}
+			}`, testTeamsCertificateID, inUse, bindingStatus)
+			return
+		}
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		deleted = true
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": null
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates/"+testTeamsCertificateID, handler)
+
+	err := client.TeamsDeleteCertificate(context.Background(), testAccountID, testTeamsCertificateID, false)
+	assert.ErrorIs(t, err, ErrTeamsCertificateInUse)
+	assert.False(t, deleted)
+
+	err = client.TeamsDeleteCertificate(context.Background(), testAccountID, testTeamsCertificateID, true)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	inUse, bindingStatus, deleted = false, "inactive", false
+	err = client.TeamsDeleteCertificate(context.Background(), testAccountID, testTeamsCertificateID, false)
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	err = client.TeamsDeleteCertificate(context.Background(), testAccountID, "", false)
+	assert.Equal(t, ErrMissingCertificateID, err)
+}
+
+func TestTeamsExpiringCertificates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	soon := time.Now().Add(48 * time.Hour).UTC().Truncate(time.Second)
+	later := time.Now().Add(90 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": "soon", "in_use": true, "expires_on": "%[1]s"},
+					{"id": "later", "in_use": true, "expires_on": "%[2]s"}
+				],
+				"result_info": {"page": 1, "per_page": 2, "count": 2, "total_count": 4, "total_pages": 2}
+			}`, soon.Format(time.RFC3339), later.Format(time.RFC3339))
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "soon-unused", "in_use": false, "expires_on": "%[1]s"},
+				{"id": "no-expiry", "in_use": true}
+			],
+			"result_info": {"page": 2, "per_page": 2, "count": 2, "total_count": 4, "total_pages": 2}
+		}`, soon.Format(time.RFC3339))
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/certificates", handler)
+
+	actual, err := client.TeamsExpiringCertificates(context.Background(), testAccountID, 7*24*time.Hour)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsCertificate{{ID: "soon", InUse: true, ExpiresOn: &soon}}, actual)
+	}
+
+	actual, err = client.TeamsExpiringCertificates(context.Background(), testAccountID, 7*24*time.Hour, WithUnusedTeamsCertificates())
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 2)
+		assert.Equal(t, "soon-unused", actual[1].ID)
+	}
+}
+
+func testTeamsCACertificatePEM(t *testing.T, isCA bool, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Example Root"},
+		NotBefore:             notAfter.Add(-365 * 24 * time.Hour),
+		NotAfter:              notAfter,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
+
+func TestValidateTeamsCACertificate(t *testing.T) {
+	notAfter := time.Now().Add(30 * 24 * time.Hour).UTC().Truncate(time.Second)
+
+	expiry, err := ValidateTeamsCACertificate(testTeamsCACertificatePEM(t, true, notAfter))
+	if assert.NoError(t, err) {
+		assert.True(t, notAfter.Equal(expiry))
+	}
+
+	_, err = ValidateTeamsCACertificate(testTeamsCACertificatePEM(t, false, notAfter))
+	assert.EqualError(t, err, `certificate "Example Root" is not a CA certificate`)
+
+	expired := time.Now().Add(-24 * time.Hour).UTC().Truncate(time.Second)
+	expiry, err = ValidateTeamsCACertificate(testTeamsCACertificatePEM(t, true, expired))
+	assert.True(t, errors.Is(err, ErrTeamsCertificateExpired))
+	assert.True(t, expired.Equal(expiry))
+
+	_, err = ValidateTeamsCACertificate([]byte("not a certificate"))
+	assert.EqualError(t, err, "no PEM encoded certificate found")
+}
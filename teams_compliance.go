@@ -0,0 +1,154 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TeamsComplianceReport summarises the security relevant Gateway settings of
+// an account for auditing.
+type TeamsComplianceReport struct {
+	AccountID                string
+	TLSDecryptEnabled        bool
+	FIPSTLSEnabled           bool
+	AntivirusDownloadEnabled bool
+	AntivirusUploadEnabled   bool
+	AntivirusFailClosed      bool
+	ActivityLogEnabled       bool
+	LoggingByRuleType        map[TeamsRuleType]TeamsAccountLoggingConfiguration
+	RedactPII                bool
+	RuleCount                int
+	EnabledRuleCount         int
+	DevicePostureRuleCount   int
+
+	// DisabledFeatures lists the security features that are turned off.
+	DisabledFeatures []string
+}
+
+// TeamsComplianceReport builds a TeamsComplianceReport from the account
+// configuration, logging settings, Gateway rules and device posture rules.
+func (api *API) TeamsComplianceReport(ctx context.Context, accountID string) (TeamsComplianceReport, error) {
+	config, err := api.TeamsAccountConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsComplianceReport{}, err
+	}
+
+	logging, err := api.TeamsAccountLoggingConfiguration(ctx, accountID)
+	if err != nil {
+		return TeamsComplianceReport{}, err
+	}
+
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return TeamsComplianceReport{}, err
+	}
+
+	postureRules, err := api.DevicePostureRulesAll(ctx, accountID)
+	if err != nil {
+		return TeamsComplianceReport{}, err
+	}
+
+	report := TeamsComplianceReport{
+		AccountID:              accountID,
+		LoggingByRuleType:      logging.LoggingSettingsByRuleType,
+		RedactPII:              logging.RedactPii,
+		RuleCount:              len(rules),
+		DevicePostureRuleCount: len(postureRules),
+	}
+
+	settings := config.Settings
+	if settings.TLSDecrypt != nil {
+		report.TLSDecryptEnabled = settings.TLSDecrypt.Enabled
+	}
+	if settings.FIPS != nil {
+		report.FIPSTLSEnabled = settings.FIPS.TLS
+	}
+	if settings.Antivirus != nil {
+		report.AntivirusDownloadEnabled = settings.Antivirus.EnabledDownloadPhase
+		report.AntivirusUploadEnabled = settings.Antivirus.EnabledUploadPhase
+		report.AntivirusFailClosed = settings.Antivirus.FailClosed
+	}
+	if settings.ActivityLog != nil {
+		report.ActivityLogEnabled = settings.ActivityLog.Enabled
+	}
+
+	for _, rule := range rules {
+		if rule.Enabled {
+			report.EnabledRuleCount++
+		}
+	}
+
+	checks := []struct {
+		name    string
+		enabled bool
+	}{
+		{"TLS decryption", report.TLSDecryptEnabled},
+		{"FIPS TLS", report.FIPSTLSEnabled},
+		{"antivirus download scanning", report.AntivirusDownloadEnabled},
+		{"antivirus upload scanning", report.AntivirusUploadEnabled},
+		{"activity logging", report.ActivityLogEnabled},
+	}
+	for _, check := range checks {
+		if !check.enabled {
+			report.DisabledFeatures = append(report.DisabledFeatures, check.name)
+		}
+	}
+
+	for _, ruleType := range []TeamsRuleType{TeamsHttpRuleType, TeamsDnsRuleType, TeamsL4RuleType} {
+		if l, ok := report.LoggingByRuleType[ruleType]; !ok || (!l.LogAll && !l.LogBlocks) {
+			report.DisabledFeatures = append(report.DisabledFeatures, ruleType+" logging")
+		}
+	}
+
+	return report, nil
+}
+
+// Markdown renders the report as a Markdown document.
+func (r TeamsComplianceReport) Markdown() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Gateway compliance report for %s\n\n", r.AccountID)
+	b.WriteString("| Setting | Value |\n")
+	b.WriteString("| --- | --- |\n")
+	fmt.Fprintf(&b, "| TLS decryption | %s |\n", enabledString(r.TLSDecryptEnabled))
+	fmt.Fprintf(&b, "| FIPS TLS | %s |\n", enabledString(r.FIPSTLSEnabled))
+	fmt.Fprintf(&b, "| Antivirus download scanning | %s |\n", enabledString(r.AntivirusDownloadEnabled))
+	fmt.Fprintf(&b, "| Antivirus upload scanning | %s |\n", enabledString(r.AntivirusUploadEnabled))
+	fmt.Fprintf(&b, "| Antivirus fail closed | %s |\n", enabledString(r.AntivirusFailClosed))
+	fmt.Fprintf(&b, "| Activity logging | %s |\n", enabledString(r.ActivityLogEnabled))
+	fmt.Fprintf(&b, "| PII redaction | %s |\n", enabledString(r.RedactPII))
+	fmt.Fprintf(&b, "| Gateway rules | %d (%d enabled) |\n", r.RuleCount, r.EnabledRuleCount)
+	fmt.Fprintf(&b, "| Device posture rules | %d |\n", r.DevicePostureRuleCount)
+
+	b.WriteString("\n## Logging\n\n")
+	b.WriteString("| Rule type | Log all | Log blocks |\n")
+	b.WriteString("| --- | --- | --- |\n")
+	ruleTypes := make([]string, 0, len(r.LoggingByRuleType))
+	for ruleType := range r.LoggingByRuleType {
+		ruleTypes = append(ruleTypes, ruleType)
+	}
+	sort.Strings(ruleTypes)
+	for _, ruleType := range ruleTypes {
+		l := r.LoggingByRuleType[ruleType]
+		fmt.Fprintf(&b, "| %s | %t | %t |\n", ruleType, l.LogAll, l.LogBlocks)
+	}
+
+	b.WriteString("\n## Disabled security features\n\n")
+	if len(r.DisabledFeatures) == 0 {
+		b.WriteString("None\n")
+	}
+	for _, feature := range r.DisabledFeatures {
+		fmt.Fprintf(&b, "- %s\n", feature)
+	}
+
+	return b.String()
+}
+
+func enabledString(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
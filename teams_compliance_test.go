@@ -0,0 +1,106 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsComplianceReport(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/configuration", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"settings": {
+					"antivirus": {"enabled_download_phase": true, "enabled_upload_phase": false, "fail_closed": true},
+					"tls_decrypt": {"enabled": true},
+					"fips": {"tls": false},
+					"activity_log": {"enabled": true}
+				}
+			}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/logging", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"settings_by_rule_type":{"dns":{"log_all":true,"log_blocks":true},"http":{"log_all":false,"log_blocks":true}},"redact_pii":true}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "7559a944-3dd7-41bf-b183-360a814a8c36", "name": "rule1", "enabled": true, "action": "block"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "9ae57318-f32e-46b3-b889-48dd6dcc49af", "name": "rule2", "enabled": false, "action": "allow"}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/posture", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", "type": "file", "name": "My rule name"}]
+		}`)
+	})
+
+	want := TeamsComplianceReport{
+		AccountID:                testAccountID,
+		TLSDecryptEnabled:        true,
+		AntivirusDownloadEnabled: true,
+		AntivirusFailClosed:      true,
+		ActivityLogEnabled:       true,
+		LoggingByRuleType: map[TeamsRuleType]TeamsAccountLoggingConfiguration{
+			TeamsDnsRuleType:  {LogAll: true, LogBlocks: true},
+			TeamsHttpRuleType: {LogAll: false, LogBlocks: true},
+		},
+		RedactPII:              true,
+		RuleCount:              2,
+		EnabledRuleCount:       1,
+		DevicePostureRuleCount: 1,
+		DisabledFeatures:       []string{"FIPS TLS", "antivirus upload scanning", "l4 logging"},
+	}
+
+	actual, err := client.TeamsComplianceReport(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+
+		markdown := actual.Markdown()
+		assert.Contains(t, markdown, "| TLS decryption | enabled |")
+		assert.Contains(t, markdown, "| Gateway rules | 2 (1 enabled) |")
+		assert.Contains(t, markdown, "| dns | true | true |")
+		assert.Contains(t, markdown, "- l4 logging\n")
+	}
+}
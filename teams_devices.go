@@ -5,11 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
 )
 
 type TeamsDevicesList struct {
 	Response
-	Result []TeamsDeviceListItem `json:"result"`
+	ResultInfo `json:"result_info"`
+	Result     []TeamsDeviceListItem `json:"result"`
 }
 
 type TeamsDeviceDetail struct {
@@ -37,30 +42,189 @@ type TeamsDeviceListItem struct {
 	RevokedAt    string   `json:"revoked_at,omitempty"`
 }
 
+// Revoked returns whether the device has been revoked.
+func (d TeamsDeviceListItem) Revoked() bool {
+	return d.RevokedAt != ""
+}
+
+// TeamsDeviceChangeType is the kind of change observed by WatchDeviceChanges.
+type TeamsDeviceChangeType string
+
+const (
+	TeamsDeviceAdded   TeamsDeviceChangeType = "added"
+	TeamsDeviceRemoved TeamsDeviceChangeType = "removed"
+)
+
+// TeamsDeviceChange is a single device that was added to or removed from
+// an account between two polls.
+type TeamsDeviceChange struct {
+	Type   TeamsDeviceChangeType
+	Device TeamsDeviceListItem
+}
+
+// TeamsDeviceEffectiveSettings is a device along with the device settings
+// policy that applies to it.
+type TeamsDeviceEffectiveSettings struct {
+	Device TeamsDeviceListItem  `json:"device"`
+	Policy DeviceSettingsPolicy `json:"policy"`
+}
+
 type UserItem struct {
 	ID    string `json:"id,omitempty"`
 	Name  string `json:"name,omitempty"`
 	Email string `json:"email,omitempty"`
 }
 
+// TeamsDevicesListParams contains the optional filters for narrowing down
+// the devices returned by ListTeamsDevicesWithParams, and the page to fetch.
+// Devices that have never been seen match LastSeenBefore but not
+// LastSeenAfter.
+type TeamsDevicesListParams struct {
+	ResultInfo
+
+	LastSeenBefore *time.Time `url:"last_seen_before,omitempty"`
+	LastSeenAfter  *time.Time `url:"last_seen_after,omitempty"`
+	DeviceType     string     `url:"device_type,omitempty"`
+	Version        string     `url:"version,omitempty"`
+	Revoked        *bool      `url:"revoked,omitempty"`
+}
+
 // ListTeamsDevice returns all devices for a given account.
 //
 // API reference : https://api.cloudflare.com/#devices-list-devices
 func (api *API) ListTeamsDevices(ctx context.Context, accountID string) ([]TeamsDeviceListItem, error) {
-	uri := fmt.Sprintf("/%s/%s/devices", AccountRouteRoot, accountID)
+	return api.ListTeamsDevicesWithParams(ctx, accountID, TeamsDevicesListParams{})
+}
 
-	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+// ListTeamsDevicesWithParams returns the devices for a given account that
+// match the provided filters. The filters are sent as query parameters and
+// are also applied to the response in case the API ignores any of them.
+//
+// API reference : https://api.cloudflare.com/#devices-list-devices
+func (api *API) ListTeamsDevicesWithParams(ctx context.Context, accountID string, params TeamsDevicesListParams) ([]TeamsDeviceListItem, error) {
+	devices, _, err := api.TeamsDevices(ctx, accountID, params)
+	return devices, err
+}
+
+// TeamsDevices returns a page of the devices for a given account that match
+// the provided filters, along with the pagination details and total count.
+//
+// API reference : https://api.cloudflare.com/#devices-list-devices
+func (api *API) TeamsDevices(ctx context.Context, accountID string, params TeamsDevicesListParams) ([]TeamsDeviceListItem, ResultInfo, error) {
+	devices, resultInfo, err := api.teamsDevicesPage(ctx, accountID, params)
+	if err != nil {
+		return []TeamsDeviceListItem{}, ResultInfo{}, err
+	}
+
+	devices, err = filterTeamsDevices(devices, params)
+	if err != nil {
+		return []TeamsDeviceListItem{}, ResultInfo{}, err
+	}
+
+	return devices, resultInfo, nil
+}
+
+// TeamsDevicesAll returns all devices for a given account that match the
+// provided filters, following the pagination until every page has been
+// fetched. The page set in params is ignored. If a page after the first one
+// fails, the devices fetched so far are returned along with a
+// *PaginationError.
+//
+// API reference : https://api.cloudflare.com/#devices-list-devices
+func (api *API) TeamsDevicesAll(ctx context.Context, accountID string, params TeamsDevicesListParams) ([]TeamsDeviceListItem, error) {
+	var devices []TeamsDeviceListItem
+	pageErr := fetchAllPages(ctx, 50, func(page ResultInfo) (int, ResultInfo, error) {
+		params.ResultInfo = page
+		devicesPage, resultInfo, err := api.teamsDevicesPage(ctx, accountID, params)
+		devices = append(devices, devicesPage...)
+		return len(devicesPage), resultInfo, err
+	})
+	if pageErr != nil && !isPaginationError(pageErr) {
+		return []TeamsDeviceListItem{}, pageErr
+	}
+
+	devices, err := filterTeamsDevices(devices, params)
 	if err != nil {
 		return []TeamsDeviceListItem{}, err
 	}
 
+	return devices, pageErr
+}
+
+// teamsDevicesPage returns a page of the devices for a given account
+// without applying the filters of params client side, so the size of the
+// page can be used to follow the pagination.
+func (api *API) teamsDevicesPage(ctx context.Context, accountID string, params TeamsDevicesListParams) ([]TeamsDeviceListItem, ResultInfo, error) {
+	uri := buildURI(fmt.Sprintf("/%s/%s/devices", AccountRouteRoot, accountID), params)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []TeamsDeviceListItem{}, ResultInfo{}, err
+	}
+
 	var response TeamsDevicesList
 	err = json.Unmarshal(res, &response)
 	if err != nil {
-		return []TeamsDeviceListItem{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		return []TeamsDeviceListItem{}, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
 	}
 
-	return response.Result, nil
+	return response.Result, response.ResultInfo, nil
+}
+
+// filterTeamsDevices drops any devices that don't match params.
+func filterTeamsDevices(devices []TeamsDeviceListItem, params TeamsDevicesListParams) ([]TeamsDeviceListItem, error) {
+	filtered := make([]TeamsDeviceListItem, 0, len(devices))
+	for _, device := range devices {
+		if params.DeviceType != "" && !strings.EqualFold(device.DeviceType, params.DeviceType) {
+			continue
+		}
+
+		if params.Version != "" && device.Version != params.Version {
+			continue
+		}
+
+		if params.Revoked != nil && *params.Revoked != device.Revoked() {
+			continue
+		}
+
+		if params.LastSeenBefore != nil || params.LastSeenAfter != nil {
+			matches, err := teamsDeviceLastSeenMatches(device, params)
+			if err != nil {
+				return nil, err
+			}
+			if !matches {
+				continue
+			}
+		}
+
+		filtered = append(filtered, device)
+	}
+
+	return filtered, nil
+}
+
+// teamsDeviceLastSeenMatches reports whether the device was last seen
+// within the LastSeenBefore and LastSeenAfter bounds of params. A device
+// that has never been seen is treated as older than any bound.
+func teamsDeviceLastSeenMatches(device TeamsDeviceListItem, params TeamsDevicesListParams) (bool, error) {
+	if device.LastSeen == "" {
+		return params.LastSeenAfter == nil, nil
+	}
+
+	lastSeen, err := time.Parse(time.RFC3339, device.LastSeen)
+	if err != nil {
+		return false, fmt.Errorf("failed to parse last_seen of device %s: %w", device.ID, err)
+	}
+
+	if params.LastSeenBefore != nil && !lastSeen.Before(*params.LastSeenBefore) {
+		return false, nil
+	}
+
+	if params.LastSeenAfter != nil && !lastSeen.After(*params.LastSeenAfter) {
+		return false, nil
+	}
+
+	return true, nil
 }
 
 // RevokeTeamsDevice revokes device with given identifiers.
@@ -82,6 +246,25 @@ func (api *API) RevokeTeamsDevices(ctx context.Context, accountID string, device
 	return result, err
 }
 
+// UnrevokeTeamsDevices unrevokes the devices with the given identifiers.
+//
+// API reference : https://api.cloudflare.com/#devices-unrevoke-devices
+func (api *API) UnrevokeTeamsDevices(ctx context.Context, accountID string, deviceIds []string) (Response, error) {
+	uri := fmt.Sprintf("/%s/%s/devices/unrevoke", AccountRouteRoot, accountID)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, deviceIds)
+	if err != nil {
+		return Response{}, err
+	}
+
+	result := Response{}
+	if err := json.Unmarshal(res, &result); err != nil {
+		return result, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return result, err
+}
+
 // GetTeamsDeviceDetails gets device details.
 //
 // API reference : https://api.cloudflare.com/#devices-device-details
@@ -101,3 +284,186 @@ func (api *API) GetTeamsDeviceDetails(ctx context.Context, accountID string, dev
 
 	return response.Result, nil
 }
+
+// TeamsDeviceEffectiveSettings returns the device settings policy applied to
+// a single device. The API doesn't resolve policies per device, so the match
+// expressions of the enabled policies are evaluated against the device
+// details in order of precedence and the default policy applies when none
+// matches. The device details only hold the user email and the operating
+// system, so an error is returned when a policy that would be evaluated
+// matches on anything other than identity.email or os.name.
+//
+// API reference : https://api.cloudflare.com/#devices-device-details
+func (api *API) TeamsDeviceEffectiveSettings(ctx context.Context, accountID, deviceID string) (TeamsDeviceEffectiveSettings, error) {
+	device, err := api.GetTeamsDeviceDetails(ctx, accountID, deviceID)
+	if err != nil {
+		return TeamsDeviceEffectiveSettings{}, err
+	}
+
+	policies, err := api.DeviceSettingsPoliciesAll(ctx, accountID)
+	if err != nil {
+		return TeamsDeviceEffectiveSettings{}, err
+	}
+
+	sort.SliceStable(policies, func(i, j int) bool {
+		return policies[i].Precedence < policies[j].Precedence
+	})
+
+	for _, policy := range policies {
+		if policy.Default || (policy.Enabled != nil && !*policy.Enabled) {
+			continue
+		}
+
+		matches, err := teamsDeviceMatches(policy.Match, device)
+		if err != nil {
+			return TeamsDeviceEffectiveSettings{}, fmt.Errorf("error evaluating device settings policy %q: %w", policy.Name, err)
+		}
+		if matches {
+			return TeamsDeviceEffectiveSettings{Device: device, Policy: policy}, nil
+		}
+	}
+
+	for _, policy := range policies {
+		if policy.Default {
+			return TeamsDeviceEffectiveSettings{Device: device, Policy: policy}, nil
+		}
+	}
+
+	policy, err := api.DefaultDeviceSettingsPolicy(ctx, accountID)
+	if err != nil {
+		return TeamsDeviceEffectiveSettings{}, err
+	}
+
+	return TeamsDeviceEffectiveSettings{Device: device, Policy: policy}, nil
+}
+
+// teamsDeviceMatchClauseRegexp matches a clause of a device settings policy
+// match expression comparing an attribute to a string or a set of strings.
+var teamsDeviceMatchClauseRegexp = regexp.MustCompile(`^([a-z_.]+)\s+(==|in)\s+("[^"]*"|\{(?:\s*"[^"]*")*\s*\})$`)
+
+var teamsDeviceMatchValueRegexp = regexp.MustCompile(`"([^"]*)"`)
+
+// teamsDeviceMatches evaluates a device settings policy match expression made
+// of identity.email and os.name clauses joined with and and or.
+func teamsDeviceMatches(match string, device TeamsDeviceListItem) (bool, error) {
+	if strings.ContainsAny(match, "()") {
+		return false, fmt.Errorf("unsupported match expression %q", match)
+	}
+
+	for _, alternative := range strings.Split(match, " or ") {
+		matches := true
+		for _, clause := range strings.Split(alternative, " and ") {
+			parts := teamsDeviceMatchClauseRegexp.FindStringSubmatch(strings.TrimSpace(clause))
+			if parts == nil {
+				return false, fmt.Errorf("unsupported match expression %q", match)
+			}
+
+			var value string
+			switch parts[1] {
+			case "identity.email":
+				value = strings.ToLower(device.User.Email)
+			case "os.name":
+				value = strings.ToLower(device.DeviceType)
+			default:
+				return false, fmt.Errorf("unsupported match attribute %q: only identity.email and os.name are known for a device", parts[1])
+			}
+
+			found := false
+			for _, candidate := range teamsDeviceMatchValueRegexp.FindAllStringSubmatch(parts[3], -1) {
+				if strings.ToLower(candidate[1]) == value {
+					found = true
+					break
+				}
+			}
+			matches = matches && found
+		}
+		if matches {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// WatchDeviceChanges polls the device list every interval and emits a change
+// for each device that was registered or removed since the previous poll.
+// The API has no device lifecycle webhook so this is done by diffing
+// successive list calls.
+//
+// Every poll pages through all the devices of the account. The initial
+// device list is fetched before returning and is treated as the baseline.
+// Errors from later polls are sent on the error channel, which must be
+// drained along with the change channel, and polling continues without
+// diffing the failed poll. Both channels are closed once ctx is done.
+func (api *API) WatchDeviceChanges(ctx context.Context, accountID string, interval time.Duration) (<-chan TeamsDeviceChange, <-chan error, error) {
+	if interval <= 0 {
+		return nil, nil, fmt.Errorf("device watch interval must be positive, got %s", interval)
+	}
+
+	devices, err := api.TeamsDevicesAll(ctx, accountID, TeamsDevicesListParams{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	changes := make(chan TeamsDeviceChange)
+	errs := make(chan error)
+
+	go func() {
+		defer close(changes)
+		defer close(errs)
+
+		known := teamsDevicesByID(devices)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			devices, err := api.TeamsDevicesAll(ctx, accountID, TeamsDevicesListParams{})
+			if err != nil {
+				select {
+				case errs <- err:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+
+			current := teamsDevicesByID(devices)
+			var diff []TeamsDeviceChange
+			for _, device := range devices {
+				if _, ok := known[device.ID]; !ok {
+					diff = append(diff, TeamsDeviceChange{Type: TeamsDeviceAdded, Device: device})
+				}
+			}
+			for id, device := range known {
+				if _, ok := current[id]; !ok {
+					diff = append(diff, TeamsDeviceChange{Type: TeamsDeviceRemoved, Device: device})
+				}
+			}
+			known = current
+
+			for _, change := range diff {
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return changes, errs, nil
+}
+
+func teamsDevicesByID(devices []TeamsDeviceListItem) map[string]TeamsDeviceListItem {
+	byID := make(map[string]TeamsDeviceListItem, len(devices))
+	for _, device := range devices {
+		byID[device.ID] = device
+	}
+	return byID
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -84,6 +85,108 @@ func TestTeamsDevicesList(t *testing.T) {
 	}
 }
 
+func TestTeamsDevicesListWithParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, "windows", r.URL.Query().Get("device_type"))
+		assert.Equal(t, "2022-01-01T00:00:00Z", r.URL.Query().Get("last_seen_before"))
+		assert.Equal(t, "false", r.URL.Query().Get("revoked"))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `
+        {
+          "success": true,
+          "errors": [],
+          "messages": [],
+          "result": [
+            {
+              "id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "windows",
+              "last_seen": "2021-06-14T00:00:00Z"
+            },
+            {
+              "id": "a174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "mac",
+              "last_seen": "2021-06-14T00:00:00Z"
+            },
+            {
+              "id": "b174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "windows",
+              "last_seen": "2022-06-14T00:00:00Z"
+            },
+            {
+              "id": "c174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "windows",
+              "last_seen": "2021-06-14T00:00:00Z",
+              "revoked_at": "2021-07-14T00:00:00Z"
+            },
+            {
+              "id": "d174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "windows"
+            }
+          ]
+        }
+    `)
+	}
+
+	want := []TeamsDeviceListItem{{
+		ID:         "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+		DeviceType: "windows",
+		LastSeen:   "2021-06-14T00:00:00Z",
+	}, {
+		ID:         "d174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+		DeviceType: "windows",
+	}}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	lastSeenBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	actual, err := client.ListTeamsDevicesWithParams(context.Background(), testAccountID, TeamsDevicesListParams{
+		LastSeenBefore: &lastSeenBefore,
+		DeviceType:     "windows",
+		Revoked:        BoolPtr(false),
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+}
+
+func TestTeamsDevicesListWithParamsInvalidLastSeen(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `
+        {
+          "success": true,
+          "errors": [],
+          "messages": [],
+          "result": [
+            {
+              "id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+              "device_type": "windows",
+              "last_seen": "yesterday"
+            }
+          ]
+        }
+    `)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	lastSeenAfter := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, err := client.ListTeamsDevicesWithParams(context.Background(), testAccountID, TeamsDevicesListParams{
+		LastSeenAfter: &lastSeenAfter,
+	})
+
+	assert.Error(t, err)
+}
+
 func TestRevokeTeamsDevices(t *testing.T) {
 	setup()
 	defer teardown()
@@ -110,6 +213,69 @@ func TestRevokeTeamsDevices(t *testing.T) {
 	assert.Equal(t, want, actual)
 }
 
+func TestUnrevokeTeamsDevices(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deviceIds := []string{"f174e90a-fafe-4643-bbbc-4a0ed4fc8415", "g174e90a-fafe-4643-bbbc-4a0ed4fc8415"}
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+      "result": null,
+      "success": true,
+      "errors": [],
+      "messages": []
+    }`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/unrevoke", handler)
+
+	want := Response{Success: true, Errors: []ResponseInfo{}, Messages: []ResponseInfo{}}
+
+	actual, err := client.UnrevokeTeamsDevices(context.Background(), testAccountID, deviceIds)
+	require.NoError(t, err)
+	assert.Equal(t, want, actual)
+}
+
+func TestTeamsDevicesPaginated(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, "2", r.URL.Query().Get("page"))
+		assert.Equal(t, "1", r.URL.Query().Get("per_page"))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+					"device_type": "windows",
+					"revoked_at": "2021-07-14T00:00:00Z"
+				}
+			],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 3, "total_pages": 3}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	actual, resultInfo, err := client.TeamsDevices(context.Background(), testAccountID, TeamsDevicesListParams{
+		ResultInfo: ResultInfo{Page: 2, PerPage: 1},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 1)
+		assert.True(t, actual[0].Revoked())
+		assert.Equal(t, ResultInfo{Page: 2, PerPage: 1, Count: 1, Total: 3, TotalPages: 3}, resultInfo)
+	}
+}
+
 func TestGetTeamsDeviceDetails(t *testing.T) {
 	setup()
 	defer teardown()
@@ -182,3 +348,221 @@ func TestGetTeamsDeviceDetails(t *testing.T) {
 		assert.Equal(t, want, actual)
 	}
 }
+
+func TestTeamsDevicesAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, "windows", r.URL.Query().Get("device_type"))
+		w.Header().Set("content-type", "application/json")
+
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415", "device_type": "mac"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "a174e90a-fafe-4643-bbbc-4a0ed4fc8415", "device_type": "windows"}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	actual, err := client.TeamsDevicesAll(context.Background(), testAccountID, TeamsDevicesListParams{DeviceType: "windows"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsDeviceListItem{{ID: "a174e90a-fafe-4643-bbbc-4a0ed4fc8415", DeviceType: "windows"}}, actual)
+	}
+}
+
+func TestWatchDeviceChanges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	responses := []string{
+		`[{"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"}, {"id": "a174e90a-fafe-4643-bbbc-4a0ed4fc8415"}]`,
+		`[{"id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"}, {"id": "b174e90a-fafe-4643-bbbc-4a0ed4fc8415"}]`,
+	}
+	calls := 0
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		result := responses[len(responses)-1]
+		if calls < len(responses) {
+			result = responses[calls]
+		}
+		calls++
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s
+		}`, result)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, _, err := client.WatchDeviceChanges(ctx, testAccountID, time.Millisecond)
+	require.NoError(t, err)
+
+	got := map[TeamsDeviceChangeType]string{}
+	for i := 0; i < 2; i++ {
+		change := <-changes
+		got[change.Type] = change.Device.ID
+	}
+
+	assert.Equal(t, map[TeamsDeviceChangeType]string{
+		TeamsDeviceAdded:   "b174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+		TeamsDeviceRemoved: "a174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+	}, got)
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchDeviceChangesErrors(t *testing.T) {
+	setup()
+	defer teardown()
+
+	calls := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		calls++
+		w.Header().Set("content-type", "application/json")
+		if calls > 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 1000, "message": "internal error"}], "messages": [], "result": null}`)
+			return
+		}
+
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices", handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes, errs, err := client.WatchDeviceChanges(ctx, testAccountID, time.Millisecond)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		assert.Error(t, <-errs)
+	}
+
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchDeviceChangesInvalidInterval(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, _, err := client.WatchDeviceChanges(context.Background(), testAccountID, 0)
+	assert.Error(t, err)
+}
+
+func TestTeamsDeviceEffectiveSettings(t *testing.T) {
+	setup()
+	defer teardown()
+
+	deviceID := "f174e90a-fafe-4643-bbbc-4a0ed4fc8415"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/"+deviceID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "device_type": "windows", "name": "My mobile device", "user": {"email": "user@example.com"}}
+		}`, deviceID)
+	})
+
+	policies := `[
+		{"policy_id": "default", "default": true, "enabled": true},
+		{"policy_id": "macs", "name": "macs", "match": "os.name == \"mac\"", "precedence": 10, "enabled": true},
+		{"policy_id": "disabled", "name": "disabled", "match": "identity.email == \"user@example.com\"", "precedence": 20, "enabled": false}
+	]`
+	resultInfo := `{"page": 1, "per_page": 3, "count": 3, "total_count": 4, "total_pages": 2}`
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/policies", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "2" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"policy_id": "users", "name": "users", "match": "identity.email in {\"other@example.com\" \"User@example.com\"} and os.name == \"windows\"", "precedence": 30, "enabled": true}
+				],
+				"result_info": {"page": 2, "per_page": 3, "count": 1, "total_count": 4, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": %s,
+			"result_info": %s
+		}`, policies, resultInfo)
+	})
+
+	want := TeamsDeviceEffectiveSettings{
+		Device: TeamsDeviceListItem{
+			ID:         deviceID,
+			DeviceType: "windows",
+			Name:       "My mobile device",
+			User:       UserItem{Email: "user@example.com"},
+		},
+		Policy: DeviceSettingsPolicy{
+			PolicyID:   "users",
+			Name:       "users",
+			Match:      `identity.email in {"other@example.com" "User@example.com"} and os.name == "windows"`,
+			Precedence: 30,
+			Enabled:    BoolPtr(true),
+		},
+	}
+
+	actual, err := client.TeamsDeviceEffectiveSettings(context.Background(), testAccountID, deviceID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, want, actual)
+	}
+
+	policies = `[
+		{"policy_id": "default", "default": true, "enabled": true},
+		{"policy_id": "macs", "name": "macs", "match": "os.name == \"mac\"", "precedence": 10, "enabled": true}
+	]`
+	resultInfo = `{"page": 1, "per_page": 3, "count": 2, "total_count": 2, "total_pages": 1}`
+	actual, err = client.TeamsDeviceEffectiveSettings(context.Background(), testAccountID, deviceID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, DeviceSettingsPolicy{PolicyID: "default", Default: true, Enabled: BoolPtr(true)}, actual.Policy)
+	}
+
+	resultInfo = `{"page": 1, "per_page": 3, "count": 1, "total_count": 1, "total_pages": 1}`
+	policies = `[
+		{"policy_id": "groups", "name": "groups", "match": "any(identity.groups.name[*] in {\"engineering\"})", "precedence": 10, "enabled": true}
+	]`
+	_, err = client.TeamsDeviceEffectiveSettings(context.Background(), testAccountID, deviceID)
+	assert.EqualError(t, err, `error evaluating device settings policy "groups": unsupported match expression "any(identity.groups.name[*] in {\"engineering\"})"`)
+}
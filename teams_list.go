@@ -4,7 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"net/mail"
+	"strings"
 	"time"
 
 	"errors"
@@ -12,11 +15,33 @@ import (
 
 var ErrMissingListID = errors.New("required missing list ID")
 
+// TeamsListType is the type of values a TeamsList holds.
+type TeamsListType = string
+
+const (
+	TeamsListTypeSerial TeamsListType = "SERIAL"
+	TeamsListTypeURL    TeamsListType = "URL"
+	TeamsListTypeDomain TeamsListType = "DOMAIN"
+	TeamsListTypeEmail  TeamsListType = "EMAIL"
+	TeamsListTypeIP     TeamsListType = "IP"
+)
+
+// TeamsListTypeValues returns all of the supported teams list types.
+func TeamsListTypeValues() []string {
+	return []string{
+		TeamsListTypeSerial,
+		TeamsListTypeURL,
+		TeamsListTypeDomain,
+		TeamsListTypeEmail,
+		TeamsListTypeIP,
+	}
+}
+
 // TeamsList represents a Teams List.
 type TeamsList struct {
 	ID          string          `json:"id,omitempty"`
 	Name        string          `json:"name"`
-	Type        string          `json:"type"`
+	Type        TeamsListType   `json:"type"`
 	Description string          `json:"description,omitempty"`
 	Items       []TeamsListItem `json:"items,omitempty"`
 	Count       uint64          `json:"count,omitempty"`
@@ -24,10 +49,12 @@ type TeamsList struct {
 	UpdatedAt   *time.Time      `json:"updated_at,omitempty"`
 }
 
-// TeamsListItem represents a single list item.
+// TeamsListItem represents a single list item. Description documents why
+// the item is in the list and is kept along with the value.
 type TeamsListItem struct {
-	Value     string     `json:"value"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
+	Value       string     `json:"value"`
+	Description string     `json:"description,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
 }
 
 // PatchTeamsList represents a patch request for appending/removing list items.
@@ -67,11 +94,59 @@ type TeamsListItemsParams struct {
 	PaginationOptions
 }
 
-// TeamsLists returns all lists within an account.
+// TeamsListsParams filters the lists returned by TeamsLists.
+type TeamsListsParams struct {
+	Name string        `url:"name,omitempty"`
+	Type TeamsListType `url:"type,omitempty"`
+}
+
+func validTeamsListType(listType TeamsListType) bool {
+	for _, t := range TeamsListTypeValues() {
+		if t == listType {
+			return true
+		}
+	}
+	return false
+}
+
+// validateTeamsListItems checks that the items hold values of the list
+// type: IP addresses or CIDRs for IP lists and email addresses for EMAIL
+// lists. Values of the other types are only checked to be set.
+func validateTeamsListItems(listType TeamsListType, items []TeamsListItem) error {
+	for _, item := range items {
+		if item.Value == "" {
+			return errors.New("teams list item value cannot be empty")
+		}
+
+		switch listType {
+		case TeamsListTypeIP:
+			if net.ParseIP(item.Value) == nil {
+				if _, _, err := net.ParseCIDR(item.Value); err != nil {
+					return fmt.Errorf("invalid item %q for teams list of type %s: must be an IP address or CIDR", item.Value, listType)
+				}
+			}
+		case TeamsListTypeEmail:
+			if address, err := mail.ParseAddress(item.Value); err != nil || address.Address != item.Value {
+				return fmt.Errorf("invalid item %q for teams list of type %s: must be an email address", item.Value, listType)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TeamsLists returns all lists within an account, or when params are given
+// only the lists matching their name and type.
 //
 // API reference: https://api.cloudflare.com/#teams-lists-list-teams-lists
-func (api *API) TeamsLists(ctx context.Context, accountID string) ([]TeamsList, ResultInfo, error) {
+func (api *API) TeamsLists(ctx context.Context, accountID string, params ...TeamsListsParams) ([]TeamsList, ResultInfo, error) {
 	uri := fmt.Sprintf("/%s/%s/gateway/lists", AccountRouteRoot, accountID)
+	if len(params) > 0 {
+		if params[0].Type != "" && !validTeamsListType(params[0].Type) {
+			return []TeamsList{}, ResultInfo{}, fmt.Errorf("invalid teams list type %q, must be one of %s", params[0].Type, strings.Join(TeamsListTypeValues(), ", "))
+		}
+		uri = buildURI(uri, params[0])
+	}
 
 	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
@@ -87,6 +162,50 @@ func (api *API) TeamsLists(ctx context.Context, accountID string) ([]TeamsList,
 	return teamsListListResponse.Result, teamsListListResponse.ResultInfo, nil
 }
 
+// TeamsListsAll returns all lists within an account, following the pagination
+// until every page has been fetched. If a page after the first one fails,
+// the lists fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#teams-lists-list-teams-lists
+func (api *API) TeamsListsAll(ctx context.Context, accountID string, params ...TeamsListsParams) ([]TeamsList, error) {
+	var filter TeamsListsParams
+	if len(params) > 0 {
+		if params[0].Type != "" && !validTeamsListType(params[0].Type) {
+			return []TeamsList{}, fmt.Errorf("invalid teams list type %q, must be one of %s", params[0].Type, strings.Join(TeamsListTypeValues(), ", "))
+		}
+		filter = params[0]
+	}
+
+	uri := fmt.Sprintf("/%s/%s/gateway/lists", AccountRouteRoot, accountID)
+
+	var lists []TeamsList
+	err := fetchAllPages(ctx, 50, func(page ResultInfo) (int, ResultInfo, error) {
+		query := struct {
+			TeamsListsParams
+			PaginationOptions
+		}{filter, PaginationOptions{Page: page.Page, PerPage: page.PerPage}}
+
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, query), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var teamsListListResponse TeamsListListResponse
+		err = json.Unmarshal(res, &teamsListListResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		lists = append(lists, teamsListListResponse.Result...)
+		return len(teamsListListResponse.Result), teamsListListResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []TeamsList{}, err
+	}
+
+	return lists, err
+}
+
 // TeamsList returns a single list based on the list ID.
 //
 // API reference: https://api.cloudflare.com/#teams-lists-teams-list-details
@@ -143,10 +262,20 @@ func (api *API) TeamsListItems(ctx context.Context, params TeamsListItemsParams)
 	return teamsListItemsListResponse.Result, teamsListItemsListResponse.ResultInfo, nil
 }
 
-// CreateTeamsList creates a new teams list.
+// CreateTeamsList creates a new teams list. Retrying a failed create may
+// produce a duplicate list; use TeamsUpsertListByName when the outcome of an
+// earlier attempt is unknown.
 //
 // API reference: https://api.cloudflare.com/#teams-lists-create-teams-list
 func (api *API) CreateTeamsList(ctx context.Context, accountID string, teamsList TeamsList) (TeamsList, error) {
+	if !validTeamsListType(teamsList.Type) {
+		return TeamsList{}, fmt.Errorf("invalid teams list type %q, must be one of %s", teamsList.Type, strings.Join(TeamsListTypeValues(), ", "))
+	}
+
+	if err := validateTeamsListItems(teamsList.Type, teamsList.Items); err != nil {
+		return TeamsList{}, err
+	}
+
 	uri := fmt.Sprintf("/%s/%s/gateway/lists", AccountRouteRoot, accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, teamsList)
@@ -163,7 +292,10 @@ func (api *API) CreateTeamsList(ctx context.Context, accountID string, teamsList
 	return teamsListDetailResponse.Result, nil
 }
 
-// UpdateTeamsList updates an existing teams list.
+// UpdateTeamsList updates an existing teams list. The type of a list can't
+// be changed, so the existing list is fetched first and an update to
+// another type is refused; an empty Type keeps the type of the list. The
+// items are checked against the list type before the update is sent.
 //
 // API reference: https://api.cloudflare.com/#teams-lists-update-teams-list
 func (api *API) UpdateTeamsList(ctx context.Context, accountID string, teamsList TeamsList) (TeamsList, error) {
@@ -171,6 +303,22 @@ func (api *API) UpdateTeamsList(ctx context.Context, accountID string, teamsList
 		return TeamsList{}, fmt.Errorf("teams list ID cannot be empty")
 	}
 
+	existing, err := api.TeamsList(ctx, accountID, teamsList.ID)
+	if err != nil {
+		return TeamsList{}, err
+	}
+
+	if teamsList.Type == "" {
+		teamsList.Type = existing.Type
+	}
+	if teamsList.Type != existing.Type {
+		return TeamsList{}, fmt.Errorf("teams list type can't be changed from %s to %s, create a new list instead", existing.Type, teamsList.Type)
+	}
+
+	if err := validateTeamsListItems(teamsList.Type, teamsList.Items); err != nil {
+		return TeamsList{}, err
+	}
+
 	uri := fmt.Sprintf(
 		"/%s/%s/gateway/lists/%s",
 		AccountRouteRoot,
@@ -192,7 +340,8 @@ func (api *API) UpdateTeamsList(ctx context.Context, accountID string, teamsList
 	return teamsListDetailResponse.Result, nil
 }
 
-// PatchTeamsList updates the items in an existing teams list.
+// PatchTeamsList updates the items in an existing teams list. The returned
+// list's Count reflects the number of items after the patch is applied.
 //
 // API reference: https://api.cloudflare.com/#teams-lists-patch-teams-list
 func (api *API) PatchTeamsList(ctx context.Context, accountID string, listPatch PatchTeamsList) (TeamsList, error) {
@@ -200,6 +349,10 @@ func (api *API) PatchTeamsList(ctx context.Context, accountID string, listPatch
 		return TeamsList{}, fmt.Errorf("teams list ID cannot be empty")
 	}
 
+	if len(listPatch.Append) == 0 && len(listPatch.Remove) == 0 {
+		return TeamsList{}, fmt.Errorf("teams list patch must append or remove at least one item")
+	}
+
 	uri := fmt.Sprintf(
 		"/%s/%s/gateway/lists/%s",
 		AccountRouteRoot,
@@ -239,3 +392,305 @@ func (api *API) DeleteTeamsList(ctx context.Context, accountID, teamsListID stri
 
 	return nil
 }
+
+// TeamsDeleteListsError holds the errors of the lists that
+// TeamsDeleteListsByPrefix failed to delete.
+type TeamsDeleteListsError = BatchError
+
+// TeamsDeleteListsByPrefix deletes every list whose name starts with prefix
+// and returns the IDs of the deleted lists. A failed delete doesn't stop the
+// others; the failures are returned in a *TeamsDeleteListsError along with
+// the IDs that were deleted. An empty prefix is refused as it would delete
+// every list.
+func (api *API) TeamsDeleteListsByPrefix(ctx context.Context, accountID, prefix string) ([]string, error) {
+	if prefix == "" {
+		return []string{}, errors.New("list name prefix cannot be empty")
+	}
+
+	lists, err := api.TeamsListsAll(ctx, accountID)
+	if err != nil {
+		return []string{}, err
+	}
+
+	deleted := []string{}
+	var errs []error
+	for _, list := range lists {
+		if !strings.HasPrefix(list.Name, prefix) {
+			continue
+		}
+
+		if err := api.DeleteTeamsList(ctx, accountID, list.ID); err != nil {
+			errs = append(errs, fmt.Errorf("deleting list %s (%s): %w", list.Name, list.ID, err))
+			continue
+		}
+		deleted = append(deleted, list.ID)
+	}
+
+	if len(errs) > 0 {
+		return deleted, &TeamsDeleteListsError{Errors: errs}
+	}
+
+	return deleted, nil
+}
+
+// TeamsUpsertListByName makes sure a list named teamsList.Name exists with
+// exactly the items of teamsList. A missing list is created, otherwise the
+// items of the existing list are patched to match. Items are matched by
+// value, so the description of an item already in the list isn't changed.
+// When the create fails because another caller created the list first, the
+// list is looked up again and patched instead. An existing list of another
+// type than teamsList.Type is left untouched and an error is returned. The
+// returned bool reports whether the list was created.
+func (api *API) TeamsUpsertListByName(ctx context.Context, accountID string, teamsList TeamsList) (TeamsList, bool, error) {
+	existing, found, err := api.teamsListByName(ctx, accountID, teamsList.Name)
+	if err != nil {
+		return TeamsList{}, false, err
+	}
+
+	if !found {
+		created, createErr := api.CreateTeamsList(ctx, accountID, teamsList)
+		if createErr == nil {
+			return created, true, nil
+		}
+
+		existing, found, err = api.teamsListByName(ctx, accountID, teamsList.Name)
+		if err != nil || !found {
+			return TeamsList{}, false, createErr
+		}
+	}
+
+	if teamsList.Type != "" && existing.Type != teamsList.Type {
+		return TeamsList{}, false, fmt.Errorf("teams list %q is of type %s, not %s", existing.Name, existing.Type, teamsList.Type)
+	}
+
+	items, err := api.teamsListAllItems(ctx, accountID, existing.ID)
+	if err != nil {
+		return TeamsList{}, false, err
+	}
+
+	current := make(map[string]bool, len(items))
+	for _, item := range items {
+		current[item.Value] = true
+	}
+
+	listPatch := PatchTeamsList{ID: existing.ID}
+	desired := make(map[string]bool, len(teamsList.Items))
+	for _, item := range teamsList.Items {
+		desired[item.Value] = true
+		if !current[item.Value] {
+			listPatch.Append = append(listPatch.Append, TeamsListItem{Value: item.Value, Description: item.Description})
+		}
+	}
+	for _, item := range items {
+		if !desired[item.Value] {
+			listPatch.Remove = append(listPatch.Remove, item.Value)
+		}
+	}
+
+	if len(listPatch.Append) == 0 && len(listPatch.Remove) == 0 {
+		return existing, false, nil
+	}
+
+	updated, err := api.PatchTeamsList(ctx, accountID, listPatch)
+	if err != nil {
+		return TeamsList{}, false, err
+	}
+
+	return updated, false, nil
+}
+
+// teamsListReplaceChunkSize is the number of items patched per request by
+// TeamsReplaceListItems when no chunk size is given.
+const teamsListReplaceChunkSize = 1000
+
+// TeamsListProgressFunc is called by TeamsReplaceListItems after each chunk
+// of items is uploaded with the number of items uploaded so far.
+type TeamsListProgressFunc func(uploaded, total int)
+
+// TeamsReplaceListItems replaces the items of a list with items. The list is
+// emptied and the items are then appended in chunks of chunkSize, which
+// avoids the timeouts of sending a large list in a single request. Each chunk
+// is retried with the client's retry policy on transient failures, and
+// progress, when not nil, is called after every uploaded chunk. The list is
+// left partially filled when a chunk fails.
+//
+// API reference: https://api.cloudflare.com/#teams-lists-patch-teams-list
+func (api *API) TeamsReplaceListItems(ctx context.Context, accountID, listID string, items []TeamsListItem, chunkSize int, progress TeamsListProgressFunc) error {
+	if listID == "" {
+		return fmt.Errorf("teams list ID cannot be empty")
+	}
+
+	if chunkSize <= 0 {
+		chunkSize = teamsListReplaceChunkSize
+	}
+
+	existing, err := api.teamsListAllItems(ctx, accountID, listID)
+	if err != nil {
+		return err
+	}
+
+	// patching the same chunk twice leaves the list in the same state, so
+	// chunks are safe to retry
+	ctx = WithRetryableRequest(ctx)
+
+	for start := 0; start < len(existing); start += chunkSize {
+		end := start + chunkSize
+		if end > len(existing) {
+			end = len(existing)
+		}
+
+		remove := make([]string, 0, end-start)
+		for _, item := range existing[start:end] {
+			remove = append(remove, item.Value)
+		}
+
+		if _, err := api.PatchTeamsList(ctx, accountID, PatchTeamsList{ID: listID, Remove: remove}); err != nil {
+			return fmt.Errorf("error clearing list items: %w", err)
+		}
+	}
+
+	for start := 0; start < len(items); start += chunkSize {
+		end := start + chunkSize
+		if end > len(items) {
+			end = len(items)
+		}
+
+		if _, err := api.PatchTeamsList(ctx, accountID, PatchTeamsList{ID: listID, Append: items[start:end]}); err != nil {
+			return fmt.Errorf("error uploading list items %d to %d: %w", start, end, err)
+		}
+
+		if progress != nil {
+			progress(end, len(items))
+		}
+	}
+
+	return nil
+}
+
+// teamsListByName returns the list within an account with the given name.
+func (api *API) teamsListByName(ctx context.Context, accountID, name string) (TeamsList, bool, error) {
+	lists, err := api.TeamsListsAll(ctx, accountID)
+	if err != nil {
+		return TeamsList{}, false, err
+	}
+
+	for _, list := range lists {
+		if list.Name == name {
+			return list, true, nil
+		}
+	}
+
+	return TeamsList{}, false, nil
+}
+
+// teamsListAllItems returns every item of a list, following the pagination.
+// If a page after the first one fails, the items fetched so far are
+// returned along with a *PaginationError.
+func (api *API) teamsListAllItems(ctx context.Context, accountID, listID string) ([]TeamsListItem, error) {
+	var items []TeamsListItem
+	err := fetchAllPages(ctx, 100, func(params ResultInfo) (int, ResultInfo, error) {
+		page, resultInfo, err := api.TeamsListItems(ctx, TeamsListItemsParams{
+			AccountID:         accountID,
+			ListID:            listID,
+			PaginationOptions: PaginationOptions{Page: params.Page, PerPage: params.PerPage},
+		})
+		items = append(items, page...)
+		return len(page), resultInfo, err
+	})
+	if err != nil && !isPaginationError(err) {
+		return []TeamsListItem{}, err
+	}
+
+	return items, err
+}
+
+// TeamsListItemsIterator iterates over the items of a list, fetching a page
+// at a time as the items are consumed.
+//
+//	it := api.TeamsListItemsIterator(ctx, accountID, listID)
+//	for it.Next() {
+//		item := it.Item()
+//		...
+//	}
+//	if err := it.Err(); err != nil {
+//		...
+//	}
+type TeamsListItemsIterator struct {
+	ctx    context.Context
+	api    *API
+	params TeamsListItemsParams
+
+	items []TeamsListItem
+	index int
+	done  bool
+	err   error
+}
+
+// teamsListItemsIteratorPerPage is the number of items fetched per request.
+const teamsListItemsIteratorPerPage = 100
+
+// TeamsListItemsIterator returns an iterator over all items of a list. No
+// request is made until Next is called.
+func (api *API) TeamsListItemsIterator(ctx context.Context, accountID, listID string) *TeamsListItemsIterator {
+	return &TeamsListItemsIterator{
+		ctx: ctx,
+		api: api,
+		params: TeamsListItemsParams{
+			AccountID:         accountID,
+			ListID:            listID,
+			PaginationOptions: PaginationOptions{Page: 1, PerPage: teamsListItemsIteratorPerPage},
+		},
+		index: -1,
+	}
+}
+
+// Next advances to the next item, fetching the next page when needed. It
+// returns false when there are no more items or an error occurred.
+func (it *TeamsListItemsIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+
+	if it.index+1 < len(it.items) {
+		it.index++
+		return true
+	}
+
+	if it.done {
+		return false
+	}
+
+	if err := it.ctx.Err(); err != nil {
+		it.err = err
+		return false
+	}
+
+	items, resultInfo, err := it.api.TeamsListItems(it.ctx, it.params)
+	if err != nil {
+		it.err = err
+		return false
+	}
+
+	// responses without pagination details contain every item
+	if len(items) == 0 || resultInfo.Page == 0 || resultInfo.Page >= resultInfo.TotalPages {
+		it.done = true
+	}
+	it.params.Page++
+	it.items = items
+	it.index = -1
+
+	return it.Next()
+}
+
+// Item returns the current item.
+func (it *TeamsListItemsIterator) Item() TeamsListItem {
+	if it.index < 0 || it.index >= len(it.items) {
+		return TeamsListItem{}
+	}
+	return it.items[it.index]
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TeamsListItemsIterator) Err() error {
+	return it.err
+}
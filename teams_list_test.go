@@ -2,7 +2,10 @@ package cloudflare
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
 	"time"
@@ -64,6 +67,43 @@ func TestTeamsLists(t *testing.T) {
 	}
 }
 
+func TestTeamsListsWithParams(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, "IP", r.URL.Query().Get("type"))
+		assert.Equal(t, "office", r.URL.Query().Get("name"))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+					"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+					"name": "office",
+					"type": "IP",
+					"count": 3
+				}
+			]
+		}
+		`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", handler)
+
+	actual, _, err := client.TeamsLists(context.Background(), testAccountID, TeamsListsParams{Name: "office", Type: "IP"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsList{{ID: "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", Name: "office", Type: "IP", Count: 3}}, actual)
+	}
+
+	_, _, err = client.TeamsLists(context.Background(), testAccountID, TeamsListsParams{Type: "IPS"})
+	assert.Error(t, err)
+}
+
 func TestTeamsList(t *testing.T) {
 	setup()
 	defer teardown()
@@ -124,6 +164,7 @@ func TestTeamsListItems(t *testing.T) {
 			"result": [
 				{
 					"value": "val1",
+					"description": "TICKET-123",
 					"created_at": "2014-01-01T05:20:00.12345Z"
 				},
 				{
@@ -139,8 +180,9 @@ func TestTeamsListItems(t *testing.T) {
 
 	want := []TeamsListItem{
 		{
-			Value:     "val1",
-			CreatedAt: &createdAt,
+			Value:       "val1",
+			Description: "TICKET-123",
+			CreatedAt:   &createdAt,
 		},
 		{
 			Value:     "val2",
@@ -206,13 +248,33 @@ func TestCreateTeamsList(t *testing.T) {
 	}
 }
 
+func TestCreateTeamsListWithInvalidType(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.CreateTeamsList(context.Background(), testAccountID, TeamsList{
+		Name: "My Serial List",
+		Type: "HOSTNAME",
+	})
+	assert.EqualError(t, err, `invalid teams list type "HOSTNAME", must be one of SERIAL, URL, DOMAIN, EMAIL, IP`)
+}
+
 func TestUpdateTeamsList(t *testing.T) {
 	setup()
 	defer teardown()
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
-		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
 		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": {"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", "name": "My Serial List", "type": "SERIAL"}
+			}`)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method, "Expected method 'PUT', got %s", r.Method)
 		fmt.Fprintf(w, `{
 			"success": true,
 			"errors": [],
@@ -249,6 +311,23 @@ func TestUpdateTeamsList(t *testing.T) {
 	if assert.NoError(t, err) {
 		assert.Equal(t, teamsList, actual)
 	}
+
+	_, err = client.UpdateTeamsList(context.Background(), testAccountID, TeamsList{ID: teamsList.ID, Name: "My Serial List", Type: "IP"})
+	assert.EqualError(t, err, "teams list type can't be changed from SERIAL to IP, create a new list instead")
+}
+
+func TestValidateTeamsListItems(t *testing.T) {
+	assert.NoError(t, validateTeamsListItems(TeamsListTypeIP, []TeamsListItem{{Value: "192.0.2.1"}, {Value: "2001:db8::/32"}}))
+	assert.NoError(t, validateTeamsListItems(TeamsListTypeEmail, []TeamsListItem{{Value: "jane@example.com"}}))
+
+	err := validateTeamsListItems(TeamsListTypeIP, []TeamsListItem{{Value: "example.com"}})
+	assert.EqualError(t, err, `invalid item "example.com" for teams list of type IP: must be an IP address or CIDR`)
+
+	err = validateTeamsListItems(TeamsListTypeEmail, []TeamsListItem{{Value: "Jane <jane@example.com>"}})
+	assert.EqualError(t, err, `invalid item "Jane <jane@example.com>" for teams list of type EMAIL: must be an email address`)
+
+	err = validateTeamsListItems(TeamsListTypeSerial, []TeamsListItem{{Value: ""}})
+	assert.EqualError(t, err, "teams list item value cannot be empty")
 }
 
 func TestUpdateTeamsListWithMissingID(t *testing.T) {
@@ -265,6 +344,12 @@ func TestPatchTeamsList(t *testing.T) {
 
 	handler := func(w http.ResponseWriter, r *http.Request) {
 		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
+			"append": [{"value": "abcd-1234", "description": "TICKET-123"}],
+			"remove": ["def-5678"]
+		}`, string(body))
 		w.Header().Set("content-type", "application/json")
 		fmt.Fprintf(w, `{
 			"success": true,
@@ -299,7 +384,7 @@ func TestPatchTeamsList(t *testing.T) {
 
 	actual, err := client.PatchTeamsList(context.Background(), testAccountID, PatchTeamsList{
 		ID:     "480f4f69-1a28-4fdd-9240-1ed29f0ac1db",
-		Append: []TeamsListItem{{Value: "abcd-1234"}},
+		Append: []TeamsListItem{{Value: "abcd-1234", Description: "TICKET-123"}},
 		Remove: []string{"def-5678"},
 	})
 
@@ -331,3 +416,404 @@ func TestDeleteTeamsList(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestPatchTeamsListWithNoChanges(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.PatchTeamsList(context.Background(), testAccountID, PatchTeamsList{ID: "480f4f69-1a28-4fdd-9240-1ed29f0ac1db"})
+	assert.EqualError(t, err, "teams list patch must append or remove at least one item")
+}
+
+func TestTeamsUpsertListByName(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const listID = "480f4f69-1a28-4fdd-9240-1ed29f0ac1db"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "8e1a2d6c-4b5f-4b0e-9b0a-0f6a2c1d3e4f", "name": "Other List", "type": "SERIAL"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "%s", "name": "My Serial List", "type": "SERIAL", "count": 2}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`, listID)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+listID+"/items", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"value": "abcd-1234"}, {"value": "def-5678"}],
+			"result_info": {"page": 1, "per_page": 100, "count": 2, "total_count": 2, "total_pages": 1}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+listID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		var listPatch PatchTeamsList
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&listPatch)) {
+			assert.Equal(t, PatchTeamsList{
+				ID:     listID,
+				Append: []TeamsListItem{{Value: "ghi-9012", Description: "TICKET-456"}},
+				Remove: []string{"def-5678"},
+			}, listPatch)
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "name": "My Serial List", "type": "SERIAL", "count": 2}
+		}`, listID)
+	})
+
+	actual, created, err := client.TeamsUpsertListByName(context.Background(), testAccountID, TeamsList{
+		Name:  "My Serial List",
+		Type:  "SERIAL",
+		Items: []TeamsListItem{{Value: "abcd-1234"}, {Value: "ghi-9012", Description: "TICKET-456"}},
+	})
+
+	if assert.NoError(t, err) {
+		assert.False(t, created)
+		assert.Equal(t, TeamsList{ID: listID, Name: "My Serial List", Type: "SERIAL", Count: 2}, actual)
+	}
+}
+
+func TestTeamsUpsertListByNameTypeMismatch(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", "name": "My Serial List", "type": "DOMAIN", "count": 2}]
+		}`)
+	})
+
+	_, created, err := client.TeamsUpsertListByName(context.Background(), testAccountID, TeamsList{
+		Name:  "My Serial List",
+		Type:  "SERIAL",
+		Items: []TeamsListItem{{Value: "abcd-1234"}},
+	})
+
+	assert.EqualError(t, err, `teams list "My Serial List" is of type DOMAIN, not SERIAL`)
+	assert.False(t, created)
+}
+
+func TestTeamsUpsertListByNameCreates(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+			return
+		}
+
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", "name": "My Serial List", "type": "SERIAL", "count": 1}
+		}`)
+	})
+
+	actual, created, err := client.TeamsUpsertListByName(context.Background(), testAccountID, TeamsList{
+		Name:  "My Serial List",
+		Type:  "SERIAL",
+		Items: []TeamsListItem{{Value: "abcd-1234"}},
+	})
+
+	if assert.NoError(t, err) {
+		assert.True(t, created)
+		assert.Equal(t, "480f4f69-1a28-4fdd-9240-1ed29f0ac1db", actual.ID)
+	}
+}
+
+func TestTeamsUpsertListByNameCreateRace(t *testing.T) {
+	setup()
+	defer teardown()
+
+	const listID = "480f4f69-1a28-4fdd-9240-1ed29f0ac1db"
+
+	lookups := 0
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{"success": false, "errors": [{"code": 2000, "message": "list already exists"}], "messages": [], "result": null}`)
+			return
+		}
+
+		lookups++
+		if lookups == 1 {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "%s", "name": "My Serial List", "type": "SERIAL", "count": 1}]
+		}`, listID)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+listID+"/items", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [{"value": "abcd-1234"}]}`)
+	})
+
+	actual, created, err := client.TeamsUpsertListByName(context.Background(), testAccountID, TeamsList{
+		Name:  "My Serial List",
+		Type:  "SERIAL",
+		Items: []TeamsListItem{{Value: "abcd-1234"}},
+	})
+
+	if assert.NoError(t, err) {
+		assert.False(t, created)
+		assert.Equal(t, 2, lookups)
+		assert.Equal(t, TeamsList{ID: listID, Name: "My Serial List", Type: "SERIAL", Count: 1}, actual)
+	}
+}
+
+func TestTeamsListItemsIterator(t *testing.T) {
+	setup()
+	defer teardown()
+
+	requests := 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		requests++
+		page := r.URL.Query().Get("page")
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"value": "item-%[1]s-1"}, {"value": "item-%[1]s-2"}],
+			"result_info": {"page": %[1]s, "per_page": 2, "count": 2, "total_count": 4, "total_pages": 2}
+		}`, page)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/480f4f69-1a28-4fdd-9240-1ed29f0ac1db/items", handler)
+
+	it := client.TeamsListItemsIterator(context.Background(), testAccountID, "480f4f69-1a28-4fdd-9240-1ed29f0ac1db")
+	assert.Equal(t, 0, requests)
+
+	var values []string
+	for it.Next() {
+		values = append(values, it.Item().Value)
+		if len(values) == 1 {
+			assert.Equal(t, 1, requests)
+		}
+	}
+
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"item-1-1", "item-1-2", "item-2-1", "item-2-2"}, values)
+	assert.Equal(t, 2, requests)
+	assert.False(t, it.Next())
+}
+
+func TestTeamsListItemsIteratorCancelled(t *testing.T) {
+	setup()
+	defer teardown()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"value": "item-1"}],
+			"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/480f4f69-1a28-4fdd-9240-1ed29f0ac1db/items", handler)
+
+	it := client.TeamsListItemsIterator(ctx, testAccountID, "480f4f69-1a28-4fdd-9240-1ed29f0ac1db")
+	assert.True(t, it.Next())
+	assert.Equal(t, "item-1", it.Item().Value)
+
+	cancel()
+	assert.False(t, it.Next())
+	assert.ErrorIs(t, it.Err(), context.Canceled)
+}
+
+func TestTeamsReplaceListItems(t *testing.T) {
+	setup(UsingRetryPolicy(1, 0, 0))
+	defer teardown()
+
+	const listID = "480f4f69-1a28-4fdd-9240-1ed29f0ac1db"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+listID+"/items", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"value": "abcd-1234"}, {"value": "def-5678"}],
+			"result_info": {"page": 1, "per_page": 100, "count": 2, "total_count": 2, "total_pages": 1}
+		}`)
+	})
+
+	var patches []PatchTeamsList
+	failed := false
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+listID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+
+		var listPatch PatchTeamsList
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&listPatch))
+
+		// fail the first chunk upload once to exercise the retry
+		if len(listPatch.Append) > 0 && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprintf(w, `{"success": false, "errors": [{"code": 1000, "message": "timeout"}], "messages": [], "result": null}`)
+			return
+		}
+
+		patches = append(patches, listPatch)
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "name": "My Serial List", "type": "SERIAL"}
+		}`, listID)
+	})
+
+	var progress [][2]int
+	err := client.TeamsReplaceListItems(context.Background(), testAccountID, listID, []TeamsListItem{
+		{Value: "ghi-9012"}, {Value: "jkl-3456"}, {Value: "mno-7890"},
+	}, 2, func(uploaded, total int) {
+		progress = append(progress, [2]int{uploaded, total})
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []PatchTeamsList{
+			{ID: listID, Remove: []string{"abcd-1234", "def-5678"}},
+			{ID: listID, Append: []TeamsListItem{{Value: "ghi-9012"}, {Value: "jkl-3456"}}},
+			{ID: listID, Append: []TeamsListItem{{Value: "mno-7890"}}},
+		}, patches)
+		assert.Equal(t, [][2]int{{2, 3}, {3, 3}}, progress)
+	}
+}
+
+func TestTeamsDeleteListsByPrefix(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{"id": "list-1", "name": "ci-test-1", "type": "IP"},
+					{"id": "list-2", "name": "production", "type": "IP"}
+				],
+				"result_info": {"page": 1, "per_page": 2, "count": 2, "total_count": 4, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "list-3", "name": "ci-test-2", "type": "DOMAIN"},
+				{"id": "list-4", "name": "ci-test-3", "type": "DOMAIN"}
+			],
+			"result_info": {"page": 2, "per_page": 2, "count": 2, "total_count": 4, "total_pages": 2}
+		}`)
+	})
+
+	var deletedIDs []string
+	deleteHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method, "Expected method 'DELETE', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Path == "/accounts/"+testAccountID+"/gateway/lists/list-3" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 1000, "message": "list is in use"}], "messages": [], "result": null}`)
+			return
+		}
+		deletedIDs = append(deletedIDs, r.URL.Path)
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": null}`)
+	}
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/list-1", deleteHandler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/list-3", deleteHandler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/list-4", deleteHandler)
+
+	deleted, err := client.TeamsDeleteListsByPrefix(context.Background(), testAccountID, "ci-test-")
+
+	assert.Equal(t, []string{"list-1", "list-4"}, deleted)
+	assert.Len(t, deletedIDs, 2)
+	var deleteErr *TeamsDeleteListsError
+	if assert.True(t, errors.As(err, &deleteErr)) {
+		assert.Len(t, deleteErr.Errors, 1)
+		assert.Contains(t, err.Error(), "deleting list ci-test-2 (list-3)")
+	}
+
+	_, err = client.TeamsDeleteListsByPrefix(context.Background(), testAccountID, "")
+	assert.EqualError(t, err, "list name prefix cannot be empty")
+}
+
+func TestTeamsListsAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		assert.Equal(t, "IP", r.URL.Query().Get("type"))
+		w.Header().Set("content-type", "application/json")
+
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "list-%s", "name": "list%s", "type": "IP"}],
+			"result_info": {"page": %s, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`, page, page, page)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists", handler)
+
+	actual, err := client.TeamsListsAll(context.Background(), testAccountID, TeamsListsParams{Type: "IP"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsList{
+			{ID: "list-1", Name: "list1", Type: "IP"},
+			{ID: "list-2", Name: "list2", Type: "IP"},
+		}, actual)
+	}
+}
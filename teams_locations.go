@@ -34,6 +34,7 @@ type TeamsLocation struct {
 	AnonymizedLogsEnabled bool                   `json:"anonymized_logs_enabled"`
 	IPv4Destination       string                 `json:"ipv4_destination"`
 	ClientDefault         bool                   `json:"client_default"`
+	ECSSupport            *bool                  `json:"ecs_support,omitempty"`
 
 	CreatedAt *time.Time `json:"created_at,omitempty"`
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
@@ -59,6 +60,37 @@ func (api *API) TeamsLocations(ctx context.Context, accountID string) ([]TeamsLo
 	return teamsLocationsListResponse.Result, teamsLocationsListResponse.ResultInfo, nil
 }
 
+// TeamsLocationsAll returns all locations within an account, following the pagination
+// until every page has been fetched. If a page after the first one fails,
+// the locations fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#teams-locations-list-teams-locations
+func (api *API) TeamsLocationsAll(ctx context.Context, accountID string) ([]TeamsLocation, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/locations", AccountRouteRoot, accountID)
+
+	var locations []TeamsLocation
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, PaginationOptions{Page: params.Page, PerPage: params.PerPage}), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var teamsLocationsListResponse TeamsLocationsListResponse
+		err = json.Unmarshal(res, &teamsLocationsListResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		locations = append(locations, teamsLocationsListResponse.Result...)
+		return len(teamsLocationsListResponse.Result), teamsLocationsListResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []TeamsLocation{}, err
+	}
+
+	return locations, err
+}
+
 // TeamsLocation returns a single location based on the ID.
 //
 // API reference: https://api.cloudflare.com/#teams-locations-teams-location-details
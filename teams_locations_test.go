@@ -154,8 +154,9 @@ func TestCreateTeamsLocation(t *testing.T) {
 				"ip": "2a06:98c1:54::2419",
 				"doh_subdomain": "q15l7x2lbw",
 				"anonymized_logs_enabled": false,
-				"ipv4_destination": null,
+				"ipv4_destination": "172.64.36.1",
 				"client_default": false,
+				"ecs_support": true,
 				"created_at": "2020-05-18T22:07:03Z",
 				"updated_at": "2020-05-18T22:07:05Z"
 			}
@@ -174,8 +175,9 @@ func TestCreateTeamsLocation(t *testing.T) {
 		Ip:                    "2a06:98c1:54::2419",
 		Subdomain:             "q15l7x2lbw",
 		AnonymizedLogsEnabled: false,
-		IPv4Destination:       "",
+		IPv4Destination:       "172.64.36.1",
 		ClientDefault:         false,
+		ECSSupport:            BoolPtr(true),
 		CreatedAt:             &createdAt,
 		UpdatedAt:             &updatedAt,
 	}
@@ -185,6 +187,7 @@ func TestCreateTeamsLocation(t *testing.T) {
 	actual, err := client.CreateTeamsLocation(context.Background(), testAccountID, TeamsLocation{
 		Name:          "test",
 		ClientDefault: true,
+		ECSSupport:    BoolPtr(true),
 		Networks:      []TeamsLocationNetwork{},
 	})
 	require.Nil(t, err)
@@ -268,3 +271,37 @@ func TestDeleteTeamsLocation(t *testing.T) {
 	err := client.DeleteTeamsLocation(context.Background(), testAccountID, id)
 	require.Nil(t, err)
 }
+
+func TestTeamsLocationsAllPartialError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+
+		if r.URL.Query().Get("page") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 1000, "message": "bad page"}], "messages": [], "result": null}`)
+			return
+		}
+
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "location-1", "name": "office"}],
+			"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/locations", handler)
+
+	actual, err := client.TeamsLocationsAll(context.Background(), testAccountID)
+
+	assert.Equal(t, []TeamsLocation{{ID: "location-1", Name: "office"}}, actual)
+	var pageErr *PaginationError
+	if assert.ErrorAs(t, err, &pageErr) {
+		assert.Equal(t, 2, pageErr.Page)
+	}
+}
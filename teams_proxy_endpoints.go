@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"strings"
 	"time"
 )
 
@@ -27,6 +29,17 @@ type TeamsProxyEndpoint struct {
 	UpdatedAt *time.Time `json:"updated_at,omitempty"`
 }
 
+// validateTeamsProxyEndpointIPs ensures every source IP is in CIDR notation.
+func validateTeamsProxyEndpointIPs(ips []string) error {
+	for _, ip := range ips {
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return fmt.Errorf("invalid proxy endpoint IP %q: must be in CIDR notation", ip)
+		}
+	}
+
+	return nil
+}
+
 // TeamsProxyEndpoint returns a single proxy endpoints within an account.
 //
 // API reference: https://api.cloudflare.com/#zero-trust-gateway-proxy-endpoints-proxy-endpoint-details
@@ -67,10 +80,45 @@ func (api *API) TeamsProxyEndpoints(ctx context.Context, accountID string) ([]Te
 	return teamsProxyEndpointListResponse.Result, teamsProxyEndpointListResponse.ResultInfo, nil
 }
 
+// TeamsProxyEndpointsAll returns all proxy endpoints within an account, following the pagination
+// until every page has been fetched. If a page after the first one fails,
+// the proxy endpoints fetched so far are returned along with a *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#zero-trust-gateway-proxy-endpoints-list-proxy-endpoints
+func (api *API) TeamsProxyEndpointsAll(ctx context.Context, accountID string) ([]TeamsProxyEndpoint, error) {
+	uri := fmt.Sprintf("/%s/%s/gateway/proxy_endpoints", AccountRouteRoot, accountID)
+
+	var proxyEndpoints []TeamsProxyEndpoint
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		res, err := api.makeRequestContext(ctx, http.MethodGet, buildURI(uri, PaginationOptions{Page: params.Page, PerPage: params.PerPage}), nil)
+		if err != nil {
+			return 0, ResultInfo{}, err
+		}
+
+		var teamsProxyEndpointListResponse TeamsProxyEndpointListResponse
+		err = json.Unmarshal(res, &teamsProxyEndpointListResponse)
+		if err != nil {
+			return 0, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		}
+
+		proxyEndpoints = append(proxyEndpoints, teamsProxyEndpointListResponse.Result...)
+		return len(teamsProxyEndpointListResponse.Result), teamsProxyEndpointListResponse.ResultInfo, nil
+	})
+	if err != nil && !isPaginationError(err) {
+		return []TeamsProxyEndpoint{}, err
+	}
+
+	return proxyEndpoints, err
+}
+
 // CreateTeamsProxyEndpoint creates a new proxy endpoint.
 //
 // API reference: https://api.cloudflare.com/#zero-trust-gateway-proxy-endpoints-create-proxy-endpoint
 func (api *API) CreateTeamsProxyEndpoint(ctx context.Context, accountID string, proxyEndpoint TeamsProxyEndpoint) (TeamsProxyEndpoint, error) {
+	if err := validateTeamsProxyEndpointIPs(proxyEndpoint.IPs); err != nil {
+		return TeamsProxyEndpoint{}, err
+	}
+
 	uri := fmt.Sprintf("/%s/%s/gateway/proxy_endpoints", AccountRouteRoot, accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, proxyEndpoint)
@@ -95,6 +143,10 @@ func (api *API) UpdateTeamsProxyEndpoint(ctx context.Context, accountID string,
 		return TeamsProxyEndpoint{}, fmt.Errorf("Proxy Endpoint ID cannot be empty")
 	}
 
+	if err := validateTeamsProxyEndpointIPs(proxyEndpoint.IPs); err != nil {
+		return TeamsProxyEndpoint{}, err
+	}
+
 	uri := fmt.Sprintf(
 		"/%s/%s/gateway/proxy_endpoints/%s",
 		AccountRouteRoot,
@@ -134,3 +186,43 @@ func (api *API) DeleteTeamsProxyEndpoint(ctx context.Context, accountID, proxyEn
 
 	return nil
 }
+
+// TeamsProxyEndpointPolicies returns the rules that reference a proxy
+// endpoint. The API doesn't track the relationship so the traffic, identity
+// and device posture expressions of every rule are searched for the
+// endpoint ID or subdomain. Use it to check that nothing references an
+// endpoint before deleting it.
+func (api *API) TeamsProxyEndpointPolicies(ctx context.Context, accountID, proxyEndpointID string) ([]TeamsRule, error) {
+	proxyEndpoint, err := api.TeamsProxyEndpoint(ctx, accountID, proxyEndpointID)
+	if err != nil {
+		return []TeamsRule{}, err
+	}
+
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return []TeamsRule{}, err
+	}
+
+	references := []TeamsRule{}
+	for _, rule := range rules {
+		if teamsRuleReferences(rule, proxyEndpoint.ID, proxyEndpoint.Subdomain) {
+			references = append(references, rule)
+		}
+	}
+
+	return references, nil
+}
+
+// teamsRuleReferences reports whether any of the rule expressions contain
+// one of the non-empty values.
+func teamsRuleReferences(rule TeamsRule, values ...string) bool {
+	for _, expression := range []string{rule.Traffic, rule.Identity, rule.DevicePosture} {
+		for _, value := range values {
+			if value != "" && strings.Contains(expression, value) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
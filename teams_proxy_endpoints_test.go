@@ -142,6 +142,17 @@ func TestCreateProxyEndpoint(t *testing.T) {
 	assert.Equal(t, want, actual)
 }
 
+func TestCreateProxyEndpointWithInvalidIP(t *testing.T) {
+	setup()
+	defer teardown()
+
+	_, err := client.CreateTeamsProxyEndpoint(context.Background(), testAccountID, TeamsProxyEndpoint{
+		Name: "test",
+		IPs:  []string{"192.0.2.1/32", "192.0.2.2"},
+	})
+	assert.EqualError(t, err, `invalid proxy endpoint IP "192.0.2.2": must be in CIDR notation`)
+}
+
 func TestUpdateProxyEndpoint(t *testing.T) {
 	setup()
 	defer teardown()
@@ -201,3 +212,43 @@ func TestDeleteProxyEndpoint(t *testing.T) {
 	err := client.DeleteTeamsProxyEndpoint(context.Background(), testAccountID, id)
 	require.Nil(t, err)
 }
+
+func TestTeamsProxyEndpointPolicies(t *testing.T) {
+	setup()
+	defer teardown()
+
+	id := "0f8185414dec4a5e9034f3d917c17890"
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/gateway/proxy_endpoints/%s", testAccountID, id), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		_, err := fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "0f8185414dec4a5e9034f3d917c17890", "name": "home", "subdomain": "q15l7x2lbw"}
+		}`)
+		require.Nil(t, err)
+	})
+	mux.HandleFunc(fmt.Sprintf("/accounts/%s/gateway/rules", testAccountID), func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		_, err := fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "rule-1", "name": "by id", "traffic": "proxy.endpoint_id == \"0f8185414dec4a5e9034f3d917c17890\""},
+				{"id": "rule-2", "name": "unrelated", "traffic": "http.request.host == \"example.com\""},
+				{"id": "rule-3", "name": "by subdomain", "identity": "", "traffic": "http.request.host == \"q15l7x2lbw.proxy.cloudflare-gateway.com\""}
+			]
+		}`)
+		require.Nil(t, err)
+	})
+
+	actual, err := client.TeamsProxyEndpointPolicies(context.Background(), testAccountID, id)
+	require.Nil(t, err)
+	if assert.Len(t, actual, 2) {
+		assert.Equal(t, "rule-1", actual[0].ID)
+		assert.Equal(t, "rule-3", actual[1].ID)
+	}
+}
@@ -0,0 +1,167 @@
+package cloudflare
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// TeamsExprField is a field that can be used in a Gateway rule expression.
+type TeamsExprField struct {
+	name string
+	// list fields hold several values and are matched with any(...)
+	list bool
+	// id fields hold numeric IDs, such as category or application IDs
+	id bool
+}
+
+// Fields commonly used in Gateway rule expressions.
+var (
+	TeamsExprHTTPHost            = TeamsExprField{name: "http.request.host"}
+	TeamsExprHTTPURI             = TeamsExprField{name: "http.request.uri"}
+	TeamsExprHTTPContentCategory = TeamsExprField{name: "http.request.uri.content_category", list: true, id: true}
+	TeamsExprHTTPApp             = TeamsExprField{name: "app.ids", list: true, id: true}
+	TeamsExprHTTPAppType         = TeamsExprField{name: "app.type.ids", list: true, id: true}
+	TeamsExprDNSQuery            = TeamsExprField{name: "dns.fqdn"}
+	TeamsExprDNSDomains          = TeamsExprField{name: "dns.domains", list: true}
+	TeamsExprDNSContentCategory  = TeamsExprField{name: "dns.content_category", list: true, id: true}
+	TeamsExprDevicePosturePassed = TeamsExprField{name: "device_posture.checks.passed", list: true}
+)
+
+// TeamsExpr is a Gateway rule expression built from fields and operators,
+// for use as the Traffic of a TeamsRule:
+//
+//	traffic, err := TeamsExprHTTPHost.In("example.com", "example.net").
+//		And(TeamsExprHTTPURI.Matches(`^/admin/`)).
+//		Build()
+//
+// Invalid uses, such as a regular expression that doesn't compile or an ID
+// operator on a text field, are reported by Build.
+type TeamsExpr struct {
+	expr     string
+	compound bool
+	err      error
+}
+
+// Eq matches when the field equals value. For list fields any of the values
+// may match.
+func (f TeamsExprField) Eq(value string) TeamsExpr {
+	if f.id {
+		return f.invalid("Eq")
+	}
+	return f.compare("==", teamsExprString(value))
+}
+
+// Matches matches when the field matches the regular expression pattern.
+func (f TeamsExprField) Matches(pattern string) TeamsExpr {
+	if f.id {
+		return f.invalid("Matches")
+	}
+	if _, err := regexp.Compile(pattern); err != nil {
+		return TeamsExpr{err: fmt.Errorf("invalid pattern for %s: %w", f.name, err)}
+	}
+	return f.compare("matches", teamsExprString(pattern))
+}
+
+// In matches when the field is one of values.
+func (f TeamsExprField) In(values ...string) TeamsExpr {
+	if f.id {
+		return f.invalid("In")
+	}
+	if len(values) == 0 {
+		return TeamsExpr{err: fmt.Errorf("%s in requires at least one value", f.name)}
+	}
+
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, teamsExprString(value))
+	}
+	return f.compare("in", "{"+strings.Join(quoted, " ")+"}")
+}
+
+// InIDs matches when the field holds one of ids. It is used with the
+// category and application fields.
+func (f TeamsExprField) InIDs(ids ...int) TeamsExpr {
+	if !f.id {
+		return f.invalid("InIDs")
+	}
+	if len(ids) == 0 {
+		return TeamsExpr{err: fmt.Errorf("%s in requires at least one value", f.name)}
+	}
+
+	values := make([]string, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, strconv.Itoa(id))
+	}
+	return f.compare("in", "{"+strings.Join(values, " ")+"}")
+}
+
+func (f TeamsExprField) compare(operator, value string) TeamsExpr {
+	if f.list {
+		return TeamsExpr{expr: fmt.Sprintf("any(%s[*] %s %s)", f.name, operator, value)}
+	}
+	return TeamsExpr{expr: fmt.Sprintf("%s %s %s", f.name, operator, value)}
+}
+
+func (f TeamsExprField) invalid(operator string) TeamsExpr {
+	return TeamsExpr{err: fmt.Errorf("operator %s can't be used with field %s", operator, f.name)}
+}
+
+// And matches when both e and other match.
+func (e TeamsExpr) And(other TeamsExpr) TeamsExpr {
+	return e.join("and", other)
+}
+
+// Or matches when either e or other match.
+func (e TeamsExpr) Or(other TeamsExpr) TeamsExpr {
+	return e.join("or", other)
+}
+
+func (e TeamsExpr) join(operator string, other TeamsExpr) TeamsExpr {
+	if e.err != nil {
+		return e
+	}
+	if other.err != nil {
+		return other
+	}
+	return TeamsExpr{expr: fmt.Sprintf("%s %s %s", e.grouped(), operator, other.grouped()), compound: true}
+}
+
+func (e TeamsExpr) grouped() string {
+	if e.compound {
+		return "(" + e.expr + ")"
+	}
+	return e.expr
+}
+
+// Build returns the expression, or the first error made while building it.
+func (e TeamsExpr) Build() (string, error) {
+	if e.err != nil {
+		return "", e.err
+	}
+	if e.expr == "" {
+		return "", fmt.Errorf("empty expression")
+	}
+	return e.expr, nil
+}
+
+// String returns the expression, or an empty string when it is invalid.
+func (e TeamsExpr) String() string {
+	if e.err != nil {
+		return ""
+	}
+	return e.expr
+}
+
+// TeamsRequirePostureExpr returns the device posture expression of a rule
+// that only matches devices passing one of the device posture rules with
+// postureRuleIDs. It returns an empty string when postureRuleIDs is empty.
+func TeamsRequirePostureExpr(postureRuleIDs []string) string {
+	return TeamsExprDevicePosturePassed.In(postureRuleIDs...).String()
+}
+
+// teamsExprString quotes s as an expression string literal.
+func teamsExprString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
@@ -0,0 +1,44 @@
+package cloudflare
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsExpr(t *testing.T) {
+	expr, err := TeamsExprHTTPHost.In("example.com", "example.net").
+		And(TeamsExprHTTPURI.Matches(`^/admin/`)).
+		Build()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `http.request.host in {"example.com" "example.net"} and http.request.uri matches "^/admin/"`, expr)
+	}
+
+	expr, err = TeamsExprDNSContentCategory.InIDs(80, 83).
+		Or(TeamsExprDNSQuery.Eq(`quote".example.com`)).
+		And(TeamsExprDNSDomains.Eq("example.org")).
+		Build()
+	if assert.NoError(t, err) {
+		assert.Equal(t, `(any(dns.content_category[*] in {80 83}) or dns.fqdn == "quote\".example.com") and any(dns.domains[*] == "example.org")`, expr)
+	}
+
+	_, err = TeamsExprHTTPApp.Eq("slack").Build()
+	assert.EqualError(t, err, "operator Eq can't be used with field app.ids")
+
+	_, err = TeamsExprHTTPHost.InIDs(1).Build()
+	assert.EqualError(t, err, "operator InIDs can't be used with field http.request.host")
+
+	_, err = TeamsExprHTTPHost.Eq("example.com").And(TeamsExprHTTPURI.Matches("(")).Build()
+	assert.ErrorContains(t, err, "invalid pattern for http.request.uri")
+
+	_, err = TeamsExprHTTPHost.In().Build()
+	assert.EqualError(t, err, "http.request.host in requires at least one value")
+
+	_, err = TeamsExpr{}.Build()
+	assert.EqualError(t, err, "empty expression")
+}
+
+func TestTeamsRequirePostureExpr(t *testing.T) {
+	assert.Equal(t, `any(device_posture.checks.passed[*] in {"a1b2" "c3d4"})`, TeamsRequirePostureExpr([]string{"a1b2", "c3d4"}))
+	assert.Equal(t, "", TeamsRequirePostureExpr(nil))
+}
@@ -0,0 +1,273 @@
+package cloudflare
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// hclBlock is a block of Terraform configuration. Attribute values are
+// already encoded as HCL expressions.
+type hclBlock struct {
+	header   string
+	attrs    [][2]string
+	blocks   []*hclBlock
+	comments []string
+}
+
+func (b *hclBlock) attr(name, value string) {
+	b.attrs = append(b.attrs, [2]string{name, value})
+}
+
+func (b *hclBlock) block(header string) *hclBlock {
+	child := &hclBlock{header: header}
+	b.blocks = append(b.blocks, child)
+	return child
+}
+
+// write renders the block the way terraform fmt does, with the equal signs
+// of the attributes aligned.
+func (b *hclBlock) write(sb *strings.Builder, indent string) {
+	sb.WriteString(indent + b.header + " {\n")
+
+	width := 0
+	for _, attr := range b.attrs {
+		if len(attr[0]) > width {
+			width = len(attr[0])
+		}
+	}
+	for _, attr := range b.attrs {
+		fmt.Fprintf(sb, "%s  %-*s = %s\n", indent, width, attr[0], attr[1])
+	}
+
+	for _, block := range b.blocks {
+		if len(block.attrs) == 0 && len(block.blocks) == 0 {
+			continue
+		}
+		sb.WriteString("\n")
+		block.write(sb, indent+"  ")
+	}
+
+	for _, comment := range b.comments {
+		sb.WriteString("\n" + indent + "  # " + comment + "\n")
+	}
+
+	sb.WriteString(indent + "}\n")
+}
+
+// hclString quotes s as an HCL string literal, escaping template sequences
+// so that s is used as is.
+func hclString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == '"':
+			sb.WriteString(`\"`)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case (r == '$' || r == '%') && strings.HasPrefix(s[i+1:], "{"):
+			// ${ and %{ start template sequences, doubling escapes them
+			sb.WriteRune(r)
+			sb.WriteRune(r)
+		case unicode.IsControl(r):
+			fmt.Fprintf(&sb, `\u%04x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+func hclStringList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, value := range values {
+		quoted = append(quoted, hclString(value))
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// hclIdentifier turns name into a Terraform resource name.
+func hclIdentifier(name string) string {
+	var sb strings.Builder
+	underscore := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			sb.WriteRune(r)
+			underscore = false
+			continue
+		}
+		if !underscore {
+			sb.WriteByte('_')
+			underscore = true
+		}
+	}
+
+	id := strings.Trim(sb.String(), "_")
+	if id == "" || (id[0] >= '0' && id[0] <= '9') || id[0] == '-' {
+		id = "rule_" + id
+	}
+	return id
+}
+
+// TeamsRuleToHCL returns a cloudflare_teams_rule resource of the Cloudflare
+// Terraform provider describing rule, for instance to write the
+// configuration of rules created in the dashboard before importing them
+// into the Terraform state. The resource is named after the rule and its
+// account_id is set to var.account_id. Fields assigned by the API, such as
+// the ID, version and timestamps, are left out. The provider has no
+// equivalent for the schedule and expiration of a rule, which are noted in
+// a comment instead. Headers with several values are joined with commas as
+// the provider takes one value per header.
+func TeamsRuleToHCL(rule TeamsRule) (string, error) {
+	if rule.Name == "" {
+		return "", errors.New("rule name is required")
+	}
+
+	resource := &hclBlock{header: fmt.Sprintf("resource \"cloudflare_teams_rule\" %s", hclString(hclIdentifier(rule.Name)))}
+	resource.attr("account_id", "var.account_id")
+	resource.attr("name", hclString(rule.Name))
+	resource.attr("description", hclString(rule.Description))
+	resource.attr("precedence", strconv.FormatUint(rule.Precedence, 10))
+	resource.attr("enabled", strconv.FormatBool(rule.Enabled))
+	resource.attr("action", hclString(string(rule.Action)))
+	if len(rule.Filters) > 0 {
+		filters := make([]string, 0, len(rule.Filters))
+		for _, filter := range rule.Filters {
+			filters = append(filters, string(filter))
+		}
+		resource.attr("filters", hclStringList(filters))
+	}
+	if rule.Traffic != "" {
+		resource.attr("traffic", hclString(rule.Traffic))
+	}
+	if rule.Identity != "" {
+		resource.attr("identity", hclString(rule.Identity))
+	}
+	if rule.DevicePosture != "" {
+		resource.attr("device_posture", hclString(rule.DevicePosture))
+	}
+
+	writeTeamsRuleSettingsHCL(resource.block("rule_settings"), rule.RuleSettings)
+
+	if rule.Schedule != nil {
+		resource.comments = append(resource.comments, "the rule schedule isn't supported by cloudflare_teams_rule and was left out")
+	}
+	if rule.Expiration != nil {
+		resource.comments = append(resource.comments, "the rule expiration isn't supported by cloudflare_teams_rule and was left out")
+	}
+
+	var sb strings.Builder
+	resource.write(&sb, "")
+	return sb.String(), nil
+}
+
+func writeTeamsRuleSettingsHCL(b *hclBlock, settings TeamsRuleSettings) {
+	if settings.BlockPageEnabled {
+		b.attr("block_page_enabled", "true")
+	}
+	if settings.BlockReason != "" {
+		b.attr("block_page_reason", hclString(settings.BlockReason))
+	}
+	if len(settings.OverrideIPs) > 0 {
+		b.attr("override_ips", hclStringList(settings.OverrideIPs))
+	}
+	if settings.OverrideHost != "" {
+		b.attr("override_host", hclString(settings.OverrideHost))
+	}
+	if settings.InsecureDisableDNSSECValidation {
+		b.attr("insecure_disable_dnssec_validation", "true")
+	}
+	if settings.ResolveDnsThroughCloudflare != nil {
+		b.attr("resolve_dns_through_cloudflare", strconv.FormatBool(*settings.ResolveDnsThroughCloudflare))
+	}
+	if len(settings.AddHeaders) > 0 {
+		names := make([]string, 0, len(settings.AddHeaders))
+		for name := range settings.AddHeaders {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		headers := make([]string, 0, len(names))
+		for _, name := range names {
+			headers = append(headers, fmt.Sprintf("%s = %s", hclString(name), hclString(strings.Join(settings.AddHeaders[name], ","))))
+		}
+		b.attr("add_headers", "{ "+strings.Join(headers, ", ")+" }")
+	}
+
+	if l4 := settings.L4Override; l4 != nil {
+		block := b.block("l4override")
+		block.attr("ip", hclString(l4.IP))
+		block.attr("port", strconv.Itoa(l4.Port))
+	}
+
+	if biso := settings.BISOAdminControls; biso != nil {
+		block := b.block("biso_admin_controls")
+		block.attr("disable_printing", strconv.FormatBool(biso.DisablePrinting))
+		block.attr("disable_copy_paste", strconv.FormatBool(biso.DisableCopyPaste))
+		block.attr("disable_clipboard_redirection", strconv.FormatBool(biso.DisableClipboardRedirection))
+		block.attr("disable_download", strconv.FormatBool(biso.DisableDownload))
+		block.attr("disable_upload", strconv.FormatBool(biso.DisableUpload))
+		block.attr("disable_keyboard", strconv.FormatBool(biso.DisableKeyboard))
+	}
+
+	if session := settings.CheckSession; session != nil {
+		block := b.block("check_session")
+		block.attr("enforce", strconv.FormatBool(session.Enforce))
+		block.attr("duration", hclString(fmt.Sprintf("%ds", int64(session.Duration.Seconds()))))
+	}
+
+	if egress := settings.Egress; egress != nil {
+		block := b.block("egress")
+		block.attr("ipv4", hclString(egress.IPv4))
+		block.attr("ipv6", hclString(egress.IPv6))
+		if egress.IPv4Fallback != "" {
+			block.attr("ipv4_fallback", hclString(egress.IPv4Fallback))
+		}
+	}
+
+	if notification := settings.NotificationSettings; notification != nil {
+		block := b.block("notification_settings")
+		if notification.Enabled != nil {
+			block.attr("enabled", strconv.FormatBool(*notification.Enabled))
+		}
+		if notification.Message != "" {
+			block.attr("message", hclString(notification.Message))
+		}
+		if notification.SupportURL != "" {
+			block.attr("support_url", hclString(notification.SupportURL))
+		}
+	}
+
+	if resolvers := settings.DnsResolvers; resolvers != nil {
+		block := b.block("dns_resolvers")
+		for _, family := range []struct {
+			name      string
+			addresses []TeamsDnsResolverAddress
+		}{{"ipv4", resolvers.IPV4}, {"ipv6", resolvers.IPV6}} {
+			for _, address := range family.addresses {
+				resolver := block.block(family.name)
+				resolver.attr("ip", hclString(address.IP))
+				if address.Port != nil {
+					resolver.attr("port", strconv.Itoa(*address.Port))
+				}
+				if address.VnetID != "" {
+					resolver.attr("vnet_id", hclString(address.VnetID))
+				}
+				if address.RouteThroughPrivateNetwork != nil {
+					resolver.attr("route_through_private_network", strconv.FormatBool(*address.RouteThroughPrivateNetwork))
+				}
+			}
+		}
+	}
+}
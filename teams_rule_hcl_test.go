@@ -0,0 +1,73 @@
+package cloudflare
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsRuleToHCL(t *testing.T) {
+	createdAt, _ := time.Parse(time.RFC3339, "2022-10-01T12:00:00Z")
+
+	hcl, err := TeamsRuleToHCL(TeamsRule{
+		ID:            "7559a944-3dd7-41bf-b183-360a814a8c36",
+		CreatedAt:     &createdAt,
+		Name:          "Block \"bad\" sites",
+		Description:   "Blocks ${var} sites\nfor everyone",
+		Precedence:    1000,
+		Enabled:       true,
+		Action:        Block,
+		Filters:       []TeamsFilterType{HttpFilter},
+		Traffic:       `http.request.uri matches ".*a/partial/uri.*"`,
+		Version:       2,
+		DevicePosture: "",
+		RuleSettings: TeamsRuleSettings{
+			BlockPageEnabled: true,
+			BlockReason:      "100% blocked",
+			AddHeaders:       http.Header{"X-Foo": {"a", "b"}},
+			CheckSession: &TeamsCheckSessionSettings{
+				Enforce:  true,
+				Duration: Duration{5 * time.Minute},
+			},
+		},
+		Schedule: &TeamsRuleSchedule{TimeZone: "America/New_York"},
+	})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, `resource "cloudflare_teams_rule" "block_bad_sites" {
+  account_id  = var.account_id
+  name        = "Block \"bad\" sites"
+  description = "Blocks $${var} sites\nfor everyone"
+  precedence  = 1000
+  enabled     = true
+  action      = "block"
+  filters     = ["http"]
+  traffic     = "http.request.uri matches \".*a/partial/uri.*\""
+
+  rule_settings {
+    block_page_enabled = true
+    block_page_reason  = "100% blocked"
+    add_headers        = { "X-Foo" = "a,b" }
+
+    check_session {
+      enforce  = true
+      duration = "300s"
+    }
+  }
+
+  # the rule schedule isn't supported by cloudflare_teams_rule and was left out
+}
+`, hcl)
+	}
+
+	_, err = TeamsRuleToHCL(TeamsRule{})
+	assert.EqualError(t, err, "rule name is required")
+}
+
+func TestHCLIdentifier(t *testing.T) {
+	assert.Equal(t, "block_malware", hclIdentifier("Block Malware!"))
+	assert.Equal(t, "rule_1st-rule", hclIdentifier("1st-rule"))
+	assert.Equal(t, "rule_", hclIdentifier("***"))
+}
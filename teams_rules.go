@@ -3,11 +3,28 @@ package cloudflare
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 )
 
+var ErrMissingRuleID = errors.New("required missing rule ID")
+
+// TeamsRuleConflictErrorCode is the internal error code returned when a rule
+// conflicts with an existing one, such as a duplicate precedence.
+const TeamsRuleConflictErrorCode = 2001
+
+// IsTeamsRuleConflict returns whether err is an API error reporting a rule
+// conflict.
+func IsTeamsRuleConflict(err error) bool {
+	return ErrorCodeIs(err, TeamsRuleConflictErrorCode)
+}
+
 type TeamsRuleSettings struct {
 	// list of ipv4 or ipv6 ips to override with, when action is set to dns override
 	OverrideIPs []string `json:"override_ips"`
@@ -24,7 +41,9 @@ type TeamsRuleSettings struct {
 	// settings for l4(network) level overrides
 	L4Override *TeamsL4OverrideSettings `json:"l4override"`
 
-	// settings for adding headers to http requests
+	// settings for adding headers to http requests. Header names must be
+	// valid and can't be hop-by-hop headers such as Connection, which
+	// wouldn't reach the origin.
 	AddHeaders http.Header `json:"add_headers"`
 
 	// settings for session check in allow action
@@ -35,20 +54,173 @@ type TeamsRuleSettings struct {
 
 	// whether to disable dnssec validation for allow action
 	InsecureDisableDNSSECValidation bool `json:"insecure_disable_dnssec_validation"`
+
+	// custom resolvers to forward queries to when action is set to resolve
+	DnsResolvers *TeamsDnsResolverSettings `json:"dns_resolvers,omitempty"`
+
+	// whether to resolve with Cloudflare's public resolver when action is set to resolve
+	ResolveDnsThroughCloudflare *bool `json:"resolve_dns_through_cloudflare,omitempty"`
+
+	// dedicated egress IPs to use when action is set to egress
+	Egress *TeamsEgressSettings `json:"egress,omitempty"`
+
+	// notification shown by the WARP client when the rule matches. Gateway
+	// has no warn and continue action, a notification only informs the user
+	// and doesn't ask them to acknowledge anything before proceeding.
+	NotificationSettings *TeamsNotificationSettings `json:"notification_settings,omitempty"`
+}
+
+// TeamsEgressSettings selects the dedicated egress IPs of an egress rule.
+// Egress policies mapping traffic to dedicated egress IPs aren't a resource
+// of their own, there's no /gateway/egress_policies endpoint: they are
+// Gateway rules with the egress action and these settings, managed with
+// TeamsRules, TeamsCreateRule, TeamsUpdateRule and TeamsDeleteRule.
+// IPv4 is an egress IPv4 address and IPv6 an egress IPv6 /64 range, and
+// IPv4Fallback is used when the IPv4 address is unavailable. The API
+// doesn't expose which egress IPs are allocated to an account so only their
+// format is checked before sending.
+type TeamsEgressSettings struct {
+	IPv4         string `json:"ipv4,omitempty"`
+	IPv6         string `json:"ipv6,omitempty"`
+	IPv4Fallback string `json:"ipv4_fallback,omitempty"`
+}
+
+// Validate checks that IPv4 and IPv4Fallback are IPv4 addresses and that
+// IPv6 is an IPv6 range in CIDR notation.
+func (s TeamsEgressSettings) Validate() error {
+	for _, ip := range []string{s.IPv4, s.IPv4Fallback} {
+		if ip == "" {
+			continue
+		}
+		if parsed := net.ParseIP(ip); parsed == nil || parsed.To4() == nil {
+			return fmt.Errorf("invalid egress IPv4 address %q", ip)
+		}
+	}
+
+	if s.IPv6 != "" {
+		ip, _, err := net.ParseCIDR(s.IPv6)
+		if err != nil || ip.To4() != nil {
+			return fmt.Errorf("invalid egress IPv6 range %q: must be in CIDR notation", s.IPv6)
+		}
+	}
+
+	if s.IPv4 == "" && s.IPv6 == "" {
+		return errors.New("egress settings must set an IPv4 address or an IPv6 range")
+	}
+
+	return nil
+}
+
+// TeamsDnsResolverSettings lists the custom resolvers a resolve rule forwards
+// matching DNS queries to. Resolvers are tried in order and the first
+// healthy one answers, so the order of each list is kept as given; when no
+// IPv4 resolver is healthy the IPv6 ones are used.
+type TeamsDnsResolverSettings struct {
+	IPV4 []TeamsDnsResolverAddress `json:"ipv4,omitempty"`
+	IPV6 []TeamsDnsResolverAddress `json:"ipv6,omitempty"`
+}
+
+// Validate checks that there is at least one resolver, that the IPV4 and
+// IPV6 resolvers have addresses of their IP family and that ports are valid.
+func (s TeamsDnsResolverSettings) Validate() error {
+	if len(s.IPV4) == 0 && len(s.IPV6) == 0 {
+		return errors.New("dns resolver settings must have at least one resolver")
+	}
+
+	families := []struct {
+		family    TeamsIPFamily
+		resolvers []TeamsDnsResolverAddress
+	}{
+		{TeamsIPv4, s.IPV4},
+		{TeamsIPv6, s.IPV6},
+	}
+
+	for _, f := range families {
+		for _, resolver := range f.resolvers {
+			ip := net.ParseIP(resolver.IP)
+			if ip == nil || (ip.To4() != nil) != (f.family == TeamsIPv4) {
+				return fmt.Errorf("invalid %s dns resolver IP %q", f.family, resolver.IP)
+			}
+			if resolver.Port != nil && (*resolver.Port < 1 || *resolver.Port > 65535) {
+				return fmt.Errorf("invalid dns resolver port %d: must be between 1 and 65535", *resolver.Port)
+			}
+		}
+	}
+
+	return nil
+}
+
+// TeamsDnsResolverAddress is a custom DNS resolver. Private resolvers are
+// reached through the virtual network VnetID when RouteThroughPrivateNetwork
+// is set, such as a resolver behind Magic WAN.
+//
+// Gateway forwards queries to custom resolvers over plain DNS only, the API
+// has no DNS over HTTPS or DNS over TLS option for resolvers. Keeping the
+// queries to an on-premises resolver private requires routing them through
+// a private network with RouteThroughPrivateNetwork.
+type TeamsDnsResolverAddress struct {
+	IP                         string `json:"ip"`
+	Port                       *int   `json:"port,omitempty"`
+	VnetID                     string `json:"vnet_id,omitempty"`
+	RouteThroughPrivateNetwork *bool  `json:"route_through_private_network,omitempty"`
 }
 
 // TeamsL4OverrideSettings used in l4 filter type rule with action set to override.
+// IP may be an IPv4 or IPv6 address, and a Port of 0 keeps the destination
+// port of the original traffic. The API takes a single IP per rule, so
+// dual-stack traffic needs one rule per IP family.
 type TeamsL4OverrideSettings struct {
 	IP   string `json:"ip,omitempty"`
 	Port int    `json:"port,omitempty"`
 }
 
+// TeamsIPFamily is the IP version of an address.
+type TeamsIPFamily = string
+
+const (
+	TeamsIPv4 TeamsIPFamily = "ipv4"
+	TeamsIPv6 TeamsIPFamily = "ipv6"
+)
+
+// IPFamily returns whether IP is an IPv4 or an IPv6 address.
+func (s TeamsL4OverrideSettings) IPFamily() (TeamsIPFamily, error) {
+	ip := net.ParseIP(s.IP)
+	if ip == nil {
+		return "", fmt.Errorf("invalid l4 override IP %q", s.IP)
+	}
+
+	if ip.To4() != nil {
+		return TeamsIPv4, nil
+	}
+
+	return TeamsIPv6, nil
+}
+
+// Validate checks that IP is an IPv4 or IPv6 address and that Port is
+// between 0 and 65535.
+func (s TeamsL4OverrideSettings) Validate() error {
+	if _, err := s.IPFamily(); err != nil {
+		return err
+	}
+
+	if s.Port < 0 || s.Port > 65535 {
+		return fmt.Errorf("invalid l4 override port %d: must be between 0 and 65535", s.Port)
+	}
+
+	return nil
+}
+
+// TeamsBISOAdminControlSettings are the controls applied to sessions isolated
+// by an isolate rule. DisableClipboardRedirection stops the clipboard being
+// shared between the local device and the isolated browser, while
+// DisableCopyPaste stops copy and paste within the isolated page.
 type TeamsBISOAdminControlSettings struct {
-	DisablePrinting  bool `json:"dp"`
-	DisableCopyPaste bool `json:"dcp"`
-	DisableDownload  bool `json:"dd"`
-	DisableUpload    bool `json:"du"`
-	DisableKeyboard  bool `json:"dk"`
+	DisablePrinting             bool `json:"dp"`
+	DisableCopyPaste            bool `json:"dcp"`
+	DisableClipboardRedirection bool `json:"dcr"`
+	DisableDownload             bool `json:"dd"`
+	DisableUpload               bool `json:"du"`
+	DisableKeyboard             bool `json:"dk"`
 }
 
 type TeamsCheckSessionSettings struct {
@@ -64,6 +236,8 @@ const (
 	HttpFilter TeamsFilterType = "http"
 	DnsFilter  TeamsFilterType = "dns"
 	L4Filter   TeamsFilterType = "l4"
+	// EgressFilter selects the traffic of egress rules.
+	EgressFilter TeamsFilterType = "egress"
 )
 
 const (
@@ -79,8 +253,19 @@ const (
 	NoIsolate    TeamsGatewayAction = "noisolate"
 	Override     TeamsGatewayAction = "override"
 	L4Override   TeamsGatewayAction = "l4_override"
+	Resolve      TeamsGatewayAction = "resolve"
+	Egress       TeamsGatewayAction = "egress"
 )
 
+// teamsRuleFilterActions lists the actions that rules with each filter can
+// take. The API accepts other combinations but such rules never match.
+var teamsRuleFilterActions = map[TeamsFilterType][]TeamsGatewayAction{
+	DnsFilter:    {Allow, Block, SafeSearch, YTRestricted, Override, Resolve},
+	HttpFilter:   {Allow, Block, On, Off, Scan, NoScan, Isolate, NoIsolate},
+	L4Filter:     {Allow, Block, L4Override},
+	EgressFilter: {Egress},
+}
+
 func TeamsRulesActionValues() []string {
 	return []string{
 		string(Allow),
@@ -95,6 +280,8 @@ func TeamsRulesActionValues() []string {
 		string(NoIsolate),
 		string(Override),
 		string(L4Override),
+		string(Resolve),
+		string(Egress),
 	}
 }
 
@@ -115,6 +302,130 @@ type TeamsRule struct {
 	DevicePosture string             `json:"device_posture"`
 	Version       uint64             `json:"version"`
 	RuleSettings  TeamsRuleSettings  `json:"rule_settings,omitempty"`
+	Schedule      *TeamsRuleSchedule `json:"schedule,omitempty"`
+
+	// Expiration disables the rule once it passes, for temporary rules
+	// such as those created during an incident.
+	Expiration *TeamsRuleExpiration `json:"expiration,omitempty"`
+}
+
+// TeamsRuleExpiration sets when a rule stops applying. Duration is the
+// number of minutes the rule applies after it is enabled and is an
+// alternative to ExpiresAt. Expired is set by the API.
+type TeamsRuleExpiration struct {
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	Duration  int        `json:"duration,omitempty"`
+	Expired   bool       `json:"expired,omitempty"`
+}
+
+// Validate checks that an expiration time, unless already expired, is in
+// the future and that the duration isn't negative.
+func (e TeamsRuleExpiration) Validate() error {
+	if e.Duration < 0 {
+		return fmt.Errorf("invalid rule expiration duration %d: must not be negative", e.Duration)
+	}
+
+	if e.ExpiresAt != nil && !e.Expired && !e.ExpiresAt.After(time.Now()) {
+		return fmt.Errorf("invalid rule expiration %s: must be in the future", e.ExpiresAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// TeamsRuleSchedule restricts when a rule is active. Each day holds a comma
+// separated list of HH:MM-HH:MM time ranges, such as "08:00-12:30,13:30-17:00",
+// and a rule without ranges for a day is inactive on that day. TimeZone is
+// an IANA time zone name; when empty the user's time zone is used.
+type TeamsRuleSchedule struct {
+	Monday    string `json:"mon,omitempty"`
+	Tuesday   string `json:"tue,omitempty"`
+	Wednesday string `json:"wed,omitempty"`
+	Thursday  string `json:"thu,omitempty"`
+	Friday    string `json:"fri,omitempty"`
+	Saturday  string `json:"sat,omitempty"`
+	Sunday    string `json:"sun,omitempty"`
+	TimeZone  string `json:"time_zone,omitempty"`
+}
+
+var teamsRuleScheduleRangeRegexp = regexp.MustCompile(`^((?:[01]\d|2[0-3]):[0-5]\d)-((?:[01]\d|2[0-3]):[0-5]\d|24:00)$`)
+
+// Validate checks that every day of the schedule is a list of well formed
+// time ranges that end after they start.
+func (s TeamsRuleSchedule) Validate() error {
+	days := []struct {
+		name   string
+		ranges string
+	}{
+		{"mon", s.Monday},
+		{"tue", s.Tuesday},
+		{"wed", s.Wednesday},
+		{"thu", s.Thursday},
+		{"fri", s.Friday},
+		{"sat", s.Saturday},
+		{"sun", s.Sunday},
+	}
+
+	for _, day := range days {
+		if day.ranges == "" {
+			continue
+		}
+
+		for _, timeRange := range strings.Split(day.ranges, ",") {
+			match := teamsRuleScheduleRangeRegexp.FindStringSubmatch(timeRange)
+			if match == nil {
+				return fmt.Errorf("invalid schedule for %s: %q is not a HH:MM-HH:MM time range", day.name, timeRange)
+			}
+			// zero padded times sort lexically
+			if match[1] >= match[2] {
+				return fmt.Errorf("invalid schedule for %s: time range %q must end after it starts", day.name, timeRange)
+			}
+		}
+	}
+
+	return nil
+}
+
+// NewTeamsL4OverrideRule returns an enabled network rule that redirects
+// matching traffic to ip and port, where a port of 0 keeps the original
+// destination port. The Traffic expression selecting what to redirect is
+// left for the caller to set.
+func NewTeamsL4OverrideRule(name string, ip string, port int) (TeamsRule, error) {
+	override := &TeamsL4OverrideSettings{IP: ip, Port: port}
+	if err := override.Validate(); err != nil {
+		return TeamsRule{}, err
+	}
+
+	return TeamsRule{
+		Name:    name,
+		Enabled: true,
+		Action:  L4Override,
+		Filters: []TeamsFilterType{L4Filter},
+		RuleSettings: TeamsRuleSettings{
+			L4Override: override,
+		},
+	}, nil
+}
+
+// NewTeamsEgressRule returns an enabled egress rule that sends matching
+// traffic out through the dedicated egress IPs of egress. The Traffic,
+// Identity or DevicePosture expressions selecting the source of the
+// traffic, such as its source IPs, are left for the caller to set. The rule
+// is the egress policy: create it with TeamsCreateRule, as the API has no
+// separate egress policy endpoint.
+func NewTeamsEgressRule(name string, egress TeamsEgressSettings) (TeamsRule, error) {
+	if err := egress.Validate(); err != nil {
+		return TeamsRule{}, err
+	}
+
+	return TeamsRule{
+		Name:    name,
+		Enabled: true,
+		Action:  Egress,
+		Filters: []TeamsFilterType{EgressFilter},
+		RuleSettings: TeamsRuleSettings{
+			Egress: &egress,
+		},
+	}, nil
 }
 
 // TeamsRuleResponse is the API response, containing a single rule.
@@ -126,9 +437,16 @@ type TeamsRuleResponse struct {
 // TeamsRuleResponse is the API response, containing an array of rules.
 type TeamsRulesResponse struct {
 	Response
-	Result []TeamsRule `json:"result"`
+	ResultInfo `json:"result_info"`
+	Result     []TeamsRule `json:"result"`
 }
 
+// TeamsRulesPartialError is returned by TeamsRulesAll when a page after the
+// first one fails. It is the *PaginationError returned by every method
+// fetching all pages; the rules fetched before the failure are returned
+// along with it.
+type TeamsRulesPartialError = PaginationError
+
 // TeamsRulePatchRequest is used to patch an existing rule.
 type TeamsRulePatchRequest struct {
 	ID           string             `json:"id"`
@@ -140,30 +458,493 @@ type TeamsRulePatchRequest struct {
 	RuleSettings TeamsRuleSettings  `json:"rule_settings,omitempty"`
 }
 
+// TeamsRuleOption is a functional option for configuring how a rule is
+// created or updated.
+type TeamsRuleOption func(opt *teamsRuleOption)
+
+type teamsRuleOption struct {
+	validateListReferences bool
+	precedence             func(rules []TeamsRule) (uint64, error)
+	retrySafeCreate        bool
+	devicePosture          bool
+	postureRuleIDs         []string
+}
+
+// WithTeamsRuleListValidation checks that every list referenced by the rule
+// expressions exists before the rule is sent to the API. It costs one
+// request per referenced list.
+func WithTeamsRuleListValidation() TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.validateListReferences = true
+	}
+}
+
+// WithRetrySafeCreate makes TeamsCreateRule safe to retry after a failure
+// that leaves it unknown whether the rule was created. The API doesn't
+// support idempotency keys, so rules are matched by name instead: an
+// existing rule with the same name is returned rather than creating a
+// duplicate, both before the create and when the create request fails.
+func WithRetrySafeCreate() TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.retrySafeCreate = true
+	}
+}
+
+// WithDevicePosture sets the device posture expression of the rule to
+// require passing one of the device posture rules with postureRuleIDs, see
+// TeamsRequirePostureExpr. The posture rules are checked to exist in the
+// account before the rule is sent to the API.
+func WithDevicePosture(postureRuleIDs ...string) TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.devicePosture = true
+		opt.postureRuleIDs = postureRuleIDs
+	}
+}
+
+// WithLowestPrecedence places the rule after every existing rule, so it is
+// evaluated last.
+func WithLowestPrecedence() TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.precedence = func(rules []TeamsRule) (uint64, error) {
+			if len(rules) == 0 {
+				return teamsRulePrecedenceStep, nil
+			}
+			return rules[len(rules)-1].Precedence + teamsRulePrecedenceStep, nil
+		}
+	}
+}
+
+// WithInsertAfter places the rule directly after the rule with ruleID.
+func WithInsertAfter(ruleID string) TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.precedence = func(rules []TeamsRule) (uint64, error) {
+			i, err := teamsRuleIndex(rules, ruleID)
+			if err != nil {
+				return 0, err
+			}
+			if i == len(rules)-1 {
+				return rules[i].Precedence + teamsRulePrecedenceStep, nil
+			}
+			return teamsRulePrecedenceBetween(rules[i], rules[i+1])
+		}
+	}
+}
+
+// WithInsertBefore places the rule directly before the rule with ruleID.
+func WithInsertBefore(ruleID string) TeamsRuleOption {
+	return func(opt *teamsRuleOption) {
+		opt.precedence = func(rules []TeamsRule) (uint64, error) {
+			i, err := teamsRuleIndex(rules, ruleID)
+			if err != nil {
+				return 0, err
+			}
+			if i == 0 {
+				return teamsRulePrecedenceBetween(TeamsRule{}, rules[0])
+			}
+			return teamsRulePrecedenceBetween(rules[i-1], rules[i])
+		}
+	}
+}
+
+func teamsRuleIndex(rules []TeamsRule, ruleID string) (int, error) {
+	for i, rule := range rules {
+		if rule.ID == ruleID {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("cannot position rule relative to %s: rule not found", ruleID)
+}
+
+// teamsRulePrecedenceBetween returns the precedence halfway between two
+// adjacent rules.
+func teamsRulePrecedenceBetween(before, after TeamsRule) (uint64, error) {
+	if after.Precedence-before.Precedence < 2 {
+		return 0, fmt.Errorf("no free precedence between %d and %d, reorder the rules with TeamsReorderRules first", before.Precedence, after.Precedence)
+	}
+
+	return before.Precedence + (after.Precedence-before.Precedence)/2, nil
+}
+
+// TeamsMissingListsError is returned when a rule references lists that
+// don't exist within the account.
+type TeamsMissingListsError struct {
+	ListIDs []string
+}
+
+func (e TeamsMissingListsError) Error() string {
+	return fmt.Sprintf("rule references missing teams lists: %s", strings.Join(e.ListIDs, ", "))
+}
+
+var teamsListReferenceRegexp = regexp.MustCompile(`\$([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+// TeamsRuleListReferences returns the IDs of all lists referenced (as
+// `$<listID>`) in the traffic, identity and device posture expressions.
+func TeamsRuleListReferences(rule TeamsRule) []string {
+	seen := make(map[string]bool)
+	ids := []string{}
+	for _, expression := range []string{rule.Traffic, rule.Identity, rule.DevicePosture} {
+		for _, match := range teamsListReferenceRegexp.FindAllStringSubmatch(expression, -1) {
+			if !seen[match[1]] {
+				seen[match[1]] = true
+				ids = append(ids, match[1])
+			}
+		}
+	}
+
+	return ids
+}
+
+// validateTeamsRule checks the rule settings that the API would otherwise
+// reject with an unhelpful error.
+func validateTeamsRule(rule TeamsRule) error {
+	if rule.Schedule != nil {
+		if err := rule.Schedule.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Expiration != nil {
+		if err := rule.Expiration.Validate(); err != nil {
+			return err
+		}
+	}
+
+	settings := rule.RuleSettings
+	if settings.L4Override != nil {
+		if err := settings.L4Override.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if settings.Egress != nil {
+		if rule.Action != Egress {
+			return fmt.Errorf("egress settings can only be set on rules with the %s action", Egress)
+		}
+		if err := settings.Egress.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if err := validateTeamsRuleAddHeaders(settings.AddHeaders); err != nil {
+		return err
+	}
+
+	if settings.NotificationSettings != nil {
+		if err := settings.NotificationSettings.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if settings.DnsResolvers != nil {
+		if err := settings.DnsResolvers.Validate(); err != nil {
+			return err
+		}
+	}
+
+	if rule.Action == Resolve && settings.DnsResolvers == nil && (settings.ResolveDnsThroughCloudflare == nil || !*settings.ResolveDnsThroughCloudflare) {
+		return errors.New("resolve rules must set dns resolvers or resolve dns through cloudflare")
+	}
+
+	if settings.OverrideHost != "" || len(settings.OverrideIPs) > 0 || settings.ResolveDnsThroughCloudflare != nil {
+		dns := false
+		for _, filter := range rule.Filters {
+			if filter == DnsFilter {
+				dns = true
+			}
+		}
+		if !dns {
+			return errors.New("dns override and resolve settings can only be set on rules with the dns filter")
+		}
+	}
+
+	return validateTeamsRuleFilterActions(rule)
+}
+
+// teamsHopByHopHeaders are the headers that apply to a single connection
+// and can't be added to requests by a rule.
+var teamsHopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// validateTeamsRuleAddHeaders returns an error for the first header, in
+// name order, that is invalid or hop-by-hop.
+func validateTeamsRuleAddHeaders(headers http.Header) error {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !validHeaderName(name) {
+			return fmt.Errorf("invalid header name %q", name)
+		}
+		if contains(teamsHopByHopHeaders, http.CanonicalHeaderKey(name)) {
+			return fmt.Errorf("hop-by-hop header %s can't be added to requests", http.CanonicalHeaderKey(name))
+		}
+	}
+
+	return nil
+}
+
+// validHeaderName returns whether name is a token as defined by RFC 7230.
+func validHeaderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case strings.ContainsRune("!#$%&'*+-.^_`|~", c):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// validateTeamsRuleFilterActions returns an error if the action of the rule
+// can't be taken by rules with one of its filters. Filters and actions not
+// in teamsRuleFilterActions aren't checked.
+func validateTeamsRuleFilterActions(rule TeamsRule) error {
+	if rule.Action == "" {
+		return nil
+	}
+
+	for _, filter := range rule.Filters {
+		actions, ok := teamsRuleFilterActions[filter]
+		if !ok || !contains(TeamsRulesActionValues(), string(rule.Action)) {
+			continue
+		}
+
+		valid := false
+		for _, action := range actions {
+			if action == rule.Action {
+				valid = true
+			}
+		}
+		if !valid {
+			return fmt.Errorf("action %s can't be used with the %s filter", rule.Action, filter)
+		}
+	}
+
+	return nil
+}
+
+// validateTeamsRuleListReferences returns a TeamsMissingListsError if any of
+// the lists the rule references can't be found.
+func (api *API) validateTeamsRuleListReferences(ctx context.Context, accountID string, rule TeamsRule) error {
+	var missing []string
+	for _, listID := range TeamsRuleListReferences(rule) {
+		_, err := api.TeamsList(ctx, accountID, listID)
+		if err != nil {
+			var notFoundErr *NotFoundError
+			if errors.As(err, &notFoundErr) {
+				missing = append(missing, listID)
+				continue
+			}
+			return err
+		}
+	}
+
+	if len(missing) > 0 {
+		return TeamsMissingListsError{ListIDs: missing}
+	}
+
+	return nil
+}
+
+func newTeamsRuleOption(opts []TeamsRuleOption) teamsRuleOption {
+	opt := teamsRuleOption{}
+	for _, of := range opts {
+		of(&opt)
+	}
+	return opt
+}
+
+func (api *API) applyTeamsRuleOptions(ctx context.Context, accountID string, rule TeamsRule, opts []TeamsRuleOption) (TeamsRule, error) {
+	opt := newTeamsRuleOption(opts)
+
+	if opt.precedence != nil {
+		rules, err := api.TeamsRulesAll(ctx, accountID)
+		if err != nil {
+			return TeamsRule{}, err
+		}
+
+		// the rule being updated doesn't count as a neighbour of itself
+		others := make([]TeamsRule, 0, len(rules))
+		for _, r := range rules {
+			if rule.ID == "" || r.ID != rule.ID {
+				others = append(others, r)
+			}
+		}
+		sort.SliceStable(others, func(i, j int) bool { return others[i].Precedence < others[j].Precedence })
+
+		precedence, err := opt.precedence(others)
+		if err != nil {
+			return TeamsRule{}, err
+		}
+		rule.Precedence = precedence
+	}
+
+	if opt.devicePosture {
+		if err := api.validateTeamsRulePostureReferences(ctx, accountID, opt.postureRuleIDs); err != nil {
+			return TeamsRule{}, err
+		}
+		rule.DevicePosture = TeamsRequirePostureExpr(opt.postureRuleIDs)
+	}
+
+	if opt.validateListReferences {
+		if err := api.validateTeamsRuleListReferences(ctx, accountID, rule); err != nil {
+			return TeamsRule{}, err
+		}
+	}
+
+	return rule, nil
+}
+
+// validateTeamsRulePostureReferences returns an error if any of the device
+// posture rules with postureRuleIDs can't be found.
+func (api *API) validateTeamsRulePostureReferences(ctx context.Context, accountID string, postureRuleIDs []string) error {
+	if len(postureRuleIDs) == 0 {
+		return errors.New("device posture requires at least one posture rule ID")
+	}
+
+	postureRules, err := api.DevicePostureRulesAll(ctx, accountID)
+	if err != nil {
+		return err
+	}
+
+	ids := make([]string, 0, len(postureRules))
+	for _, postureRule := range postureRules {
+		ids = append(ids, postureRule.ID)
+	}
+
+	for _, id := range postureRuleIDs {
+		if !contains(ids, id) {
+			return fmt.Errorf("unknown device posture rule %q", id)
+		}
+	}
+
+	return nil
+}
+
 // TeamsRules returns all rules within an account.
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
 func (api *API) TeamsRules(ctx context.Context, accountID string) ([]TeamsRule, error) {
+	rules, _, err := api.TeamsRulesWithInfo(ctx, accountID)
+	return rules, err
+}
+
+// TeamsRulesWithInfo returns all rules within an account along with the
+// result info, whose TotalCount reports the number of rules in the account.
+//
+// API reference: https://api.cloudflare.com/#teams-rules-properties
+func (api *API) TeamsRulesWithInfo(ctx context.Context, accountID string) ([]TeamsRule, ResultInfo, error) {
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules", accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
 	if err != nil {
+		return []TeamsRule{}, ResultInfo{}, err
+	}
+
+	var teamsRulesResponse TeamsRulesResponse
+	err = json.Unmarshal(res, &teamsRulesResponse)
+	if err != nil {
+		return []TeamsRule{}, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsRulesResponse.Result, teamsRulesResponse.ResultInfo, nil
+}
+
+// TeamsRuleCount returns the number of rules within an account, read from
+// the total count of the rules list. The API doesn't expose the rule limit
+// of the account, so callers enforcing a cap have to compare the count with
+// the limit of their plan themselves.
+func (api *API) TeamsRuleCount(ctx context.Context, accountID string) (int, error) {
+	rules, resultInfo, err := api.TeamsRulesWithInfo(ctx, accountID)
+	if err != nil {
+		return 0, err
+	}
+
+	if resultInfo.Total == 0 {
+		return len(rules), nil
+	}
+
+	return resultInfo.Total, nil
+}
+
+// TeamsRulesAll returns all rules within an account, following the
+// pagination until every page has been fetched. If a page after the first
+// one fails, the rules fetched so far are returned along with a
+// *PaginationError.
+//
+// API reference: https://api.cloudflare.com/#teams-rules-properties
+func (api *API) TeamsRulesAll(ctx context.Context, accountID string) ([]TeamsRule, error) {
+	var rules []TeamsRule
+	err := fetchAllPages(ctx, 50, func(params ResultInfo) (int, ResultInfo, error) {
+		rulesPage, resultInfo, err := api.teamsRulesPage(ctx, accountID, params)
+		rules = append(rules, rulesPage...)
+		return len(rulesPage), resultInfo, err
+	})
+	if err != nil && !isPaginationError(err) {
 		return []TeamsRule{}, err
 	}
 
+	return rules, err
+}
+
+func (api *API) teamsRulesPage(ctx context.Context, accountID string, params ResultInfo) ([]TeamsRule, ResultInfo, error) {
+	uri := buildURI(fmt.Sprintf("/accounts/%s/gateway/rules", accountID), params)
+
+	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return []TeamsRule{}, ResultInfo{}, err
+	}
+
 	var teamsRulesResponse TeamsRulesResponse
 	err = json.Unmarshal(res, &teamsRulesResponse)
 	if err != nil {
-		return []TeamsRule{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+		return []TeamsRule{}, ResultInfo{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsRulesResponse.Result, teamsRulesResponse.ResultInfo, nil
+}
+
+// teamsRuleByName returns the rule within an account with the given name.
+func (api *API) teamsRuleByName(ctx context.Context, accountID, name string) (TeamsRule, bool, error) {
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return TeamsRule{}, false, err
 	}
 
-	return teamsRulesResponse.Result, nil
+	for _, rule := range rules {
+		if rule.Name == name {
+			return rule, true, nil
+		}
+	}
+
+	return TeamsRule{}, false, nil
 }
 
 // TeamsRule returns the rule with rule ID in the URL.
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
 func (api *API) TeamsRule(ctx context.Context, accountID string, ruleId string) (TeamsRule, error) {
+	if ruleId == "" {
+		return TeamsRule{}, ErrMissingRuleID
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
 
 	res, err := api.makeRequestContext(ctx, http.MethodGet, uri, nil)
@@ -183,11 +964,36 @@ func (api *API) TeamsRule(ctx context.Context, accountID string, ruleId string)
 // TeamsCreateRule creates a rule with wirefilter expression.
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
-func (api *API) TeamsCreateRule(ctx context.Context, accountID string, rule TeamsRule) (TeamsRule, error) {
+func (api *API) TeamsCreateRule(ctx context.Context, accountID string, rule TeamsRule, opts ...TeamsRuleOption) (TeamsRule, error) {
+	if err := validateTeamsRule(rule); err != nil {
+		return TeamsRule{}, err
+	}
+
+	retrySafe := newTeamsRuleOption(opts).retrySafeCreate
+	if retrySafe {
+		existing, found, err := api.teamsRuleByName(ctx, accountID, rule.Name)
+		if err != nil {
+			return TeamsRule{}, err
+		}
+		if found {
+			return existing, nil
+		}
+	}
+
+	rule, err := api.applyTeamsRuleOptions(ctx, accountID, rule, opts)
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules", accountID)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPost, uri, rule)
 	if err != nil {
+		if retrySafe {
+			if existing, found, findErr := api.teamsRuleByName(ctx, accountID, rule.Name); findErr == nil && found {
+				return existing, nil
+			}
+		}
 		return TeamsRule{}, err
 	}
 
@@ -200,10 +1006,35 @@ func (api *API) TeamsCreateRule(ctx context.Context, accountID string, rule Team
 	return teamsRuleResponse.Result, nil
 }
 
+// TeamsCreateTemporaryRule creates a rule that expires ttl from now.
+func (api *API) TeamsCreateTemporaryRule(ctx context.Context, accountID string, rule TeamsRule, ttl time.Duration, opts ...TeamsRuleOption) (TeamsRule, error) {
+	if ttl <= 0 {
+		return TeamsRule{}, fmt.Errorf("invalid rule time to live %s: must be positive", ttl)
+	}
+
+	expiresAt := time.Now().Add(ttl).UTC()
+	rule.Expiration = &TeamsRuleExpiration{ExpiresAt: &expiresAt}
+
+	return api.TeamsCreateRule(ctx, accountID, rule, opts...)
+}
+
 // TeamsUpdateRule updates a rule with wirefilter expression.
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
-func (api *API) TeamsUpdateRule(ctx context.Context, accountID string, ruleId string, rule TeamsRule) (TeamsRule, error) {
+func (api *API) TeamsUpdateRule(ctx context.Context, accountID string, ruleId string, rule TeamsRule, opts ...TeamsRuleOption) (TeamsRule, error) {
+	if ruleId == "" {
+		return TeamsRule{}, ErrMissingRuleID
+	}
+
+	if err := validateTeamsRule(rule); err != nil {
+		return TeamsRule{}, err
+	}
+
+	rule, err := api.applyTeamsRuleOptions(ctx, accountID, rule, opts)
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPut, uri, rule)
@@ -224,6 +1055,10 @@ func (api *API) TeamsUpdateRule(ctx context.Context, accountID string, ruleId st
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
 func (api *API) TeamsPatchRule(ctx context.Context, accountID string, ruleId string, rule TeamsRulePatchRequest) (TeamsRule, error) {
+	if ruleId == "" {
+		return TeamsRule{}, ErrMissingRuleID
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
 
 	res, err := api.makeRequestContext(ctx, http.MethodPatch, uri, rule)
@@ -240,10 +1075,53 @@ func (api *API) TeamsPatchRule(ctx context.Context, accountID string, ruleId str
 	return teamsRuleResponse.Result, nil
 }
 
+// TeamsEnableRule enables a rule. Only the enabled field is patched, so
+// concurrent changes to the rest of the rule are kept.
+//
+// API reference: https://api.cloudflare.com/#teams-rules-properties
+func (api *API) TeamsEnableRule(ctx context.Context, accountID string, ruleId string) (TeamsRule, error) {
+	return api.teamsSetRuleEnabled(ctx, accountID, ruleId, true)
+}
+
+// TeamsDisableRule disables a rule. Only the enabled field is patched, so
+// concurrent changes to the rest of the rule are kept.
+//
+// API reference: https://api.cloudflare.com/#teams-rules-properties
+func (api *API) TeamsDisableRule(ctx context.Context, accountID string, ruleId string) (TeamsRule, error) {
+	return api.teamsSetRuleEnabled(ctx, accountID, ruleId, false)
+}
+
+func (api *API) teamsSetRuleEnabled(ctx context.Context, accountID string, ruleId string, enabled bool) (TeamsRule, error) {
+	if ruleId == "" {
+		return TeamsRule{}, ErrMissingRuleID
+	}
+
+	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPatch, uri, struct {
+		Enabled bool `json:"enabled"`
+	}{enabled})
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
+	var teamsRuleResponse TeamsRuleResponse
+	err = json.Unmarshal(res, &teamsRuleResponse)
+	if err != nil {
+		return TeamsRule{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsRuleResponse.Result, nil
+}
+
 // TeamsDeleteRule deletes a rule.
 //
 // API reference: https://api.cloudflare.com/#teams-rules-properties
 func (api *API) TeamsDeleteRule(ctx context.Context, accountID string, ruleId string) error {
+	if ruleId == "" {
+		return ErrMissingRuleID
+	}
+
 	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
 
 	_, err := api.makeRequestContext(ctx, http.MethodDelete, uri, nil)
@@ -253,3 +1131,167 @@ func (api *API) TeamsDeleteRule(ctx context.Context, accountID string, ruleId st
 
 	return nil
 }
+
+// teamsRulePrecedenceStep is the gap left between rule precedences assigned
+// by TeamsReorderRules so single rules can be inserted in between later.
+const teamsRulePrecedenceStep = 1000
+
+// TeamsReorderRules assigns ascending precedences to the account's rules in
+// the order of orderedRuleIDs, which must list every existing rule exactly
+// once. The rules are first moved above all current precedences and then
+// onto their final values, so no update collides with another rule's
+// precedence. Only the precedence of each rule is patched, so the rest of
+// the rules is neither validated nor changed.
+//
+// The moves aren't atomic. When one fails the error is returned and the
+// rules moved so far are left where they are, some of them at temporary
+// precedences above all others; calling TeamsReorderRules again with the
+// same order completes the reorder.
+//
+// API reference: https://api.cloudflare.com/#teams-rules-properties
+func (api *API) TeamsReorderRules(ctx context.Context, accountID string, orderedRuleIDs []string) ([]TeamsRule, error) {
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return []TeamsRule{}, err
+	}
+
+	existing := make(map[string]TeamsRule, len(rules))
+	var maxPrecedence uint64
+	for _, rule := range rules {
+		existing[rule.ID] = rule
+		if rule.Precedence > maxPrecedence {
+			maxPrecedence = rule.Precedence
+		}
+	}
+
+	seen := make(map[string]bool, len(orderedRuleIDs))
+	var duplicates, unknown []string
+	for _, id := range orderedRuleIDs {
+		if seen[id] {
+			duplicates = append(duplicates, id)
+			continue
+		}
+		seen[id] = true
+		if _, ok := existing[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+	if len(duplicates) > 0 {
+		return []TeamsRule{}, fmt.Errorf("duplicate rule IDs in order: %s", strings.Join(duplicates, ", "))
+	}
+	if len(unknown) > 0 {
+		return []TeamsRule{}, fmt.Errorf("unknown rule IDs in order: %s", strings.Join(unknown, ", "))
+	}
+
+	var missing []string
+	for _, rule := range rules {
+		if !seen[rule.ID] {
+			missing = append(missing, rule.ID)
+		}
+	}
+	if len(missing) > 0 {
+		return []TeamsRule{}, fmt.Errorf("rule IDs missing from order: %s", strings.Join(missing, ", "))
+	}
+
+	temporaryBase := uint64(len(orderedRuleIDs)) * teamsRulePrecedenceStep
+	if maxPrecedence > temporaryBase {
+		temporaryBase = maxPrecedence
+	}
+
+	for i, id := range orderedRuleIDs {
+		if _, err := api.teamsSetRulePrecedence(ctx, accountID, id, temporaryBase+uint64(i)+1); err != nil {
+			return []TeamsRule{}, err
+		}
+	}
+
+	reordered := make([]TeamsRule, 0, len(orderedRuleIDs))
+	for i, id := range orderedRuleIDs {
+		updated, err := api.teamsSetRulePrecedence(ctx, accountID, id, uint64(i+1)*teamsRulePrecedenceStep)
+		if err != nil {
+			return []TeamsRule{}, err
+		}
+		reordered = append(reordered, updated)
+	}
+
+	return reordered, nil
+}
+
+// teamsSetRulePrecedence patches only the precedence of a rule.
+func (api *API) teamsSetRulePrecedence(ctx context.Context, accountID string, ruleId string, precedence uint64) (TeamsRule, error) {
+	uri := fmt.Sprintf("/accounts/%s/gateway/rules/%s", accountID, ruleId)
+
+	res, err := api.makeRequestContext(ctx, http.MethodPatch, uri, struct {
+		Precedence uint64 `json:"precedence"`
+	}{precedence})
+	if err != nil {
+		return TeamsRule{}, err
+	}
+
+	var teamsRuleResponse TeamsRuleResponse
+	err = json.Unmarshal(res, &teamsRuleResponse)
+	if err != nil {
+		return TeamsRule{}, fmt.Errorf("%s: %w", errUnmarshalError, err)
+	}
+
+	return teamsRuleResponse.Result, nil
+}
+
+// TeamsFindRulesByDescription returns all rules within an account whose
+// description contains substring. The match is case-insensitive.
+func (api *API) TeamsFindRulesByDescription(ctx context.Context, accountID string, substring string) ([]TeamsRule, error) {
+	return api.TeamsFindRulesByDescriptionRegexp(ctx, accountID, regexp.MustCompile("(?i)"+regexp.QuoteMeta(substring)))
+}
+
+// TeamsFindRulesByDescriptionRegexp returns all rules within an account whose
+// description matches re.
+func (api *API) TeamsFindRulesByDescriptionRegexp(ctx context.Context, accountID string, re *regexp.Regexp) ([]TeamsRule, error) {
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return []TeamsRule{}, err
+	}
+
+	matches := []TeamsRule{}
+	for _, rule := range rules {
+		if re.MatchString(rule.Description) {
+			matches = append(matches, rule)
+		}
+	}
+
+	return matches, nil
+}
+
+// TeamsSetRulesEnabledError holds the errors of the rules that
+// TeamsSetRulesEnabledWhere failed to enable or disable.
+type TeamsSetRulesEnabledError = BatchError
+
+// TeamsSetRulesEnabledWhere enables or disables every rule for which match
+// returns true and returns the IDs of the rules that were changed. Matching
+// rules already in the requested state are left alone. A failed update
+// doesn't stop the others; the failures are returned in a
+// *TeamsSetRulesEnabledError along with the IDs that were changed.
+func (api *API) TeamsSetRulesEnabledWhere(ctx context.Context, accountID string, match func(TeamsRule) bool, enabled bool) ([]string, error) {
+	rules, err := api.TeamsRulesAll(ctx, accountID)
+	if err != nil {
+		return []string{}, err
+	}
+
+	changed := []string{}
+	var errs []error
+	for _, rule := range rules {
+		if rule.Enabled == enabled || !match(rule) {
+			continue
+		}
+
+		if _, err := api.teamsSetRuleEnabled(ctx, accountID, rule.ID, enabled); err != nil {
+			errs = append(errs, fmt.Errorf("updating rule %s (%s): %w", rule.Name, rule.ID, err))
+			continue
+		}
+		changed = append(changed, rule.ID)
+	}
+
+	if len(errs) > 0 {
+		return changed, &TeamsSetRulesEnabledError{Errors: errs}
+	}
+
+	return changed, nil
+}
@@ -2,8 +2,13 @@ package cloudflare
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"regexp"
+	"strings"
 	"testing"
 	"time"
 
@@ -325,6 +330,45 @@ func TestTeamsCreateRule(t *testing.T) {
 	}
 }
 
+func TestTeamsCreateRuleRetrySafe(t *testing.T) {
+	setup()
+	defer teardown()
+
+	lookups, creates := 0, 0
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			lookups++
+			if lookups == 1 {
+				fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": []}`)
+				return
+			}
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": [{"id": "7559a944-3dd7-41bf-b183-360a814a8c36", "name": "rule1"}]}`)
+			return
+		}
+
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		creates++
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{"success": false, "errors": [{"code": 1000, "message": "connection reset"}], "messages": [], "result": null}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, err := client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "rule1", Action: Block}, WithRetrySafeCreate())
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{ID: "7559a944-3dd7-41bf-b183-360a814a8c36", Name: "rule1"}, actual)
+		assert.Equal(t, 1, creates)
+	}
+
+	// the rule now exists, so a retry doesn't create it again
+	actual, err = client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "rule1", Action: Block}, WithRetrySafeCreate())
+	if assert.NoError(t, err) {
+		assert.Equal(t, "7559a944-3dd7-41bf-b183-360a814a8c36", actual.ID)
+		assert.Equal(t, 1, creates)
+	}
+}
+
 func TestTeamsUpdateRule(t *testing.T) {
 	setup()
 	defer teardown()
@@ -405,6 +449,63 @@ func TestTeamsUpdateRule(t *testing.T) {
 	}
 }
 
+func TestTeamsEnableDisableRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	var body string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(b)
+		var patch TeamsRule
+		assert.NoError(t, json.Unmarshal(b, &patch))
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "7559a944-3dd7-41bf-b183-360a814a8c36", "name": "rule1", "enabled": %t}
+		}`, patch.Enabled)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/7559a944-3dd7-41bf-b183-360a814a8c36", handler)
+
+	actual, err := client.TeamsDisableRule(context.Background(), testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"enabled":false}`, body)
+		assert.False(t, actual.Enabled)
+	}
+
+	actual, err = client.TeamsEnableRule(context.Background(), testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36")
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"enabled":true}`, body)
+		assert.True(t, actual.Enabled)
+	}
+
+	_, err = client.TeamsEnableRule(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingRuleID, err)
+}
+
+func TestTeamsBISOAdminControlSettingsRoundTrip(t *testing.T) {
+	controls := TeamsBISOAdminControlSettings{
+		DisablePrinting:             true,
+		DisableClipboardRedirection: true,
+		DisableUpload:               true,
+	}
+
+	b, err := json.Marshal(controls)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{"dp":true,"dcp":false,"dcr":true,"dd":false,"du":true,"dk":false}`, string(b))
+	}
+
+	var actual TeamsBISOAdminControlSettings
+	if assert.NoError(t, json.Unmarshal(b, &actual)) {
+		assert.Equal(t, controls, actual)
+	}
+}
+
 func TestTeamsPatchRule(t *testing.T) {
 	setup()
 	defer teardown()
@@ -492,3 +593,885 @@ func TestTeamsDeleteRule(t *testing.T) {
 
 	assert.NoError(t, err)
 }
+
+func TestTeamsDeleteRuleWithMissingID(t *testing.T) {
+	setup()
+	defer teardown()
+
+	err := client.TeamsDeleteRule(context.Background(), testAccountID, "")
+	assert.Equal(t, ErrMissingRuleID, err)
+}
+
+func TestTeamsRuleSettingsMarshal(t *testing.T) {
+	settings := TeamsRuleSettings{
+		BlockPageEnabled: true,
+		BlockReason:      "not allowed",
+		OverrideIPs:      []string{"192.0.2.1", "2001:db8::1"},
+		OverrideHost:     "example.com",
+	}
+
+	b, err := json.Marshal(settings)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"block_page_enabled": true,
+			"block_reason": "not allowed",
+			"override_ips": ["192.0.2.1", "2001:db8::1"],
+			"override_host": "example.com",
+			"biso_admin_controls": null,
+			"l4override": null,
+			"add_headers": null,
+			"check_session": null,
+			"insecure_disable_dnssec_validation": false
+		}`, string(b))
+	}
+}
+
+func TestTeamsRuleSettingsDnsResolversMarshal(t *testing.T) {
+	rule := TeamsRule{
+		Name:    "forward internal zone",
+		Action:  Resolve,
+		Filters: []TeamsFilterType{DnsFilter},
+		Traffic: `any(dns.domains[*] == "corp.example.com")`,
+		RuleSettings: TeamsRuleSettings{
+			DnsResolvers: &TeamsDnsResolverSettings{
+				IPV4: []TeamsDnsResolverAddress{{
+					IP:                         "10.0.0.53",
+					Port:                       IntPtr(5053),
+					VnetID:                     "f174e90a-fafe-4643-bbbc-4a0ed4fc8415",
+					RouteThroughPrivateNetwork: BoolPtr(true),
+				}},
+				IPV6: []TeamsDnsResolverAddress{{IP: "2001:db8::53"}},
+			},
+		},
+	}
+
+	b, err := json.Marshal(rule.RuleSettings.DnsResolvers)
+	if assert.NoError(t, err) {
+		assert.JSONEq(t, `{
+			"ipv4": [{"ip": "10.0.0.53", "port": 5053, "vnet_id": "f174e90a-fafe-4643-bbbc-4a0ed4fc8415", "route_through_private_network": true}],
+			"ipv6": [{"ip": "2001:db8::53"}]
+		}`, string(b))
+	}
+
+	var decoded TeamsRule
+	b, err = json.Marshal(rule)
+	if assert.NoError(t, err) && assert.NoError(t, json.Unmarshal(b, &decoded)) {
+		assert.Equal(t, rule, decoded)
+	}
+}
+
+func TestTeamsRuleDnsResolversValidation(t *testing.T) {
+	rule := TeamsRule{
+		Action:  Resolve,
+		Filters: []TeamsFilterType{DnsFilter},
+		RuleSettings: TeamsRuleSettings{
+			DnsResolvers: &TeamsDnsResolverSettings{
+				IPV4: []TeamsDnsResolverAddress{{IP: "10.0.0.53"}, {IP: "10.0.1.53", Port: IntPtr(5053)}},
+				IPV6: []TeamsDnsResolverAddress{{IP: "2001:db8::53"}},
+			},
+		},
+	}
+	assert.NoError(t, validateTeamsRule(rule))
+
+	// the order of the resolvers is kept
+	var decoded TeamsRule
+	b, err := json.Marshal(rule)
+	if assert.NoError(t, err) && assert.NoError(t, json.Unmarshal(b, &decoded)) {
+		assert.Equal(t, "10.0.0.53", decoded.RuleSettings.DnsResolvers.IPV4[0].IP)
+		assert.Equal(t, "10.0.1.53", decoded.RuleSettings.DnsResolvers.IPV4[1].IP)
+	}
+
+	rule.RuleSettings.DnsResolvers = &TeamsDnsResolverSettings{}
+	assert.EqualError(t, validateTeamsRule(rule), "dns resolver settings must have at least one resolver")
+
+	rule.RuleSettings.DnsResolvers = &TeamsDnsResolverSettings{IPV4: []TeamsDnsResolverAddress{{IP: "2001:db8::53"}}}
+	assert.EqualError(t, validateTeamsRule(rule), `invalid ipv4 dns resolver IP "2001:db8::53"`)
+
+	rule.RuleSettings.DnsResolvers = &TeamsDnsResolverSettings{IPV6: []TeamsDnsResolverAddress{{IP: "2001:db8::53", Port: IntPtr(0)}}}
+	assert.EqualError(t, validateTeamsRule(rule), "invalid dns resolver port 0: must be between 1 and 65535")
+
+	rule.RuleSettings.DnsResolvers = nil
+	assert.EqualError(t, validateTeamsRule(rule), "resolve rules must set dns resolvers or resolve dns through cloudflare")
+
+	rule.RuleSettings.ResolveDnsThroughCloudflare = BoolPtr(true)
+	assert.NoError(t, validateTeamsRule(rule))
+}
+
+func TestTeamsRuleFilterActionValidation(t *testing.T) {
+	for _, rule := range []TeamsRule{
+		{Action: Isolate, Filters: []TeamsFilterType{HttpFilter}},
+		{Action: L4Override, Filters: []TeamsFilterType{L4Filter}, RuleSettings: TeamsRuleSettings{L4Override: &TeamsL4OverrideSettings{IP: "10.0.0.1", Port: 443}}},
+		{Action: SafeSearch, Filters: []TeamsFilterType{DnsFilter}},
+		{Action: Block, Filters: []TeamsFilterType{DnsFilter, HttpFilter, L4Filter}},
+		{Action: "audit_ssh", Filters: []TeamsFilterType{L4Filter}},
+	} {
+		assert.NoError(t, validateTeamsRule(rule), "%s with %v", rule.Action, rule.Filters)
+	}
+
+	err := validateTeamsRule(TeamsRule{Action: Isolate, Filters: []TeamsFilterType{DnsFilter}})
+	assert.EqualError(t, err, "action isolate can't be used with the dns filter")
+
+	err = validateTeamsRule(TeamsRule{Action: L4Override, Filters: []TeamsFilterType{HttpFilter}})
+	assert.EqualError(t, err, "action l4_override can't be used with the http filter")
+
+	err = validateTeamsRule(TeamsRule{Action: SafeSearch, Filters: []TeamsFilterType{DnsFilter, HttpFilter}})
+	assert.EqualError(t, err, "action safesearch can't be used with the http filter")
+}
+
+func TestTeamsRuleAddHeadersValidation(t *testing.T) {
+	rule := TeamsRule{
+		Action:  Allow,
+		Filters: []TeamsFilterType{HttpFilter},
+		RuleSettings: TeamsRuleSettings{
+			AddHeaders: http.Header{"X-User-Email": {"user@example.com"}, "X-Team": {"platform"}},
+		},
+	}
+	assert.NoError(t, validateTeamsRule(rule))
+
+	rule.RuleSettings.AddHeaders = http.Header{"X-Team": {"platform"}, "connection": {"close"}}
+	assert.EqualError(t, validateTeamsRule(rule), "hop-by-hop header Connection can't be added to requests")
+
+	rule.RuleSettings.AddHeaders = http.Header{"X User": {"user@example.com"}}
+	assert.EqualError(t, validateTeamsRule(rule), `invalid header name "X User"`)
+}
+
+func TestTeamsRuleNotificationSettings(t *testing.T) {
+	rule := TeamsRule{
+		Action:  Block,
+		Filters: []TeamsFilterType{HttpFilter},
+		RuleSettings: TeamsRuleSettings{
+			NotificationSettings: &TeamsNotificationSettings{
+				Enabled:    BoolPtr(true),
+				Message:    "This site is blocked",
+				SupportURL: "https://it.example.com/blocked",
+			},
+		},
+	}
+	assert.NoError(t, validateTeamsRule(rule))
+
+	b, err := json.Marshal(rule.RuleSettings)
+	if assert.NoError(t, err) {
+		var settings map[string]json.RawMessage
+		if assert.NoError(t, json.Unmarshal(b, &settings)) {
+			assert.JSONEq(t, `{"enabled":true,"msg":"This site is blocked","support_url":"https://it.example.com/blocked"}`, string(settings["notification_settings"]))
+		}
+	}
+
+	rule.RuleSettings.NotificationSettings.SupportURL = "it.example.com"
+	assert.EqualError(t, validateTeamsRule(rule), `invalid notification support URL "it.example.com": must be an absolute http or https URL`)
+}
+
+func TestTeamsFindRulesByDescription(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprintf(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [
+					{
+					  "id": "7559a944-3dd7-41bf-b183-360a814a8c36",
+					  "name": "rule1",
+					  "description": "Block gambling [SEC-1234]",
+					  "precedence": 1000,
+					  "action": "block",
+					  "filters": ["dns"]
+					}
+				],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}
+			`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{
+				  "id": "9ae57318-f32e-46b3-b889-48dd6dcc49af",
+				  "name": "rule2",
+				  "description": "Isolate news [ops-42]",
+				  "precedence": 2000,
+				  "action": "isolate",
+				  "filters": ["http"]
+				}
+			],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}
+		`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, err := client.TeamsFindRulesByDescription(context.Background(), testAccountID, "sec-1234")
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 1)
+		assert.Equal(t, "7559a944-3dd7-41bf-b183-360a814a8c36", actual[0].ID)
+	}
+
+	actual, err = client.TeamsFindRulesByDescriptionRegexp(context.Background(), testAccountID, regexp.MustCompile(`\[[a-z]+-\d+\]`))
+	if assert.NoError(t, err) {
+		assert.Len(t, actual, 1)
+		assert.Equal(t, "9ae57318-f32e-46b3-b889-48dd6dcc49af", actual[0].ID)
+	}
+}
+
+func TestTeamsCreateRuleWithListValidation(t *testing.T) {
+	setup()
+	defer teardown()
+
+	existingListID := "0d25a8c6-6d8b-4d3b-9a6c-2a5bd4ad6b2f"
+	missingListID := "4f8d3a4e-93b0-4bd5-a3c4-2b2fdc7b5c6e"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+existingListID, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "name": "My list", "type": "DOMAIN"}
+		}`, existingListID)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/lists/"+missingListID, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprintf(w, `{
+			"success": false,
+			"errors": [{"code": 2003, "message": "list not found"}],
+			"messages": [],
+			"result": null
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("rule should not be created when referenced lists are missing")
+	})
+
+	rule := TeamsRule{
+		Name:    "block listed domains",
+		Action:  Block,
+		Filters: []TeamsFilterType{DnsFilter},
+		Traffic: fmt.Sprintf("any(dns.domains[*] in $%s) or any(dns.domains[*] in $%s)", existingListID, missingListID),
+	}
+
+	assert.Equal(t, []string{existingListID, missingListID}, TeamsRuleListReferences(rule))
+
+	_, err := client.TeamsCreateRule(context.Background(), testAccountID, rule, WithTeamsRuleListValidation())
+	assert.Equal(t, TeamsMissingListsError{ListIDs: []string{missingListID}}, err)
+}
+
+func TestTeamsCreateRuleWithDevicePosture(t *testing.T) {
+	setup()
+	defer teardown()
+
+	postureRuleID := "0f6ef5e4-8d2c-4c8a-a4a7-1a5b0e7f1c2d"
+	unknownPostureRuleID := "9a7b5c3d-1e2f-4a6b-8c9d-0e1f2a3b4c5d"
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/devices/posture", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Query().Get("page") == "1" {
+			fmt.Fprint(w, `{
+				"success": true,
+				"errors": [],
+				"messages": [],
+				"result": [{"id": "1c2d3e4f-5a6b-4c7d-8e9f-0a1b2c3d4e5f", "type": "disk_encryption", "name": "Disk encrypted"}],
+				"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+			}`)
+			return
+		}
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "%s", "type": "firewall", "name": "Firewall enabled"}],
+			"result_info": {"page": 2, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`, postureRuleID)
+	})
+
+	creates := 0
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		creates++
+		var rule TeamsRule
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rule))
+		assert.Equal(t, `any(device_posture.checks.passed[*] in {"`+postureRuleID+`"})`, rule.DevicePosture)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "7559a944-3dd7-41bf-b183-360a814a8c36"}}`)
+	})
+
+	rule := TeamsRule{
+		Name:    "require firewall",
+		Action:  Allow,
+		Filters: []TeamsFilterType{HttpFilter},
+		Traffic: `http.request.host == "example.com"`,
+	}
+
+	_, err := client.TeamsCreateRule(context.Background(), testAccountID, rule, WithDevicePosture(postureRuleID))
+	assert.NoError(t, err)
+
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, rule, WithDevicePosture(postureRuleID, unknownPostureRuleID))
+	assert.EqualError(t, err, `unknown device posture rule "`+unknownPostureRuleID+`"`)
+
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, rule, WithDevicePosture())
+	assert.EqualError(t, err, "device posture requires at least one posture rule ID")
+
+	assert.Equal(t, 1, creates)
+}
+
+func TestTeamsCreateRuleConflict(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `{
+			"success": false,
+			"errors": [{"code": 2001, "message": "rule precedence conflict"}],
+			"messages": [],
+			"result": null
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	_, err := client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "rule1", Precedence: 1000})
+
+	assert.True(t, IsTeamsRuleConflict(err))
+	assert.Equal(t, []int{TeamsRuleConflictErrorCode}, ErrorCodes(err))
+}
+
+func TestTeamsRulesAll(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+
+		page := r.URL.Query().Get("page")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "rule-%s", "name": "rule%s"}],
+			"result_info": {"page": %s, "per_page": 1, "count": 1, "total_count": 2, "total_pages": 2}
+		}`, page, page, page)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, err := client.TeamsRulesAll(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsRule{{ID: "rule-1", Name: "rule1"}, {ID: "rule-2", Name: "rule2"}}, actual)
+	}
+}
+
+func TestTeamsRulesAllPartialError(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+
+		if r.URL.Query().Get("page") != "1" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprintf(w, `{
+				"success": false,
+				"errors": [{"code": 1000, "message": "bad page"}],
+				"messages": [],
+				"result": null
+			}`)
+			return
+		}
+
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "rule-1", "name": "rule1"}],
+			"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 3, "total_pages": 3}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, err := client.TeamsRulesAll(context.Background(), testAccountID)
+
+	var partialErr *TeamsRulesPartialError
+	if assert.ErrorAs(t, err, &partialErr) {
+		assert.Equal(t, 2, partialErr.Page)
+		assert.Equal(t, []TeamsRule{{ID: "rule-1", Name: "rule1"}}, actual)
+		assert.True(t, ErrorCodeIs(err, 1000))
+	}
+}
+
+func TestTeamsReorderRules(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "rule-1", "name": "rule1", "precedence": 1000},
+				{"id": "rule-2", "name": "rule2", "precedence": 2000},
+				{"id": "rule-3", "name": "rule3", "precedence": 3000}
+			],
+			"result_info": {"page": 1, "per_page": 50, "count": 3, "total_count": 3, "total_pages": 1}
+		}`)
+	})
+
+	var updates []string
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+
+		var patch map[string]uint64
+		assert.NoError(t, json.Unmarshal(body, &patch))
+		assert.Len(t, patch, 1)
+		id := strings.TrimPrefix(r.URL.Path, "/accounts/"+testAccountID+"/gateway/rules/")
+		updates = append(updates, fmt.Sprintf("%s=%d", id, patch["precedence"]))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "%s", "precedence": %d}
+		}`, id, patch["precedence"])
+	})
+
+	actual, err := client.TeamsReorderRules(context.Background(), testAccountID, []string{"rule-3", "rule-1", "rule-2"})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{
+			"rule-3=3001", "rule-1=3002", "rule-2=3003",
+			"rule-3=1000", "rule-1=2000", "rule-2=3000",
+		}, updates)
+		assert.Equal(t, []TeamsRule{
+			{ID: "rule-3", Precedence: 1000},
+			{ID: "rule-1", Precedence: 2000},
+			{ID: "rule-2", Precedence: 3000},
+		}, actual)
+	}
+
+	_, err = client.TeamsReorderRules(context.Background(), testAccountID, []string{"rule-3", "rule-3", "rule-1", "rule-2"})
+	assert.EqualError(t, err, "duplicate rule IDs in order: rule-3")
+
+	_, err = client.TeamsReorderRules(context.Background(), testAccountID, []string{"rule-3", "rule-1", "rule-4"})
+	assert.EqualError(t, err, "unknown rule IDs in order: rule-4")
+
+	_, err = client.TeamsReorderRules(context.Background(), testAccountID, []string{"rule-3", "rule-1"})
+	assert.EqualError(t, err, "rule IDs missing from order: rule-2")
+}
+
+func TestTeamsCreateRuleWithSchedule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body map[string]json.RawMessage
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.JSONEq(t, `{"mon":"08:00-12:30,13:30-17:00","fri":"08:00-12:00","time_zone":"Europe/London"}`, string(body["schedule"]))
+
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {
+				"id": "7559a944-3dd7-41bf-b183-360a814a8c36",
+				"name": "block social media",
+				"schedule": {"mon": "08:00-12:30,13:30-17:00", "fri": "08:00-12:00", "time_zone": "Europe/London"}
+			}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	schedule := &TeamsRuleSchedule{
+		Monday:   "08:00-12:30,13:30-17:00",
+		Friday:   "08:00-12:00",
+		TimeZone: "Europe/London",
+	}
+	actual, err := client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "block social media", Schedule: schedule})
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, schedule, actual.Schedule)
+	}
+}
+
+func TestTeamsRuleScheduleValidate(t *testing.T) {
+	setup()
+	defer teardown()
+
+	assert.NoError(t, TeamsRuleSchedule{Monday: "00:00-24:00", Sunday: "09:15-09:45,22:00-23:59"}.Validate())
+
+	assert.EqualError(t, TeamsRuleSchedule{Tuesday: "8:00-12:00"}.Validate(), `invalid schedule for tue: "8:00-12:00" is not a HH:MM-HH:MM time range`)
+	assert.EqualError(t, TeamsRuleSchedule{Wednesday: "08:00-12:00,"}.Validate(), `invalid schedule for wed: "" is not a HH:MM-HH:MM time range`)
+	assert.EqualError(t, TeamsRuleSchedule{Saturday: "17:00-09:00"}.Validate(), `invalid schedule for sat: time range "17:00-09:00" must end after it starts`)
+
+	_, err := client.TeamsUpdateRule(context.Background(), testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36", TeamsRule{Schedule: &TeamsRuleSchedule{Thursday: "25:00-26:00"}})
+	assert.EqualError(t, err, `invalid schedule for thu: "25:00-26:00" is not a HH:MM-HH:MM time range`)
+}
+
+func TestTeamsRulesWithInfo(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "rule-1", "name": "rule1"}],
+			"result_info": {"page": 1, "per_page": 20, "count": 1, "total_count": 1}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, resultInfo, err := client.TeamsRulesWithInfo(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, []TeamsRule{{ID: "rule-1", Name: "rule1"}}, actual)
+		assert.Equal(t, ResultInfo{Page: 1, PerPage: 20, Count: 1, Total: 1}, resultInfo)
+	}
+}
+
+func TestNewTeamsL4OverrideRule(t *testing.T) {
+	rule, err := NewTeamsL4OverrideRule("egress", "192.0.2.10", 8443)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{
+			Name:    "egress",
+			Enabled: true,
+			Action:  L4Override,
+			Filters: []TeamsFilterType{L4Filter},
+			RuleSettings: TeamsRuleSettings{
+				L4Override: &TeamsL4OverrideSettings{IP: "192.0.2.10", Port: 8443},
+			},
+		}, rule)
+	}
+
+	_, err = NewTeamsL4OverrideRule("egress", "2001:db8::10", 443)
+	assert.NoError(t, err)
+
+	_, err = NewTeamsL4OverrideRule("egress", "example.com", 443)
+	assert.EqualError(t, err, `invalid l4 override IP "example.com"`)
+
+	// port 0 keeps the original destination port
+	rule, err = NewTeamsL4OverrideRule("egress", "192.0.2.10", 0)
+	if assert.NoError(t, err) {
+		assert.Equal(t, &TeamsL4OverrideSettings{IP: "192.0.2.10"}, rule.RuleSettings.L4Override)
+	}
+
+	_, err = NewTeamsL4OverrideRule("egress", "192.0.2.10", 65536)
+	assert.EqualError(t, err, "invalid l4 override port 65536: must be between 0 and 65535")
+}
+
+func TestNewTeamsEgressRule(t *testing.T) {
+	egress := TeamsEgressSettings{IPv4: "192.0.2.2", IPv6: "2001:db8::/64", IPv4Fallback: "192.0.2.3"}
+	rule, err := NewTeamsEgressRule("static egress", egress)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsRule{
+			Name:    "static egress",
+			Enabled: true,
+			Action:  Egress,
+			Filters: []TeamsFilterType{EgressFilter},
+			RuleSettings: TeamsRuleSettings{
+				Egress: &egress,
+			},
+		}, rule)
+		assert.NoError(t, validateTeamsRule(rule))
+	}
+
+	_, err = NewTeamsEgressRule("static egress", TeamsEgressSettings{IPv4: "2001:db8::2"})
+	assert.EqualError(t, err, `invalid egress IPv4 address "2001:db8::2"`)
+
+	_, err = NewTeamsEgressRule("static egress", TeamsEgressSettings{IPv6: "2001:db8::2"})
+	assert.EqualError(t, err, `invalid egress IPv6 range "2001:db8::2": must be in CIDR notation`)
+
+	_, err = NewTeamsEgressRule("static egress", TeamsEgressSettings{IPv4Fallback: "192.0.2.3"})
+	assert.EqualError(t, err, "egress settings must set an IPv4 address or an IPv6 range")
+
+	err = validateTeamsRule(TeamsRule{
+		Action:       Block,
+		RuleSettings: TeamsRuleSettings{Egress: &egress},
+	})
+	assert.EqualError(t, err, "egress settings can only be set on rules with the egress action")
+}
+
+func TestTeamsL4OverrideSettingsIPFamily(t *testing.T) {
+	setup()
+	defer teardown()
+
+	family, err := TeamsL4OverrideSettings{IP: "192.0.2.10"}.IPFamily()
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsIPv4, family)
+	}
+
+	family, err = TeamsL4OverrideSettings{IP: "2001:db8::10"}.IPFamily()
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsIPv6, family)
+	}
+
+	_, err = TeamsL4OverrideSettings{IP: ""}.IPFamily()
+	assert.EqualError(t, err, `invalid l4 override IP ""`)
+
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{
+		Name:         "egress",
+		Action:       L4Override,
+		Filters:      []TeamsFilterType{L4Filter},
+		RuleSettings: TeamsRuleSettings{L4Override: &TeamsL4OverrideSettings{IP: "2001:db8::10", Port: -1}},
+	})
+	assert.EqualError(t, err, "invalid l4 override port -1: must be between 0 and 65535")
+}
+
+func TestTeamsRuleCount(t *testing.T) {
+	setup()
+	defer teardown()
+
+	resultInfo := `"result_info": {"page": 1, "per_page": 1, "count": 1, "total_count": 42},`
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			%s
+			"result": [{"id": "rule-1", "name": "rule1"}, {"id": "rule-2", "name": "rule2"}]
+		}`, resultInfo)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	count, err := client.TeamsRuleCount(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 42, count)
+	}
+
+	resultInfo = ""
+	count, err = client.TeamsRuleCount(context.Background(), testAccountID)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, count)
+	}
+}
+
+func TestTeamsCreateRuleDnsOverride(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var body struct {
+			RuleSettings map[string]json.RawMessage `json:"rule_settings"`
+		}
+		if assert.NoError(t, json.NewDecoder(r.Body).Decode(&body)) {
+			assert.JSONEq(t, `["10.0.0.5"]`, string(body.RuleSettings["override_ips"]))
+			assert.NotContains(t, body.RuleSettings, "resolve_dns_through_cloudflare")
+		}
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprintf(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": {"id": "7559a944-3dd7-41bf-b183-360a814a8c36", "name": "rewrite internal", "action": "override"}
+		}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	rule := TeamsRule{
+		Name:    "rewrite internal",
+		Action:  Override,
+		Filters: []TeamsFilterType{DnsFilter},
+		Traffic: `any(dns.domains[*] == "internal.example.com")`,
+		RuleSettings: TeamsRuleSettings{
+			OverrideIPs: []string{"10.0.0.5"},
+		},
+	}
+	_, err := client.TeamsCreateRule(context.Background(), testAccountID, rule)
+	assert.NoError(t, err)
+
+	rule.Filters = []TeamsFilterType{HttpFilter}
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, rule)
+	assert.EqualError(t, err, "dns override and resolve settings can only be set on rules with the dns filter")
+
+	_, err = client.TeamsUpdateRule(context.Background(), testAccountID, "7559a944-3dd7-41bf-b183-360a814a8c36", TeamsRule{
+		Filters:      []TeamsFilterType{L4Filter},
+		RuleSettings: TeamsRuleSettings{ResolveDnsThroughCloudflare: BoolPtr(true)},
+	})
+	assert.Error(t, err)
+}
+
+func TestTeamsCreateRuleWithPrecedenceOptions(t *testing.T) {
+	setup()
+	defer teardown()
+
+	existing := `[
+		{"id": "rule-1", "name": "rule1", "precedence": 1000},
+		{"id": "rule-3", "name": "rule3", "precedence": 3000},
+		{"id": "rule-2", "name": "rule2", "precedence": 2000},
+		{"id": "rule-4", "name": "rule4", "precedence": 3001}
+	]`
+	var created []uint64
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("content-type", "application/json")
+		if r.Method == http.MethodGet {
+			fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": %s}`, existing)
+			return
+		}
+
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var rule TeamsRule
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rule))
+		created = append(created, rule.Precedence)
+		fmt.Fprintf(w, `{"success": true, "errors": [], "messages": [], "result": {"id": "new", "precedence": %d}}`, rule.Precedence)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	for _, opt := range []TeamsRuleOption{
+		WithLowestPrecedence(),
+		WithInsertAfter("rule-1"),
+		WithInsertBefore("rule-1"),
+		WithInsertAfter("rule-4"),
+	} {
+		_, err := client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "new"}, opt)
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, []uint64{4001, 1500, 500, 4001}, created)
+
+	_, err := client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "new"}, WithInsertAfter("rule-3"))
+	assert.EqualError(t, err, "no free precedence between 3000 and 3001, reorder the rules with TeamsReorderRules first")
+
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "new"}, WithInsertBefore("rule-9"))
+	assert.EqualError(t, err, "cannot position rule relative to rule-9: rule not found")
+
+	existing = `[]`
+	created = nil
+	_, err = client.TeamsCreateRule(context.Background(), testAccountID, TeamsRule{Name: "new"}, WithLowestPrecedence())
+	assert.NoError(t, err)
+	assert.Equal(t, []uint64{1000}, created)
+}
+
+func TestTeamsCreateTemporaryRule(t *testing.T) {
+	setup()
+	defer teardown()
+
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method, "Expected method 'POST', got %s", r.Method)
+		var rule TeamsRule
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&rule))
+		if assert.NotNil(t, rule.Expiration) && assert.NotNil(t, rule.Expiration.ExpiresAt) {
+			assert.WithinDuration(t, time.Now().Add(time.Hour), *rule.Expiration.ExpiresAt, time.Minute)
+		}
+		rule.ID = "7559a944-3dd7-41bf-b183-360a814a8c36"
+		w.Header().Set("content-type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(TeamsRuleResponse{Response: Response{Success: true}, Result: rule}))
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", handler)
+
+	actual, err := client.TeamsCreateTemporaryRule(context.Background(), testAccountID, TeamsRule{
+		Name:    "incident block",
+		Enabled: true,
+		Action:  Block,
+		Filters: []TeamsFilterType{DnsFilter},
+		Traffic: `dns.fqdn == "evil.example.com"`,
+	}, time.Hour)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, "7559a944-3dd7-41bf-b183-360a814a8c36", actual.ID)
+		assert.NotNil(t, actual.Expiration)
+	}
+
+	_, err = client.TeamsCreateTemporaryRule(context.Background(), testAccountID, TeamsRule{Name: "no ttl"}, 0)
+	assert.EqualError(t, err, "invalid rule time to live 0s: must be positive")
+}
+
+func TestTeamsRuleExpirationValidate(t *testing.T) {
+	past := time.Now().Add(-time.Hour).UTC()
+	future := time.Now().Add(time.Hour)
+
+	assert.NoError(t, TeamsRuleExpiration{ExpiresAt: &future}.Validate())
+	assert.NoError(t, TeamsRuleExpiration{Duration: 30}.Validate())
+	assert.NoError(t, TeamsRuleExpiration{ExpiresAt: &past, Expired: true}.Validate())
+	assert.EqualError(t, TeamsRuleExpiration{ExpiresAt: &past}.Validate(), "invalid rule expiration "+past.Format(time.RFC3339)+": must be in the future")
+	assert.EqualError(t, TeamsRuleExpiration{Duration: -1}.Validate(), "invalid rule expiration duration -1: must not be negative")
+}
+
+func TestTeamsSetRulesEnabledWhere(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "rule-1", "name": "emergency: block 1", "enabled": false},
+				{"id": "rule-2", "name": "emergency: block 2", "enabled": true},
+				{"id": "rule-3", "name": "allow all", "enabled": false},
+				{"id": "rule-4", "name": "emergency: block 4", "enabled": false}
+			]
+		}`)
+	})
+
+	var patched []string
+	patchHandler := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method, "Expected method 'PATCH', got %s", r.Method)
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.JSONEq(t, `{"enabled": true}`, string(body))
+		w.Header().Set("content-type", "application/json")
+		if r.URL.Path == "/accounts/"+testAccountID+"/gateway/rules/rule-4" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"success": false, "errors": [{"code": 1000, "message": "invalid rule"}], "messages": [], "result": null}`)
+			return
+		}
+		patched = append(patched, r.URL.Path)
+		fmt.Fprint(w, `{"success": true, "errors": [], "messages": [], "result": {"enabled": true}}`)
+	}
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/rule-1", patchHandler)
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules/rule-4", patchHandler)
+
+	changed, err := client.TeamsSetRulesEnabledWhere(context.Background(), testAccountID, func(rule TeamsRule) bool {
+		return strings.HasPrefix(rule.Name, "emergency:")
+	}, true)
+
+	assert.Equal(t, []string{"rule-1"}, changed)
+	assert.Len(t, patched, 1)
+	var enabledErr *TeamsSetRulesEnabledError
+	if assert.True(t, errors.As(err, &enabledErr)) {
+		assert.Len(t, enabledErr.Errors, 1)
+		assert.Contains(t, err.Error(), "updating rule emergency: block 4 (rule-4)")
+	}
+}
@@ -0,0 +1,51 @@
+package cloudflare
+
+import (
+	"context"
+)
+
+// TeamsUsage is a rollup of the Gateway usage of an account.
+//
+// DNSQueries is always 0: query volumes are only available from the
+// analytics GraphQL API, which this library doesn't cover. IsolationSeats is
+// always 0 as well since the API doesn't report browser isolation seat
+// usage. Seat and rule limits aren't exposed by the API either.
+type TeamsUsage struct {
+	// Rules is the number of Gateway rules.
+	Rules int
+	// ActiveSeats is the number of users holding an Access or a Gateway
+	// seat.
+	ActiveSeats int
+	// GatewaySeats is the number of users holding a Gateway seat.
+	GatewaySeats int
+	// IsolationSeats is the number of browser isolation seats in use.
+	IsolationSeats int
+	// DNSQueries is the number of DNS queries resolved by Gateway.
+	DNSQueries int64
+}
+
+// TeamsAccountUsage returns the Gateway usage of an account, gathered from
+// the rules and the Zero Trust users of the account.
+func (api *API) TeamsAccountUsage(ctx context.Context, accountID string) (TeamsUsage, error) {
+	rules, err := api.TeamsRuleCount(ctx, accountID)
+	if err != nil {
+		return TeamsUsage{}, err
+	}
+
+	users, err := api.teamsSeatUsers(ctx, accountID)
+	if err != nil {
+		return TeamsUsage{}, err
+	}
+
+	usage := TeamsUsage{Rules: rules}
+	for _, user := range users {
+		if user.AccessSeat || user.GatewaySeat {
+			usage.ActiveSeats++
+		}
+		if user.GatewaySeat {
+			usage.GatewaySeats++
+		}
+	}
+
+	return usage, nil
+}
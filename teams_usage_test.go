@@ -0,0 +1,49 @@
+package cloudflare
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamsAccountUsage(t *testing.T) {
+	setup()
+	defer teardown()
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/gateway/rules", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [{"id": "1"}, {"id": "2"}],
+			"result_info": {"page": 1, "per_page": 2, "total_pages": 3, "count": 2, "total_count": 5}
+		}`)
+	})
+
+	mux.HandleFunc("/accounts/"+testAccountID+"/access/users", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method, "Expected method 'GET', got %s", r.Method)
+		w.Header().Set("content-type", "application/json")
+		fmt.Fprint(w, `{
+			"success": true,
+			"errors": [],
+			"messages": [],
+			"result": [
+				{"id": "1", "email": "a@example.com", "gateway_seat": true, "access_seat": true},
+				{"id": "2", "email": "b@example.com", "gateway_seat": false, "access_seat": true},
+				{"id": "3", "email": "c@example.com", "gateway_seat": false, "access_seat": false}
+			],
+			"result_info": {"page": 1, "per_page": 100, "total_pages": 1, "count": 3, "total_count": 3}
+		}`)
+	})
+
+	usage, err := client.TeamsAccountUsage(context.Background(), testAccountID)
+
+	if assert.NoError(t, err) {
+		assert.Equal(t, TeamsUsage{Rules: 5, ActiveSeats: 2, GatewaySeats: 1}, usage)
+	}
+}